@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/consumer"
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+// Usage of the dead letter subcommand.
+const deadLettersUsage = `usage: zeevision dead-letters <command>
+
+commands:
+  list          list stored dead letters
+  retry <id>    retry applying a dead letter
+  retry-all     retry applying every dead letter
+  discard <id>  discard a dead letter without applying it`
+
+// Run the dead letter subcommand with the given arguments.
+func runDeadLetters(args []string, fetcher *storage.Fetcher, deadLetters *consumer.DeadLetterQueue) error {
+	if len(args) == 0 {
+		return fmt.Errorf(deadLettersUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		return listDeadLetters(fetcher)
+	case "retry":
+		id, err := parseDeadLetterID(args[1:])
+		if err != nil {
+			return err
+		}
+		applied, err := deadLetters.Retry(id)
+		if err != nil {
+			return fmt.Errorf("failed to retry dead letter: %w", err)
+		}
+		if applied {
+			fmt.Printf("Dead letter %d applied\n", id)
+		} else {
+			fmt.Printf("Dead letter %d failed to apply again\n", id)
+		}
+	case "retry-all":
+		// Manual retries aren't limited by the number of attempts
+		applied, err := deadLetters.RetryAll(math.MaxInt64)
+		if err != nil {
+			return fmt.Errorf("failed to retry dead letters: %w", err)
+		}
+		fmt.Printf("%d dead letters applied\n", applied)
+	case "discard":
+		id, err := parseDeadLetterID(args[1:])
+		if err != nil {
+			return err
+		}
+		err = deadLetters.Discard(id)
+		if err != nil {
+			return fmt.Errorf("failed to discard dead letter: %w", err)
+		}
+		fmt.Printf("Dead letter %d discarded\n", id)
+	default:
+		return fmt.Errorf(deadLettersUsage)
+	}
+
+	return nil
+}
+
+func parseDeadLetterID(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf(deadLettersUsage)
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid dead letter id %q: %w", args[0], err)
+	}
+
+	return id, nil
+}
+
+func listDeadLetters(fetcher *storage.Fetcher) error {
+	deadLetters, err := fetcher.GetDeadLetters(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dead letters: %w", err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tTOPIC\tPARTITION\tOFFSET\tATTEMPTS\tTIME\tERROR")
+	for _, deadLetter := range deadLetters.Items {
+		fmt.Fprintf(writer, "%d\t%s\t%d\t%d\t%d\t%s\t%s\n",
+			deadLetter.ID,
+			deadLetter.Topic,
+			deadLetter.Partition,
+			deadLetter.Offset,
+			deadLetter.Attempts,
+			deadLetter.Time.Format("2006-01-02 15:04:05"),
+			deadLetter.Error,
+		)
+	}
+
+	return writer.Flush()
+}
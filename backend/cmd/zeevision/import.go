@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/consumer"
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+// Usage of the import subcommand.
+const importUsage = `usage: zeevision import <file>...
+
+Apply the Zeebe records in the given files, one JSON encoded record per line.
+Files can be gzip compressed.`
+
+// Run the import subcommand with the given arguments.
+func runImport(args []string, storer storage.Storer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(importUsage)
+	}
+
+	fileConsumer, err := consumer.NewFromSource(storer, consumer.NewFileSource(args...))
+	if err != nil {
+		return fmt.Errorf("failed to import records: %w", err)
+	}
+
+	<-fileConsumer.Done()
+	log.Printf("Imported %d files", len(args))
+
+	return fileConsumer.Close()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"github.com/ducanhpham0312/zeevision/backend/internal/consumer"
@@ -42,13 +43,27 @@ func main() {
 	// Create fetcher for fetching data from database.
 	fetcher := storage.NewFetcher(db)
 
-	// Get Kafka address from environment variable.
-	kafkaAddr := environment.KafkaAddress()
+	storer := storage.NewStorer(db)
+	deadLetters := consumer.NewDeadLetterQueue(storer)
+
+	// Manage dead letters from the command line instead of running normally
+	if len(os.Args) > 1 && os.Args[1] == "dead-letters" {
+		if err := runDeadLetters(os.Args[2:], fetcher, deadLetters); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Import records from files instead of consuming them from Kafka
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(os.Args[2:], storer); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	// Launch goroutine for consuming from specified topic and partition
-	storer := storage.NewStorer(db)
-	brokers := []string{kafkaAddr}
-	kafkaConsumer, err := consumer.NewConsumer(storer, brokers, ConsumerRetries, ConsumerRetryDelay)
+	kafkaConsumer, err := consumer.NewFromEnv(storer, ConsumerRetries, ConsumerRetryDelay)
 	if err != nil {
 		panic(err)
 	}
@@ -56,7 +71,19 @@ func main() {
 	// need to be closed manually
 	defer kafkaConsumer.Close()
 
-	server, err := endpoint.NewFromEnv(fetcher)
+	// When only backfilling there's no need to serve anything
+	if consumer.BackfillMode(environment.Backfill()) == consumer.BackfillExit {
+		<-kafkaConsumer.Done()
+		log.Printf("Backfill done, exiting")
+		return
+	}
+
+	server, err := endpoint.NewFromEnv(
+		fetcher,
+		kafkaConsumer.DeadLetters(),
+		consumer.NewPendingRecordQueue(storer),
+		consumer.NewIngester(storer),
+	)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -27,15 +27,24 @@ func FromStorageBpmnResource(bpmnResource storage.BpmnResource) string {
 
 // Convert storage instance to GraphQL instance.
 func FromStorageInstance(instance storage.Instance) *Instance {
+	// Zeebe uses -1 for instances not started by a call activity
+	var parentInstanceKey, parentElementInstanceKey *int64
+	if instance.ParentProcessInstanceKey > 0 {
+		parentInstanceKey = &instance.ParentProcessInstanceKey
+		parentElementInstanceKey = &instance.ParentElementInstanceKey
+	}
+
 	return &Instance{
-		StartTime:   formatTime(instance.StartTime),
-		EndTime:     formatNullTime(instance.EndTime),
-		InstanceKey: instance.ProcessInstanceKey,
-		ProcessKey:  instance.ProcessDefinitionKey,
-		Version:     instance.Version,
-		Status:      instance.Status,
-		// Variables and Process have their own resolvers and are not populated
-		// here.
+		StartTime:                formatTime(instance.StartTime),
+		EndTime:                  formatNullTime(instance.EndTime),
+		InstanceKey:              instance.ProcessInstanceKey,
+		ProcessKey:               instance.ProcessDefinitionKey,
+		Version:                  instance.Version,
+		Status:                   instance.Status,
+		ParentInstanceKey:        parentInstanceKey,
+		ParentElementInstanceKey: parentElementInstanceKey,
+		// Variables, Process and the related instances have their own
+		// resolvers and are not populated here.
 	}
 }
 
@@ -51,6 +60,108 @@ func FromStorageProcess(process storage.Process) *Process {
 	}
 }
 
+// Convert storage deployment to GraphQL deployment.
+func FromStorageDeployment(deployment storage.Deployment) *Deployment {
+	return &Deployment{
+		DeploymentKey: deployment.DeploymentKey,
+		Time:          formatTime(deployment.Time),
+		// Resources and Definitions are populated by their own resolvers.
+	}
+}
+
+// Convert storage deployment resource to GraphQL deployment resource.
+func FromStorageDeploymentResource(resource storage.DeploymentResource) *DeploymentResource {
+	return &DeploymentResource{
+		ResourceName: resource.ResourceName,
+		ResourceType: resource.ResourceType,
+		Size:         resource.Size,
+	}
+}
+
+// Convert storage deployed definition to GraphQL deployed definition.
+func FromStorageDeployedDefinition(definition storage.DeployedDefinition) *DeployedDefinition {
+	return &DeployedDefinition{
+		DefinitionKey: definition.DefinitionKey,
+		Kind:          definition.Kind,
+		DefinitionID:  definition.DefinitionID,
+		Version:       definition.Version,
+		ResourceName:  definition.ResourceName,
+		Duplicate:     definition.Duplicate,
+	}
+}
+
+// Convert storage DMN resource to GraphQL DMN resource.
+func FromStorageDmnResource(dmnResource storage.DmnResource) string {
+	return dmnResource.DmnFile
+}
+
+// Convert storage decision requirements to GraphQL decision requirements.
+func FromStorageDecisionRequirements(decisionRequirements storage.DecisionRequirements) *DecisionRequirements {
+	return &DecisionRequirements{
+		DecisionRequirementsKey: decisionRequirements.DecisionRequirementsKey,
+		DecisionRequirementsID:  decisionRequirements.DecisionRequirementsID,
+		Name:                    decisionRequirements.Name,
+		Version:                 decisionRequirements.Version,
+		Namespace:               decisionRequirements.Namespace,
+		ResourceName:            decisionRequirements.ResourceName,
+		DeploymentTime:          formatTime(decisionRequirements.DeploymentTime),
+		// DmnResource and Decisions have their own resolvers and are not
+		// populated here.
+	}
+}
+
+// Convert storage decision to GraphQL decision.
+func FromStorageDecision(decision storage.Decision) *Decision {
+	return &Decision{
+		DecisionKey:             decision.DecisionKey,
+		DecisionID:              decision.DecisionID,
+		Name:                    decision.Name,
+		Version:                 decision.Version,
+		DecisionRequirementsKey: decision.DecisionRequirementsKey,
+		DecisionRequirementsID:  decision.DecisionRequirementsID,
+		DeploymentTime:          formatTime(decision.DeploymentTime),
+		// DecisionRequirements and Evaluations are populated by their own
+		// resolvers.
+	}
+}
+
+// Convert storage decision evaluation to GraphQL decision evaluation.
+func FromStorageDecisionEvaluation(evaluation storage.DecisionEvaluation) *DecisionEvaluation {
+	return &DecisionEvaluation{
+		Key:                     evaluation.Key,
+		DecisionKey:             evaluation.DecisionKey,
+		DecisionID:              evaluation.DecisionID,
+		DecisionName:            evaluation.DecisionName,
+		DecisionVersion:         evaluation.DecisionVersion,
+		DecisionRequirementsKey: evaluation.DecisionRequirementsKey,
+		Output:                  evaluation.Output,
+		InstanceKey:             evaluation.ProcessInstanceKey,
+		ProcessKey:              evaluation.ProcessDefinitionKey,
+		ElementInstanceKey:      evaluation.ElementInstanceKey,
+		ElementID:               evaluation.ElementID,
+		State:                   evaluation.State,
+		FailureMessage:          evaluation.FailureMessage,
+		FailedDecisionID:        evaluation.FailedDecisionID,
+		Time:                    formatTime(evaluation.Time),
+		// Instance and EvaluatedDecisions are populated by their own
+		// resolvers.
+	}
+}
+
+// Convert storage evaluated decision to GraphQL evaluated decision.
+func FromStorageEvaluatedDecision(decision storage.EvaluatedDecision) *EvaluatedDecision {
+	return &EvaluatedDecision{
+		DecisionKey:     decision.DecisionKey,
+		DecisionID:      decision.DecisionID,
+		DecisionName:    decision.DecisionName,
+		DecisionVersion: decision.DecisionVersion,
+		DecisionType:    decision.DecisionType,
+		Output:          decision.Output,
+		Inputs:          decision.Inputs,
+		MatchedRules:    decision.MatchedRules,
+	}
+}
+
 // Convert storage audit log to GraphQL audit log.
 func FromStorageAuditLog(auditLog storage.AuditLog) *AuditLog {
 	return &AuditLog{
@@ -62,6 +173,20 @@ func FromStorageAuditLog(auditLog storage.AuditLog) *AuditLog {
 	}
 }
 
+// Convert storage element instance to GraphQL element instance.
+func FromStorageElementInstance(elementInstance storage.ElementInstance) *ElementInstance {
+	return &ElementInstance{
+		Key:          elementInstance.Key,
+		InstanceKey:  elementInstance.ProcessInstanceKey,
+		ElementID:    elementInstance.ElementID,
+		ElementType:  elementInstance.ElementType,
+		FlowScopeKey: elementInstance.FlowScopeKey,
+		State:        elementInstance.State,
+		StartTime:    formatTime(elementInstance.StartTime),
+		EndTime:      formatNullTime(elementInstance.EndTime),
+	}
+}
+
 // Convert storage incident to GraphQL incident.
 func FromStorageIncident(incident storage.Incident) *Incident {
 	return &Incident{
@@ -79,23 +204,248 @@ func FromStorageIncident(incident storage.Incident) *Incident {
 // Convert storage job to GraphQL job.
 func FromStorageJob(job storage.Job) *Job {
 	return &Job{
-		ElementID:   job.ElementID,
-		InstanceKey: job.ProcessInstanceKey,
-		Key:         job.Key,
-		Type:        job.Type,
-		Retries:     job.Retries,
-		Worker:      job.Worker,
-		State:       job.State,
-		Time:        formatTime(job.Time),
+		ElementID:          job.ElementID,
+		ElementInstanceKey: job.ElementInstanceKey,
+		InstanceKey:        job.ProcessInstanceKey,
+		Key:                job.Key,
+		Type:               job.Type,
+		Retries:            job.Retries,
+		Worker:             job.Worker,
+		State:              job.State,
+		ErrorMessage:       job.ErrorMessage,
+		ErrorCode:          job.ErrorCode,
+		Deadline:           formatNullTime(job.Deadline),
+		RetryBackoff:       job.RetryBackoff,
+		CustomHeaders:      job.CustomHeaders,
+		Time:               formatTime(job.Time),
+		// Instance and History are populated by their own resolvers.
+	}
+}
+
+// Convert storage job event to GraphQL job event.
+func FromStorageJobEvent(event storage.JobEvent) *JobEvent {
+	return &JobEvent{
+		Position:     event.Position,
+		Intent:       event.Intent,
+		Retries:      event.Retries,
+		Worker:       event.Worker,
+		ErrorMessage: event.ErrorMessage,
+		ErrorCode:    event.ErrorCode,
+		RetryBackoff: event.RetryBackoff,
+		Time:         formatTime(event.Time),
+	}
+}
+
+// Convert storage rejection to GraphQL rejection.
+func FromStorageRejection(rejection storage.Rejection) *Rejection {
+	return &Rejection{
+		Position:        rejection.Position,
+		Key:             rejection.Key,
+		InstanceKey:     rejection.ProcessInstanceKey,
+		ElementID:       rejection.ElementID,
+		ValueType:       rejection.ValueType,
+		Intent:          rejection.Intent,
+		RejectionType:   rejection.RejectionType,
+		RejectionReason: rejection.RejectionReason,
+		Time:            formatTime(rejection.Time),
+		// Instance is populated by the Instance resolver.
+	}
+}
+
+// Convert storage message to GraphQL message.
+func FromStorageMessage(message storage.Message) *Message {
+	return &Message{
+		Key:            message.Key,
+		Name:           message.Name,
+		CorrelationKey: message.CorrelationKey,
+		MessageID:      message.MessageID,
+		TimeToLive:     message.TimeToLive,
+		Variables:      message.Variables,
+		State:          message.State,
+		PublishTime:    formatTime(message.PublishTime),
+		Time:           formatTime(message.Time),
+		// Subscriptions have their own resolver and are not populated
+		// here.
+	}
+}
+
+// Convert storage message subscription to GraphQL message subscription.
+func FromStorageMessageSubscription(subscription storage.MessageSubscription) *MessageSubscription {
+	var messageKey *int64
+	if subscription.MessageKey != 0 {
+		messageKey = &subscription.MessageKey
+	}
+
+	return &MessageSubscription{
+		InstanceKey:        subscription.ProcessInstanceKey,
+		ElementInstanceKey: subscription.ElementInstanceKey,
+		ElementID:          subscription.ElementID,
+		MessageName:        subscription.MessageName,
+		CorrelationKey:     subscription.CorrelationKey,
+		MessageKey:         messageKey,
+		Interrupting:       subscription.Interrupting,
+		State:              subscription.State,
+		Time:               formatTime(subscription.Time),
+		// Instance and Message are populated by their own resolvers.
+	}
+}
+
+// Convert storage timer to GraphQL timer.
+func FromStorageTimer(timer storage.Timer) *Timer {
+	return &Timer{
+		Key:                timer.Key,
+		InstanceKey:        timer.ProcessInstanceKey,
+		ProcessKey:         timer.ProcessDefinitionKey,
+		ElementInstanceKey: timer.ElementInstanceKey,
+		TargetElementID:    timer.TargetElementID,
+		DueDate:            formatTime(timer.DueDate),
+		Repetitions:        timer.Repetitions,
+		State:              timer.State,
+		Time:               formatTime(timer.Time),
+		// Instance is populated by the Instance resolver.
+	}
+}
+
+// Convert storage user task to GraphQL user task.
+func FromStorageUserTask(userTask storage.UserTask) *UserTask {
+	var formKey *int64
+	if userTask.FormKey > 0 {
+		formKey = &userTask.FormKey
+	}
+
+	return &UserTask{
+		Key:                userTask.Key,
+		InstanceKey:        userTask.ProcessInstanceKey,
+		ProcessKey:         userTask.ProcessDefinitionKey,
+		ElementInstanceKey: userTask.ElementInstanceKey,
+		ElementID:          userTask.ElementID,
+		Assignee:           userTask.Assignee,
+		CandidateGroups:    userTask.CandidateGroups,
+		CandidateUsers:     userTask.CandidateUsers,
+		DueDate:            formatNullTime(userTask.DueDate),
+		FollowUpDate:       formatNullTime(userTask.FollowUpDate),
+		FormKey:            formKey,
+		State:              userTask.State,
+		CreationTime:       formatTime(userTask.CreationTime),
+		EndTime:            formatNullTime(userTask.EndTime),
+		Time:               formatTime(userTask.Time),
+		// Instance is populated by its own resolver.
+	}
+}
+
+// Convert storage signal to GraphQL signal.
+func FromStorageSignal(signal storage.Signal) *Signal {
+	return &Signal{
+		Key:       signal.Key,
+		Name:      signal.Name,
+		Variables: signal.Variables,
+		Time:      formatTime(signal.Time),
+		// Subscriptions are populated by their own resolver.
+	}
+}
+
+// Convert storage signal subscription to GraphQL signal subscription.
+func FromStorageSignalSubscription(subscription storage.SignalSubscription) *SignalSubscription {
+	return &SignalSubscription{
+		Key:                   subscription.Key,
+		SignalName:            subscription.SignalName,
+		ProcessKey:            subscription.ProcessDefinitionKey,
+		BpmnProcessID:         subscription.BpmnProcessID,
+		CatchEventID:          subscription.CatchEventID,
+		CatchEventInstanceKey: subscription.CatchEventInstanceKey,
+		State:                 subscription.State,
+		Time:                  formatTime(subscription.Time),
+		// Process is populated by its own resolver.
+	}
+}
+
+// Convert storage broker error to GraphQL broker error.
+func FromStorageBrokerError(brokerError storage.BrokerError) *BrokerError {
+	return &BrokerError{
+		Position:           brokerError.Position,
+		ErrorEventPosition: brokerError.ErrorEventPosition,
+		InstanceKey:        brokerError.ProcessInstanceKey,
+		ExceptionMessage:   brokerError.ExceptionMessage,
+		Stacktrace:         brokerError.Stacktrace,
+		Time:               formatTime(brokerError.Time),
+		// Instance is populated by the Instance resolver.
 	}
 }
 
 // Convert storage variable to GraphQL variable.
 func FromStorageVariable(variable storage.Variable) *Variable {
 	return &Variable{
-		Name:  variable.Name,
-		Value: variable.Value,
-		Time:  formatTime(variable.Time),
+		Name:        variable.Name,
+		Value:       variable.Value,
+		Time:        formatTime(variable.Time),
+		InstanceKey: variable.ProcessInstanceKey,
+		ScopeKey:    variable.ScopeKey,
+		Global:      variable.ScopeKey == variable.ProcessInstanceKey,
+		// ElementInstance is populated by its own resolver.
+	}
+}
+
+// Convert storage variable change to GraphQL variable change.
+func FromStorageVariableChange(change storage.VariableChange) *VariableChange {
+	return &VariableChange{
+		Position:    change.Position,
+		InstanceKey: change.ProcessInstanceKey,
+		ScopeKey:    change.ScopeKey,
+		Name:        change.Name,
+		Intent:      change.Intent,
+		Value:       change.Value,
+		Time:        formatTime(change.Time),
+	}
+}
+
+// Convert storage worker to GraphQL worker.
+func FromStorageWorker(worker storage.Worker) *Worker {
+	var completionRate, failureRate *float64
+	if worker.ActivatedJobs > 0 {
+		completed := float64(worker.CompletedJobs) / float64(worker.ActivatedJobs)
+		failed := float64(worker.FailedJobs) / float64(worker.ActivatedJobs)
+		completionRate = &completed
+		failureRate = &failed
+	}
+
+	return &Worker{
+		Name:           worker.Name,
+		LastSeen:       formatTime(worker.LastSeen),
+		Polls:          worker.Polls,
+		Activations:    worker.Activations,
+		ActivatedJobs:  worker.ActivatedJobs,
+		CompletedJobs:  worker.CompletedJobs,
+		FailedJobs:     worker.FailedJobs,
+		CompletionRate: completionRate,
+		FailureRate:    failureRate,
+		// JobTypes is populated by its own resolver.
+	}
+}
+
+// Convert storage dead letter to GraphQL dead letter.
+func FromStorageDeadLetter(deadLetter storage.DeadLetter) *DeadLetter {
+	return &DeadLetter{
+		ID:        deadLetter.ID,
+		Topic:     deadLetter.Topic,
+		Partition: int64(deadLetter.Partition),
+		Offset:    deadLetter.Offset,
+		Payload:   string(deadLetter.Payload),
+		Error:     deadLetter.Error,
+		Attempts:  deadLetter.Attempts,
+		Time:      formatTime(deadLetter.Time),
+	}
+}
+
+// Convert storage pending record to GraphQL pending record.
+func FromStoragePendingRecord(pendingRecord storage.PendingRecord) *PendingRecord {
+	return &PendingRecord{
+		ID:         pendingRecord.ID,
+		Topic:      pendingRecord.Topic,
+		Partition:  int64(pendingRecord.Partition),
+		Offset:     pendingRecord.Offset,
+		Payload:    string(pendingRecord.Payload),
+		MissingKey: pendingRecord.MissingKey,
+		Time:       formatTime(pendingRecord.Time),
 	}
 }
 
@@ -111,6 +461,39 @@ func VariableFilterToStorageFilter(filter *VariableFilter) *storage.Filter {
 	}
 }
 
+// Convert GraphQL rejection filter to storage filter. Nil value is preserved.
+func RejectionFilterToStorageFilter(filter *RejectionFilter) *storage.Filter {
+	if filter == nil {
+		return nil
+	}
+	return &storage.Filter{
+		Input: filter.RejectionType,
+		Type:  storage.FilterType(filter.Type.String()),
+	}
+}
+
+// Convert GraphQL message filter to storage filter. Nil value is preserved.
+func MessageFilterToStorageFilter(filter *MessageFilter) *storage.Filter {
+	if filter == nil {
+		return nil
+	}
+	return &storage.Filter{
+		Input: filter.Name,
+		Type:  storage.FilterType(filter.Type.String()),
+	}
+}
+
+// Convert GraphQL user task filter to storage filter. Nil value is preserved.
+func UserTaskFilterToStorageFilter(filter *UserTaskFilter) *storage.Filter {
+	if filter == nil {
+		return nil
+	}
+	return &storage.Filter{
+		Input: filter.Assignee,
+		Type:  storage.FilterType(filter.Type.String()),
+	}
+}
+
 // Convert GraphQL pagination to storage pagination. Nil value is preserved.
 func ToStoragePagination(pagination *Pagination) *storage.Pagination {
 	if pagination == nil {
@@ -34,6 +34,8 @@ func TestFromStorageBpmnResource(t *testing.T) {
 func TestFromStorageInstance(t *testing.T) {
 	now := time.Now()
 	nowFormatted := now.UTC().Format(RFC3339Milli)
+	parentInstanceKey := int64(30)
+	parentElementInstanceKey := int64(35)
 
 	tests := []struct {
 		name            string
@@ -78,6 +80,46 @@ func TestFromStorageInstance(t *testing.T) {
 				Status:      "COMPLETED",
 			},
 		},
+		{
+			name: "Instance without parent",
+			storageInstance: storage.Instance{
+				ProcessInstanceKey:       30,
+				ProcessDefinitionKey:     3,
+				Version:                  1,
+				Status:                   "ACTIVE",
+				StartTime:                now,
+				ParentProcessInstanceKey: -1,
+				ParentElementInstanceKey: -1,
+			},
+			expected: &Instance{
+				StartTime:   nowFormatted,
+				InstanceKey: 30,
+				ProcessKey:  3,
+				Version:     1,
+				Status:      "ACTIVE",
+			},
+		},
+		{
+			name: "Child instance",
+			storageInstance: storage.Instance{
+				ProcessInstanceKey:       40,
+				ProcessDefinitionKey:     4,
+				Version:                  1,
+				Status:                   "ACTIVE",
+				StartTime:                now,
+				ParentProcessInstanceKey: 30,
+				ParentElementInstanceKey: 35,
+			},
+			expected: &Instance{
+				StartTime:                nowFormatted,
+				InstanceKey:              40,
+				ProcessKey:               4,
+				Version:                  1,
+				Status:                   "ACTIVE",
+				ParentInstanceKey:        &parentInstanceKey,
+				ParentElementInstanceKey: &parentElementInstanceKey,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -161,6 +203,101 @@ func TestFromStorageProcess(t *testing.T) {
 	}
 }
 
+func TestFromStorageDeployedDefinition(t *testing.T) {
+	storageDefinition := storage.DeployedDefinition{
+		DeploymentKey: 1,
+		DefinitionKey: 10,
+		Kind:          "PROCESS",
+		DefinitionID:  "process-id",
+		Version:       3,
+		ResourceName:  "process.bpmn",
+		Duplicate:     true,
+	}
+	expected := &DeployedDefinition{
+		DefinitionKey: 10,
+		Kind:          "PROCESS",
+		DefinitionID:  "process-id",
+		Version:       3,
+		ResourceName:  "process.bpmn",
+		Duplicate:     true,
+	}
+
+	actual := FromStorageDeployedDefinition(storageDefinition)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageDecision(t *testing.T) {
+	now := time.Now()
+
+	storageDecision := storage.Decision{
+		DecisionKey:             10,
+		DecisionID:              "decision-id",
+		Name:                    "decision-name",
+		Version:                 2,
+		DecisionRequirementsKey: 20,
+		DecisionRequirementsID:  "drg-id",
+		DeploymentTime:          now,
+		Position:                5,
+	}
+	expected := &Decision{
+		DecisionKey:             10,
+		DecisionID:              "decision-id",
+		Name:                    "decision-name",
+		Version:                 2,
+		DecisionRequirementsKey: 20,
+		DecisionRequirementsID:  "drg-id",
+		DeploymentTime:          now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageDecision(storageDecision)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageDecisionEvaluation(t *testing.T) {
+	now := time.Now()
+
+	storageEvaluation := storage.DecisionEvaluation{
+		Key:                     30,
+		DecisionKey:             10,
+		DecisionID:              "decision-id",
+		DecisionName:            "decision-name",
+		DecisionVersion:         2,
+		DecisionRequirementsKey: 20,
+		Output:                  `"approved"`,
+		ProcessInstanceKey:      100,
+		ProcessDefinitionKey:    200,
+		ElementInstanceKey:      300,
+		ElementID:               "element-id",
+		State:                   "FAILED",
+		FailureMessage:          "failure-message",
+		FailedDecisionID:        "decision-id",
+		Time:                    now,
+	}
+	expected := &DecisionEvaluation{
+		Key:                     30,
+		DecisionKey:             10,
+		DecisionID:              "decision-id",
+		DecisionName:            "decision-name",
+		DecisionVersion:         2,
+		DecisionRequirementsKey: 20,
+		Output:                  `"approved"`,
+		InstanceKey:             100,
+		ProcessKey:              200,
+		ElementInstanceKey:      300,
+		ElementID:               "element-id",
+		State:                   "FAILED",
+		FailureMessage:          "failure-message",
+		FailedDecisionID:        "decision-id",
+		Time:                    now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageDecisionEvaluation(storageEvaluation)
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestFromStorageAuditLog(t *testing.T) {
 	now := time.Now()
 
@@ -185,6 +322,37 @@ func TestFromStorageAuditLog(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFromStorageElementInstance(t *testing.T) {
+	now := time.Now()
+	nowFormatted := now.UTC().Format(RFC3339Milli)
+
+	storageElementInstance := storage.ElementInstance{
+		Key:                11,
+		ProcessInstanceKey: 10,
+		ElementID:          "element-id",
+		ElementType:        "SERVICE_TASK",
+		FlowScopeKey:       10,
+		State:              "ELEMENT_COMPLETED",
+		StartTime:          now,
+		EndTime:            sql.NullTime{Time: now, Valid: true},
+		Position:           5,
+	}
+	expected := &ElementInstance{
+		Key:          11,
+		InstanceKey:  10,
+		ElementID:    "element-id",
+		ElementType:  "SERVICE_TASK",
+		FlowScopeKey: 10,
+		State:        "ELEMENT_COMPLETED",
+		StartTime:    nowFormatted,
+		EndTime:      &nowFormatted,
+	}
+
+	actual := FromStorageElementInstance(storageElementInstance)
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestFromStrorageIncident(t *testing.T) {
 	now := time.Now()
 
@@ -214,26 +382,39 @@ func TestFromStrorageIncident(t *testing.T) {
 
 func TestFromStorageJob(t *testing.T) {
 	now := time.Now()
+	nowFormatted := now.UTC().Format(RFC3339Milli)
 
 	storageJob := storage.Job{
 		ElementID:          "element-id",
+		ElementInstanceKey: 50,
 		Key:                10,
 		Type:               "type",
 		Retries:            3,
 		Worker:             "worker",
 		State:              "state",
+		ErrorMessage:       "error-message",
+		ErrorCode:          "error-code",
+		Deadline:           sql.NullTime{Time: now, Valid: true},
+		RetryBackoff:       1000,
+		CustomHeaders:      `{"header":"value"}`,
 		Time:               now,
 		ProcessInstanceKey: 100,
 	}
 	expected := &Job{
-		ElementID:   "element-id",
-		Key:         10,
-		Type:        "type",
-		Retries:     3,
-		Worker:      "worker",
-		State:       "state",
-		Time:        now.UTC().Format(RFC3339Milli),
-		InstanceKey: 100,
+		ElementID:          "element-id",
+		ElementInstanceKey: 50,
+		Key:                10,
+		Type:               "type",
+		Retries:            3,
+		Worker:             "worker",
+		State:              "state",
+		ErrorMessage:       "error-message",
+		ErrorCode:          "error-code",
+		Deadline:           &nowFormatted,
+		RetryBackoff:       1000,
+		CustomHeaders:      `{"header":"value"}`,
+		Time:               nowFormatted,
+		InstanceKey:        100,
 	}
 
 	actual := FromStorageJob(storageJob)
@@ -241,22 +422,494 @@ func TestFromStorageJob(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFromStorageJobEvent(t *testing.T) {
+	now := time.Now()
+
+	storageEvent := storage.JobEvent{
+		Position:           20,
+		JobKey:             10,
+		ProcessInstanceKey: 100,
+		Intent:             "FAILED",
+		Retries:            2,
+		Worker:             "worker",
+		ErrorMessage:       "error-message",
+		ErrorCode:          "error-code",
+		RetryBackoff:       1000,
+		Time:               now,
+	}
+	expected := &JobEvent{
+		Position:     20,
+		Intent:       "FAILED",
+		Retries:      2,
+		Worker:       "worker",
+		ErrorMessage: "error-message",
+		ErrorCode:    "error-code",
+		RetryBackoff: 1000,
+		Time:         now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageJobEvent(storageEvent)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageRejection(t *testing.T) {
+	now := time.Now()
+
+	storageRejection := storage.Rejection{
+		Position:           5,
+		Key:                10,
+		ProcessInstanceKey: 100,
+		ElementID:          "element-id",
+		ValueType:          "JOB",
+		Intent:             "COMPLETE",
+		RejectionType:      "NOT_FOUND",
+		RejectionReason:    "reason",
+		Time:               now,
+	}
+	expected := &Rejection{
+		Position:        5,
+		Key:             10,
+		InstanceKey:     100,
+		ElementID:       "element-id",
+		ValueType:       "JOB",
+		Intent:          "COMPLETE",
+		RejectionType:   "NOT_FOUND",
+		RejectionReason: "reason",
+		Time:            now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageRejection(storageRejection)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestRejectionFilterToStorageFilter(t *testing.T) {
+	assert.Nil(t, RejectionFilterToStorageFilter(nil))
+
+	actual := RejectionFilterToStorageFilter(&RejectionFilter{
+		RejectionType: "NOT_FOUND",
+		Type:          FilterTypeIsNot,
+	})
+	assert.Equal(t, &storage.Filter{
+		Input: "NOT_FOUND",
+		Type:  storage.FilterTypeIsNot,
+	}, actual)
+}
+
+func TestFromStorageMessage(t *testing.T) {
+	now := time.Now()
+	nowFormatted := now.UTC().Format(RFC3339Milli)
+
+	storageMessage := storage.Message{
+		Key:            10,
+		Name:           "name",
+		CorrelationKey: "correlation-key",
+		MessageID:      "message-id",
+		TimeToLive:     1000,
+		Variables:      `{"a":1}`,
+		State:          "PUBLISHED",
+		PublishTime:    now,
+		Time:           now,
+		Position:       5,
+	}
+	expected := &Message{
+		Key:            10,
+		Name:           "name",
+		CorrelationKey: "correlation-key",
+		MessageID:      "message-id",
+		TimeToLive:     1000,
+		Variables:      `{"a":1}`,
+		State:          "PUBLISHED",
+		PublishTime:    nowFormatted,
+		Time:           nowFormatted,
+	}
+
+	actual := FromStorageMessage(storageMessage)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageMessageSubscription(t *testing.T) {
+	now := time.Now()
+	messageKey := int64(20)
+
+	tests := []struct {
+		name       string
+		messageKey int64
+		expected   *int64
+	}{
+		{
+			name:       "Uncorrelated subscription",
+			messageKey: 0,
+			expected:   nil,
+		},
+		{
+			name:       "Correlated subscription",
+			messageKey: messageKey,
+			expected:   &messageKey,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			storageSubscription := storage.MessageSubscription{
+				ElementInstanceKey: 10,
+				MessageName:        "name",
+				ProcessInstanceKey: 100,
+				ElementID:          "element-id",
+				CorrelationKey:     "correlation-key",
+				MessageKey:         test.messageKey,
+				Interrupting:       true,
+				State:              "CREATED",
+				Time:               now,
+			}
+			expected := &MessageSubscription{
+				InstanceKey:        100,
+				ElementInstanceKey: 10,
+				ElementID:          "element-id",
+				MessageName:        "name",
+				CorrelationKey:     "correlation-key",
+				MessageKey:         test.expected,
+				Interrupting:       true,
+				State:              "CREATED",
+				Time:               now.UTC().Format(RFC3339Milli),
+			}
+
+			actual := FromStorageMessageSubscription(storageSubscription)
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestFromStorageTimer(t *testing.T) {
+	now := time.Now()
+	due := now.Add(time.Hour)
+
+	storageTimer := storage.Timer{
+		Key:                  10,
+		ProcessInstanceKey:   100,
+		ProcessDefinitionKey: 1,
+		ElementInstanceKey:   11,
+		TargetElementID:      "element-id",
+		DueDate:              due,
+		Repetitions:          -1,
+		State:                "CREATED",
+		Time:                 now,
+		Position:             5,
+	}
+	expected := &Timer{
+		Key:                10,
+		InstanceKey:        100,
+		ProcessKey:         1,
+		ElementInstanceKey: 11,
+		TargetElementID:    "element-id",
+		DueDate:            due.UTC().Format(RFC3339Milli),
+		Repetitions:        -1,
+		State:              "CREATED",
+		Time:               now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageTimer(storageTimer)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageUserTask(t *testing.T) {
+	now := time.Now()
+	due := now.Add(24 * time.Hour)
+
+	storageUserTask := storage.UserTask{
+		Key:                  10,
+		ProcessInstanceKey:   100,
+		ProcessDefinitionKey: 1,
+		ElementInstanceKey:   11,
+		ElementID:            "review",
+		Assignee:             "demo",
+		CandidateGroups:      `["reviewers"]`,
+		CandidateUsers:       `[]`,
+		DueDate:              sql.NullTime{Time: due, Valid: true},
+		State:                "CREATED",
+		CreationTime:         now,
+		Time:                 now,
+		Position:             5,
+	}
+	dueString := due.UTC().Format(RFC3339Milli)
+	expected := &UserTask{
+		Key:                10,
+		InstanceKey:        100,
+		ProcessKey:         1,
+		ElementInstanceKey: 11,
+		ElementID:          "review",
+		Assignee:           "demo",
+		CandidateGroups:    `["reviewers"]`,
+		CandidateUsers:     `[]`,
+		DueDate:            &dueString,
+		State:              "CREATED",
+		CreationTime:       now.UTC().Format(RFC3339Milli),
+		Time:               now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageUserTask(storageUserTask)
+
+	assert.Equal(t, expected, actual)
+
+	storageUserTask.FormKey = 20
+	actual = FromStorageUserTask(storageUserTask)
+
+	assert.Equal(t, int64(20), *actual.FormKey)
+}
+
+func TestFromStorageSignalSubscription(t *testing.T) {
+	now := time.Now()
+
+	storageSubscription := storage.SignalSubscription{
+		Key:                   10,
+		SignalName:            "signal",
+		ProcessDefinitionKey:  1,
+		BpmnProcessID:         "process",
+		CatchEventID:          "catch",
+		CatchEventInstanceKey: -1,
+		State:                 "CREATED",
+		Time:                  now,
+		Position:              5,
+	}
+	expected := &SignalSubscription{
+		Key:                   10,
+		SignalName:            "signal",
+		ProcessKey:            1,
+		BpmnProcessID:         "process",
+		CatchEventID:          "catch",
+		CatchEventInstanceKey: -1,
+		State:                 "CREATED",
+		Time:                  now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageSignalSubscription(storageSubscription)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageBrokerError(t *testing.T) {
+	now := time.Now()
+
+	storageBrokerError := storage.BrokerError{
+		Position:           5,
+		ErrorEventPosition: 4,
+		ProcessInstanceKey: 100,
+		ExceptionMessage:   "message",
+		Stacktrace:         "stacktrace",
+		Time:               now,
+	}
+	expected := &BrokerError{
+		Position:           5,
+		ErrorEventPosition: 4,
+		InstanceKey:        100,
+		ExceptionMessage:   "message",
+		Stacktrace:         "stacktrace",
+		Time:               now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageBrokerError(storageBrokerError)
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestFromStorageVariable(t *testing.T) {
 	now := time.Now()
+	nowFormatted := now.UTC().Format(RFC3339Milli)
+
+	tests := []struct {
+		name            string
+		storageVariable storage.Variable
+		expected        *Variable
+	}{
+		{
+			name: "Global variable",
+			storageVariable: storage.Variable{
+				ScopeKey:           10,
+				Name:               "variable-name",
+				ProcessInstanceKey: 10,
+				Value:              "variable-value",
+				Time:               now,
+			},
+			expected: &Variable{
+				Name:        "variable-name",
+				Value:       "variable-value",
+				Time:        nowFormatted,
+				InstanceKey: 10,
+				ScopeKey:    10,
+				Global:      true,
+			},
+		},
+		{
+			name: "Local variable",
+			storageVariable: storage.Variable{
+				ScopeKey:           11,
+				Name:               "variable-name",
+				ProcessInstanceKey: 10,
+				Value:              "variable-value",
+				Time:               now,
+			},
+			expected: &Variable{
+				Name:        "variable-name",
+				Value:       "variable-value",
+				Time:        nowFormatted,
+				InstanceKey: 10,
+				ScopeKey:    11,
+				Global:      false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := FromStorageVariable(test.storageVariable)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFromStorageVariableChange(t *testing.T) {
+	now := time.Now()
 
-	storageVariable := storage.Variable{
+	storageChange := storage.VariableChange{
+		Position:           100,
 		ProcessInstanceKey: 10,
+		ScopeKey:           11,
 		Name:               "variable-name",
+		Intent:             "UPDATED",
 		Value:              "variable-value",
 		Time:               now,
 	}
-	expected := &Variable{
-		Name:  "variable-name",
-		Value: "variable-value",
-		Time:  now.UTC().Format(RFC3339Milli),
+	expected := &VariableChange{
+		Position:    100,
+		InstanceKey: 10,
+		ScopeKey:    11,
+		Name:        "variable-name",
+		Intent:      "UPDATED",
+		Value:       "variable-value",
+		Time:        now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageVariableChange(storageChange)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStorageWorker(t *testing.T) {
+	now := time.Now()
+	nowFormatted := now.UTC().Format(RFC3339Milli)
+	completionRate := 0.75
+	failureRate := 0.25
+
+	tests := []struct {
+		name          string
+		storageWorker storage.Worker
+		expected      *Worker
+	}{
+		{
+			name: "Worker with jobs",
+			storageWorker: storage.Worker{
+				Name:          "worker",
+				LastSeen:      now,
+				Polls:         5,
+				Activations:   2,
+				ActivatedJobs: 4,
+				CompletedJobs: 3,
+				FailedJobs:    1,
+			},
+			expected: &Worker{
+				Name:           "worker",
+				LastSeen:       nowFormatted,
+				Polls:          5,
+				Activations:    2,
+				ActivatedJobs:  4,
+				CompletedJobs:  3,
+				FailedJobs:     1,
+				CompletionRate: &completionRate,
+				FailureRate:    &failureRate,
+			},
+		},
+		{
+			name: "Worker without jobs",
+			storageWorker: storage.Worker{
+				Name:     "idle-worker",
+				LastSeen: now,
+				Polls:    3,
+			},
+			expected: &Worker{
+				Name:     "idle-worker",
+				LastSeen: nowFormatted,
+				Polls:    3,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := FromStorageWorker(test.storageWorker)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFromStorageDeadLetter(t *testing.T) {
+	now := time.Now()
+
+	storageDeadLetter := storage.DeadLetter{
+		ID:        3,
+		Topic:     "zeebe-variable",
+		Partition: 1,
+		Offset:    42,
+		Payload:   []byte(`{"key":10}`),
+		Error:     "failed to apply",
+		Attempts:  2,
+		Time:      now,
+	}
+	expected := &DeadLetter{
+		ID:        3,
+		Topic:     "zeebe-variable",
+		Partition: 1,
+		Offset:    42,
+		Payload:   `{"key":10}`,
+		Error:     "failed to apply",
+		Attempts:  2,
+		Time:      now.UTC().Format(RFC3339Milli),
+	}
+
+	actual := FromStorageDeadLetter(storageDeadLetter)
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestFromStoragePendingRecord(t *testing.T) {
+	now := time.Now()
+
+	storagePendingRecord := storage.PendingRecord{
+		ID:         3,
+		Topic:      "zeebe-job",
+		Partition:  1,
+		Offset:     42,
+		Payload:    []byte(`{"key":10}`),
+		MissingKey: 10,
+		Time:       now,
+	}
+	expected := &PendingRecord{
+		ID:         3,
+		Topic:      "zeebe-job",
+		Partition:  1,
+		Offset:     42,
+		Payload:    `{"key":10}`,
+		MissingKey: 10,
+		Time:       now.UTC().Format(RFC3339Milli),
 	}
 
-	actual := FromStorageVariable(storageVariable)
+	actual := FromStoragePendingRecord(storagePendingRecord)
 
 	assert.Equal(t, expected, actual)
 }
@@ -16,6 +16,116 @@ type AuditLog struct {
 	Time        string `json:"time"`
 }
 
+type BrokerError struct {
+	Position           int64     `json:"position"`
+	ErrorEventPosition int64     `json:"errorEventPosition"`
+	InstanceKey        int64     `json:"instanceKey"`
+	ExceptionMessage   string    `json:"exceptionMessage"`
+	Stacktrace         string    `json:"stacktrace"`
+	Time               string    `json:"time"`
+	Instance           *Instance `json:"instance,omitempty"`
+}
+
+type DeadLetter struct {
+	ID        int64  `json:"id"`
+	Topic     string `json:"topic"`
+	Partition int64  `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Payload   string `json:"payload"`
+	Error     string `json:"error"`
+	Attempts  int64  `json:"attempts"`
+	Time      string `json:"time"`
+}
+
+type Decision struct {
+	DecisionKey             int64                         `json:"decisionKey"`
+	DecisionID              string                        `json:"decisionId"`
+	Name                    string                        `json:"name"`
+	Version                 int64                         `json:"version"`
+	DecisionRequirementsKey int64                         `json:"decisionRequirementsKey"`
+	DecisionRequirementsID  string                        `json:"decisionRequirementsId"`
+	DeploymentTime          string                        `json:"deploymentTime"`
+	DecisionRequirements    *DecisionRequirements         `json:"decisionRequirements"`
+	Evaluations             *PaginatedDecisionEvaluations `json:"evaluations"`
+}
+
+type DecisionEvaluation struct {
+	Key                     int64                `json:"key"`
+	DecisionKey             int64                `json:"decisionKey"`
+	DecisionID              string               `json:"decisionId"`
+	DecisionName            string               `json:"decisionName"`
+	DecisionVersion         int64                `json:"decisionVersion"`
+	DecisionRequirementsKey int64                `json:"decisionRequirementsKey"`
+	Output                  string               `json:"output"`
+	InstanceKey             int64                `json:"instanceKey"`
+	ProcessKey              int64                `json:"processKey"`
+	ElementInstanceKey      int64                `json:"elementInstanceKey"`
+	ElementID               string               `json:"elementId"`
+	State                   string               `json:"state"`
+	FailureMessage          string               `json:"failureMessage"`
+	FailedDecisionID        string               `json:"failedDecisionId"`
+	Time                    string               `json:"time"`
+	Instance                *Instance            `json:"instance,omitempty"`
+	EvaluatedDecisions      []*EvaluatedDecision `json:"evaluatedDecisions"`
+}
+
+type DecisionRequirements struct {
+	DecisionRequirementsKey int64               `json:"decisionRequirementsKey"`
+	DecisionRequirementsID  string              `json:"decisionRequirementsId"`
+	Name                    string              `json:"name"`
+	Version                 int64               `json:"version"`
+	Namespace               string              `json:"namespace"`
+	ResourceName            string              `json:"resourceName"`
+	DeploymentTime          string              `json:"deploymentTime"`
+	DmnResource             string              `json:"dmnResource"`
+	Decisions               *PaginatedDecisions `json:"decisions"`
+}
+
+type DeployedDefinition struct {
+	DefinitionKey int64  `json:"definitionKey"`
+	Kind          string `json:"kind"`
+	DefinitionID  string `json:"definitionId"`
+	Version       int64  `json:"version"`
+	ResourceName  string `json:"resourceName"`
+	Duplicate     bool   `json:"duplicate"`
+}
+
+type Deployment struct {
+	DeploymentKey int64                 `json:"deploymentKey"`
+	Time          string                `json:"time"`
+	Resources     []*DeploymentResource `json:"resources"`
+	Definitions   []*DeployedDefinition `json:"definitions"`
+}
+
+type DeploymentResource struct {
+	ResourceName string `json:"resourceName"`
+	ResourceType string `json:"resourceType"`
+	Size         int64  `json:"size"`
+}
+
+type ElementInstance struct {
+	Key          int64               `json:"key"`
+	InstanceKey  int64               `json:"instanceKey"`
+	ElementID    string              `json:"elementId"`
+	ElementType  string              `json:"elementType"`
+	FlowScopeKey int64               `json:"flowScopeKey"`
+	State        string              `json:"state"`
+	StartTime    string              `json:"startTime"`
+	EndTime      *string             `json:"endTime,omitempty"`
+	Variables    *PaginatedVariables `json:"variables"`
+}
+
+type EvaluatedDecision struct {
+	DecisionKey     int64  `json:"decisionKey"`
+	DecisionID      string `json:"decisionId"`
+	DecisionName    string `json:"decisionName"`
+	DecisionVersion int64  `json:"decisionVersion"`
+	DecisionType    string `json:"decisionType"`
+	Output          string `json:"output"`
+	Inputs          string `json:"inputs"`
+	MatchedRules    string `json:"matchedRules"`
+}
+
 type Incident struct {
 	IncidentKey  int64     `json:"incidentKey"`
 	InstanceKey  int64     `json:"instanceKey"`
@@ -28,29 +138,93 @@ type Incident struct {
 }
 
 type Instance struct {
-	StartTime   string              `json:"startTime"`
-	EndTime     *string             `json:"endTime,omitempty"`
-	InstanceKey int64               `json:"instanceKey"`
-	ProcessKey  int64               `json:"processKey"`
-	Version     int64               `json:"version"`
-	Status      string              `json:"status"`
-	AuditLogs   *PaginatedAuditLogs `json:"auditLogs"`
-	Incidents   *PaginatedIncidents `json:"incidents"`
-	Jobs        *PaginatedJobs      `json:"jobs"`
-	Variables   *PaginatedVariables `json:"variables"`
-	Process     *Process            `json:"process"`
+	StartTime                string                         `json:"startTime"`
+	EndTime                  *string                        `json:"endTime,omitempty"`
+	InstanceKey              int64                          `json:"instanceKey"`
+	ProcessKey               int64                          `json:"processKey"`
+	Version                  int64                          `json:"version"`
+	Status                   string                         `json:"status"`
+	ParentInstanceKey        *int64                         `json:"parentInstanceKey,omitempty"`
+	ParentElementInstanceKey *int64                         `json:"parentElementInstanceKey,omitempty"`
+	Parent                   *Instance                      `json:"parent,omitempty"`
+	Children                 *PaginatedInstances            `json:"children"`
+	Root                     *Instance                      `json:"root"`
+	AuditLogs                *PaginatedAuditLogs            `json:"auditLogs"`
+	ElementInstances         *PaginatedElementInstances     `json:"elementInstances"`
+	Incidents                *PaginatedIncidents            `json:"incidents"`
+	Jobs                     *PaginatedJobs                 `json:"jobs"`
+	Rejections               *PaginatedRejections           `json:"rejections"`
+	MessageSubscriptions     *PaginatedMessageSubscriptions `json:"messageSubscriptions"`
+	Messages                 *PaginatedMessages             `json:"messages"`
+	Timers                   *PaginatedTimers               `json:"timers"`
+	Errors                   *PaginatedBrokerErrors         `json:"errors"`
+	Variables                *PaginatedVariables            `json:"variables"`
+	VariableChanges          *PaginatedVariableChanges      `json:"variableChanges"`
+	UserTasks                *PaginatedUserTasks            `json:"userTasks"`
+	DecisionEvaluations      *PaginatedDecisionEvaluations  `json:"decisionEvaluations"`
+	Process                  *Process                       `json:"process"`
 }
 
 type Job struct {
-	ElementID   string    `json:"elementId"`
-	InstanceKey int64     `json:"instanceKey"`
-	Key         int64     `json:"key"`
-	Type        string    `json:"type"`
-	Retries     int64     `json:"retries"`
-	Worker      string    `json:"worker"`
-	State       string    `json:"state"`
-	Time        string    `json:"time"`
-	Instance    *Instance `json:"instance"`
+	ElementID          string              `json:"elementId"`
+	ElementInstanceKey int64               `json:"elementInstanceKey"`
+	InstanceKey        int64               `json:"instanceKey"`
+	Key                int64               `json:"key"`
+	Type               string              `json:"type"`
+	Retries            int64               `json:"retries"`
+	Worker             string              `json:"worker"`
+	State              string              `json:"state"`
+	ErrorMessage       string              `json:"errorMessage"`
+	ErrorCode          string              `json:"errorCode"`
+	Deadline           *string             `json:"deadline,omitempty"`
+	RetryBackoff       int64               `json:"retryBackoff"`
+	CustomHeaders      string              `json:"customHeaders"`
+	Time               string              `json:"time"`
+	Instance           *Instance           `json:"instance"`
+	History            *PaginatedJobEvents `json:"history"`
+}
+
+type JobEvent struct {
+	Position     int64  `json:"position"`
+	Intent       string `json:"intent"`
+	Retries      int64  `json:"retries"`
+	Worker       string `json:"worker"`
+	ErrorMessage string `json:"errorMessage"`
+	ErrorCode    string `json:"errorCode"`
+	RetryBackoff int64  `json:"retryBackoff"`
+	Time         string `json:"time"`
+}
+
+type Message struct {
+	Key            int64                          `json:"key"`
+	Name           string                         `json:"name"`
+	CorrelationKey string                         `json:"correlationKey"`
+	MessageID      string                         `json:"messageId"`
+	TimeToLive     int64                          `json:"timeToLive"`
+	Variables      string                         `json:"variables"`
+	State          string                         `json:"state"`
+	PublishTime    string                         `json:"publishTime"`
+	Time           string                         `json:"time"`
+	Subscriptions  *PaginatedMessageSubscriptions `json:"subscriptions"`
+}
+
+type MessageFilter struct {
+	Name string     `json:"name"`
+	Type FilterType `json:"type"`
+}
+
+type MessageSubscription struct {
+	InstanceKey        int64     `json:"instanceKey"`
+	ElementInstanceKey int64     `json:"elementInstanceKey"`
+	ElementID          string    `json:"elementId"`
+	MessageName        string    `json:"messageName"`
+	CorrelationKey     string    `json:"correlationKey"`
+	MessageKey         *int64    `json:"messageKey,omitempty"`
+	Interrupting       bool      `json:"interrupting"`
+	State              string    `json:"state"`
+	Time               string    `json:"time"`
+	Instance           *Instance `json:"instance"`
+	Message            *Message  `json:"message,omitempty"`
 }
 
 type PaginatedAuditLogs struct {
@@ -58,6 +232,41 @@ type PaginatedAuditLogs struct {
 	TotalCount int64       `json:"totalCount"`
 }
 
+type PaginatedBrokerErrors struct {
+	Items      []*BrokerError `json:"items"`
+	TotalCount int64          `json:"totalCount"`
+}
+
+type PaginatedDeadLetters struct {
+	Items      []*DeadLetter `json:"items"`
+	TotalCount int64         `json:"totalCount"`
+}
+
+type PaginatedDecisionEvaluations struct {
+	Items      []*DecisionEvaluation `json:"items"`
+	TotalCount int64                 `json:"totalCount"`
+}
+
+type PaginatedDecisionRequirements struct {
+	Items      []*DecisionRequirements `json:"items"`
+	TotalCount int64                   `json:"totalCount"`
+}
+
+type PaginatedDecisions struct {
+	Items      []*Decision `json:"items"`
+	TotalCount int64       `json:"totalCount"`
+}
+
+type PaginatedDeployments struct {
+	Items      []*Deployment `json:"items"`
+	TotalCount int64         `json:"totalCount"`
+}
+
+type PaginatedElementInstances struct {
+	Items      []*ElementInstance `json:"items"`
+	TotalCount int64              `json:"totalCount"`
+}
+
 type PaginatedIncidents struct {
 	Items      []*Incident `json:"items"`
 	TotalCount int64       `json:"totalCount"`
@@ -68,39 +277,196 @@ type PaginatedInstances struct {
 	TotalCount int64       `json:"totalCount"`
 }
 
+type PaginatedJobEvents struct {
+	Items      []*JobEvent `json:"items"`
+	TotalCount int64       `json:"totalCount"`
+}
+
 type PaginatedJobs struct {
 	Items      []*Job `json:"items"`
 	TotalCount int64  `json:"totalCount"`
 }
 
+type PaginatedMessageSubscriptions struct {
+	Items      []*MessageSubscription `json:"items"`
+	TotalCount int64                  `json:"totalCount"`
+}
+
+type PaginatedMessages struct {
+	Items      []*Message `json:"items"`
+	TotalCount int64      `json:"totalCount"`
+}
+
+type PaginatedPendingRecords struct {
+	Items      []*PendingRecord `json:"items"`
+	TotalCount int64            `json:"totalCount"`
+}
+
 type PaginatedProcesses struct {
 	Items      []*Process `json:"items"`
 	TotalCount int64      `json:"totalCount"`
 }
 
+type PaginatedRejections struct {
+	Items      []*Rejection `json:"items"`
+	TotalCount int64        `json:"totalCount"`
+}
+
+type PaginatedSignalSubscriptions struct {
+	Items      []*SignalSubscription `json:"items"`
+	TotalCount int64                 `json:"totalCount"`
+}
+
+type PaginatedSignals struct {
+	Items      []*Signal `json:"items"`
+	TotalCount int64     `json:"totalCount"`
+}
+
+type PaginatedTimers struct {
+	Items      []*Timer `json:"items"`
+	TotalCount int64    `json:"totalCount"`
+}
+
+type PaginatedUserTasks struct {
+	Items      []*UserTask `json:"items"`
+	TotalCount int64       `json:"totalCount"`
+}
+
+type PaginatedVariableChanges struct {
+	Items      []*VariableChange `json:"items"`
+	TotalCount int64             `json:"totalCount"`
+}
+
 type PaginatedVariables struct {
 	Items      []*Variable `json:"items"`
 	TotalCount int64       `json:"totalCount"`
 }
 
+type PaginatedWorkers struct {
+	Items      []*Worker `json:"items"`
+	TotalCount int64     `json:"totalCount"`
+}
+
 type Pagination struct {
 	Offset int64 `json:"offset"`
 	Limit  int64 `json:"limit"`
 }
 
+type PendingRecord struct {
+	ID         int64  `json:"id"`
+	Topic      string `json:"topic"`
+	Partition  int64  `json:"partition"`
+	Offset     int64  `json:"offset"`
+	Payload    string `json:"payload"`
+	MissingKey int64  `json:"missingKey"`
+	Time       string `json:"time"`
+}
+
 type Process struct {
-	BpmnResource   string              `json:"bpmnResource"`
-	BpmnProcessID  string              `json:"bpmnProcessId"`
-	DeploymentTime string              `json:"deploymentTime"`
-	Instances      *PaginatedInstances `json:"instances"`
-	ProcessKey     int64               `json:"processKey"`
-	Version        int64               `json:"version"`
+	BpmnResource        string                        `json:"bpmnResource"`
+	BpmnProcessID       string                        `json:"bpmnProcessId"`
+	DeploymentTime      string                        `json:"deploymentTime"`
+	Instances           *PaginatedInstances           `json:"instances"`
+	Timers              *PaginatedTimers              `json:"timers"`
+	SignalSubscriptions *PaginatedSignalSubscriptions `json:"signalSubscriptions"`
+	ProcessKey          int64                         `json:"processKey"`
+	Version             int64                         `json:"version"`
+}
+
+type Rejection struct {
+	Position        int64     `json:"position"`
+	Key             int64     `json:"key"`
+	InstanceKey     int64     `json:"instanceKey"`
+	ElementID       string    `json:"elementId"`
+	ValueType       string    `json:"valueType"`
+	Intent          string    `json:"intent"`
+	RejectionType   string    `json:"rejectionType"`
+	RejectionReason string    `json:"rejectionReason"`
+	Time            string    `json:"time"`
+	Instance        *Instance `json:"instance,omitempty"`
+}
+
+type RejectionFilter struct {
+	RejectionType string     `json:"rejectionType"`
+	Type          FilterType `json:"type"`
+}
+
+type Signal struct {
+	Key           int64                         `json:"key"`
+	Name          string                        `json:"name"`
+	Variables     string                        `json:"variables"`
+	Time          string                        `json:"time"`
+	Subscriptions *PaginatedSignalSubscriptions `json:"subscriptions"`
+}
+
+type SignalSubscription struct {
+	Key                   int64    `json:"key"`
+	SignalName            string   `json:"signalName"`
+	ProcessKey            int64    `json:"processKey"`
+	BpmnProcessID         string   `json:"bpmnProcessId"`
+	CatchEventID          string   `json:"catchEventId"`
+	CatchEventInstanceKey int64    `json:"catchEventInstanceKey"`
+	State                 string   `json:"state"`
+	Time                  string   `json:"time"`
+	Process               *Process `json:"process,omitempty"`
+}
+
+type Timer struct {
+	Key                int64     `json:"key"`
+	InstanceKey        int64     `json:"instanceKey"`
+	ProcessKey         int64     `json:"processKey"`
+	ElementInstanceKey int64     `json:"elementInstanceKey"`
+	TargetElementID    string    `json:"targetElementId"`
+	DueDate            string    `json:"dueDate"`
+	Repetitions        int64     `json:"repetitions"`
+	State              string    `json:"state"`
+	Time               string    `json:"time"`
+	Instance           *Instance `json:"instance,omitempty"`
+}
+
+type UserTask struct {
+	Key                int64     `json:"key"`
+	InstanceKey        int64     `json:"instanceKey"`
+	ProcessKey         int64     `json:"processKey"`
+	ElementInstanceKey int64     `json:"elementInstanceKey"`
+	ElementID          string    `json:"elementId"`
+	Assignee           string    `json:"assignee"`
+	CandidateGroups    string    `json:"candidateGroups"`
+	CandidateUsers     string    `json:"candidateUsers"`
+	DueDate            *string   `json:"dueDate,omitempty"`
+	FollowUpDate       *string   `json:"followUpDate,omitempty"`
+	FormKey            *int64    `json:"formKey,omitempty"`
+	State              string    `json:"state"`
+	CreationTime       string    `json:"creationTime"`
+	EndTime            *string   `json:"endTime,omitempty"`
+	Time               string    `json:"time"`
+	Instance           *Instance `json:"instance"`
+}
+
+type UserTaskFilter struct {
+	Assignee string     `json:"assignee"`
+	Type     FilterType `json:"type"`
 }
 
 type Variable struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
-	Time  string `json:"time"`
+	Name            string                    `json:"name"`
+	Value           string                    `json:"value"`
+	Time            string                    `json:"time"`
+	InstanceKey     int64                     `json:"instanceKey"`
+	ScopeKey        int64                     `json:"scopeKey"`
+	Global          bool                      `json:"global"`
+	ElementInstance *ElementInstance          `json:"elementInstance,omitempty"`
+	History         *PaginatedVariableChanges `json:"history"`
+}
+
+type VariableChange struct {
+	Position    int64  `json:"position"`
+	InstanceKey int64  `json:"instanceKey"`
+	ScopeKey    int64  `json:"scopeKey"`
+	Name        string `json:"name"`
+	Intent      string `json:"intent"`
+	Value       string `json:"value"`
+	Time        string `json:"time"`
 }
 
 type VariableFilter struct {
@@ -108,6 +474,19 @@ type VariableFilter struct {
 	Type FilterType `json:"type"`
 }
 
+type Worker struct {
+	Name           string   `json:"name"`
+	LastSeen       string   `json:"lastSeen"`
+	Polls          int64    `json:"polls"`
+	Activations    int64    `json:"activations"`
+	ActivatedJobs  int64    `json:"activatedJobs"`
+	CompletedJobs  int64    `json:"completedJobs"`
+	FailedJobs     int64    `json:"failedJobs"`
+	CompletionRate *float64 `json:"completionRate,omitempty"`
+	FailureRate    *float64 `json:"failureRate,omitempty"`
+	JobTypes       []string `json:"jobTypes"`
+}
+
 type FilterType string
 
 const (
@@ -9,5 +9,25 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	Fetcher *storage.Fetcher
+	Fetcher        *storage.Fetcher
+	DeadLetters    DeadLetterQueue
+	PendingRecords PendingRecordQueue
+}
+
+// DeadLetterQueue is used to retry and discard records that failed to be
+// applied.
+type DeadLetterQueue interface {
+	// Retry applying a dead letter. Returns whether applying succeeded.
+	Retry(id int64) (bool, error)
+	// Discard a dead letter without applying it.
+	Discard(id int64) error
+}
+
+// PendingRecordQueue is used to retry and discard records waiting for rows
+// that haven't been created.
+type PendingRecordQueue interface {
+	// Retry applying a pending record. Returns whether applying succeeded.
+	Retry(id int64) (bool, error)
+	// Discard a pending record without applying it.
+	Discard(id int64) error
 }
@@ -9,8 +9,138 @@ import (
 	"fmt"
 
 	"github.com/ducanhpham0312/zeevision/backend/graph/model"
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
 )
 
+// Instance is the resolver for the instance field.
+func (r *brokerErrorResolver) Instance(ctx context.Context, obj *model.BrokerError) (*model.Instance, error) {
+	// Not every error happens while processing an instance
+	if obj.InstanceKey <= 0 {
+		return nil, nil
+	}
+
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// DecisionRequirements is the resolver for the decisionRequirements field.
+func (r *decisionResolver) DecisionRequirements(ctx context.Context, obj *model.Decision) (*model.DecisionRequirements, error) {
+	dbDecisionRequirements, err := r.Fetcher.GetDecisionRequirements(ctx, obj.DecisionRequirementsKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision requirements: %w", err)
+	}
+
+	return model.FromStorageDecisionRequirements(dbDecisionRequirements), nil
+}
+
+// Evaluations is the resolver for the evaluations field.
+func (r *decisionResolver) Evaluations(ctx context.Context, obj *model.Decision, pagination *model.Pagination) (*model.PaginatedDecisionEvaluations, error) {
+	dbEvaluations, err := r.Fetcher.GetDecisionEvaluationsForDecision(ctx, model.ToStoragePagination(pagination), obj.DecisionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision evaluations: %w", err)
+	}
+
+	return &model.PaginatedDecisionEvaluations{
+		Items:      model.Map(dbEvaluations.Items, model.FromStorageDecisionEvaluation),
+		TotalCount: dbEvaluations.TotalCount,
+	}, nil
+}
+
+// Instance is the resolver for the instance field.
+func (r *decisionEvaluationResolver) Instance(ctx context.Context, obj *model.DecisionEvaluation) (*model.Instance, error) {
+	// Decisions can be evaluated outside of process instances too
+	if obj.InstanceKey <= 0 {
+		return nil, nil
+	}
+
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// EvaluatedDecisions is the resolver for the evaluatedDecisions field.
+func (r *decisionEvaluationResolver) EvaluatedDecisions(ctx context.Context, obj *model.DecisionEvaluation) ([]*model.EvaluatedDecision, error) {
+	dbDecisions, err := r.Fetcher.GetEvaluatedDecisions(ctx, obj.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch evaluated decisions: %w", err)
+	}
+
+	return model.Map(dbDecisions, model.FromStorageEvaluatedDecision), nil
+}
+
+// DmnResource is the resolver for the dmnResource field.
+func (r *decisionRequirementsResolver) DmnResource(ctx context.Context, obj *model.DecisionRequirements) (string, error) {
+	dbDmnResource, err := r.Fetcher.GetDmnResource(ctx, obj.DecisionRequirementsKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dmn resource: %w", err)
+	}
+
+	return model.FromStorageDmnResource(dbDmnResource), nil
+}
+
+// Decisions is the resolver for the decisions field.
+func (r *decisionRequirementsResolver) Decisions(ctx context.Context, obj *model.DecisionRequirements, pagination *model.Pagination) (*model.PaginatedDecisions, error) {
+	dbDecisions, err := r.Fetcher.GetDecisionsForRequirements(ctx, model.ToStoragePagination(pagination), obj.DecisionRequirementsKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decisions: %w", err)
+	}
+
+	return &model.PaginatedDecisions{
+		Items:      model.Map(dbDecisions.Items, model.FromStorageDecision),
+		TotalCount: dbDecisions.TotalCount,
+	}, nil
+}
+
+// Resources is the resolver for the resources field.
+func (r *deploymentResolver) Resources(ctx context.Context, obj *model.Deployment) ([]*model.DeploymentResource, error) {
+	dbResources, err := r.Fetcher.GetDeploymentResources(ctx, obj.DeploymentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployment resources: %w", err)
+	}
+
+	return model.Map(dbResources, model.FromStorageDeploymentResource), nil
+}
+
+// Definitions is the resolver for the definitions field.
+func (r *deploymentResolver) Definitions(ctx context.Context, obj *model.Deployment) ([]*model.DeployedDefinition, error) {
+	dbDefinitions, err := r.Fetcher.GetDeployedDefinitions(ctx, obj.DeploymentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployed definitions: %w", err)
+	}
+
+	return model.Map(dbDefinitions, model.FromStorageDeployedDefinition), nil
+}
+
+// Variables is the resolver for the variables field.
+func (r *elementInstanceResolver) Variables(ctx context.Context, obj *model.ElementInstance, pagination *model.Pagination, filter *model.VariableFilter) (*model.PaginatedVariables, error) {
+	dbVariables, err := r.Fetcher.GetVariablesForScope(ctx,
+		model.ToStoragePagination(pagination),
+		model.VariableFilterToStorageFilter(filter),
+		obj.Key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variables: %w", err)
+	}
+
+	return &model.PaginatedVariables{
+		Items:      model.Map(dbVariables.Items, model.FromStorageVariable),
+		TotalCount: dbVariables.TotalCount,
+	}, nil
+}
+
 // Instance is the resolver for the instance field.
 func (r *incidentResolver) Instance(ctx context.Context, obj *model.Incident) (*model.Instance, error) {
 	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
@@ -21,6 +151,47 @@ func (r *incidentResolver) Instance(ctx context.Context, obj *model.Incident) (*
 	return model.FromStorageInstance(dbInstance), nil
 }
 
+// Parent is the resolver for the parent field.
+func (r *instanceResolver) Parent(ctx context.Context, obj *model.Instance) (*model.Instance, error) {
+	// Instances not started by a call activity have no parent
+	if obj.ParentInstanceKey == nil {
+		return nil, nil
+	}
+
+	dbInstance, err := r.Fetcher.GetInstance(ctx, *obj.ParentInstanceKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// Children is the resolver for the children field.
+func (r *instanceResolver) Children(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedInstances, error) {
+	dbInstances, err := r.Fetcher.GetChildInstances(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instances: %w", err)
+	}
+
+	return &model.PaginatedInstances{
+		Items:      model.Map(dbInstances.Items, model.FromStorageInstance),
+		TotalCount: dbInstances.TotalCount,
+	}, nil
+}
+
+// Root is the resolver for the root field.
+func (r *instanceResolver) Root(ctx context.Context, obj *model.Instance) (*model.Instance, error) {
+	dbInstance, err := r.Fetcher.GetRootInstance(ctx, obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
 // AuditLogs is the resolver for the auditLogs field.
 func (r *instanceResolver) AuditLogs(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedAuditLogs, error) {
 	dbAuditLogs, err := r.Fetcher.GetAuditLogsForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
@@ -34,6 +205,19 @@ func (r *instanceResolver) AuditLogs(ctx context.Context, obj *model.Instance, p
 	}, nil
 }
 
+// ElementInstances is the resolver for the elementInstances field.
+func (r *instanceResolver) ElementInstances(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedElementInstances, error) {
+	dbElementInstances, err := r.Fetcher.GetElementInstancesForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch element instances: %w", err)
+	}
+
+	return &model.PaginatedElementInstances{
+		Items:      model.Map(dbElementInstances.Items, model.FromStorageElementInstance),
+		TotalCount: dbElementInstances.TotalCount,
+	}, nil
+}
+
 // Incidents is the resolver for the incidents field.
 func (r *instanceResolver) Incidents(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedIncidents, error) {
 	dbIncidents, err := r.Fetcher.GetIncidentsForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
@@ -60,12 +244,90 @@ func (r *instanceResolver) Jobs(ctx context.Context, obj *model.Instance, pagina
 	}, nil
 }
 
+// Rejections is the resolver for the rejections field.
+func (r *instanceResolver) Rejections(ctx context.Context, obj *model.Instance, pagination *model.Pagination, filter *model.RejectionFilter) (*model.PaginatedRejections, error) {
+	dbRejections, err := r.Fetcher.GetRejectionsForInstance(ctx,
+		model.ToStoragePagination(pagination),
+		model.RejectionFilterToStorageFilter(filter),
+		obj.InstanceKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rejections: %w", err)
+	}
+
+	return &model.PaginatedRejections{
+		Items:      model.Map(dbRejections.Items, model.FromStorageRejection),
+		TotalCount: dbRejections.TotalCount,
+	}, nil
+}
+
+// MessageSubscriptions is the resolver for the messageSubscriptions field.
+func (r *instanceResolver) MessageSubscriptions(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedMessageSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetMessageSubscriptionsForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message subscriptions: %w", err)
+	}
+
+	return &model.PaginatedMessageSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageMessageSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
+// Messages is the resolver for the messages field.
+func (r *instanceResolver) Messages(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedMessages, error) {
+	dbMessages, err := r.Fetcher.GetMessagesForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	return &model.PaginatedMessages{
+		Items:      model.Map(dbMessages.Items, model.FromStorageMessage),
+		TotalCount: dbMessages.TotalCount,
+	}, nil
+}
+
+// Timers is the resolver for the timers field.
+func (r *instanceResolver) Timers(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedTimers, error) {
+	dbTimers, err := r.Fetcher.GetTimersForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timers: %w", err)
+	}
+
+	return &model.PaginatedTimers{
+		Items:      model.Map(dbTimers.Items, model.FromStorageTimer),
+		TotalCount: dbTimers.TotalCount,
+	}, nil
+}
+
+// Errors is the resolver for the errors field.
+func (r *instanceResolver) Errors(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedBrokerErrors, error) {
+	dbBrokerErrors, err := r.Fetcher.GetBrokerErrorsForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch errors: %w", err)
+	}
+
+	return &model.PaginatedBrokerErrors{
+		Items:      model.Map(dbBrokerErrors.Items, model.FromStorageBrokerError),
+		TotalCount: dbBrokerErrors.TotalCount,
+	}, nil
+}
+
 // Variables is the resolver for the variables field.
-func (r *instanceResolver) Variables(ctx context.Context, obj *model.Instance, pagination *model.Pagination, filter *model.VariableFilter) (*model.PaginatedVariables, error) {
-	dbVariables, err := r.Fetcher.GetVariablesForInstance(ctx,
+func (r *instanceResolver) Variables(ctx context.Context, obj *model.Instance, pagination *model.Pagination, filter *model.VariableFilter, scopeKey *int64) (*model.PaginatedVariables, error) {
+	fetch := r.Fetcher.GetVariablesForInstance
+	key := obj.InstanceKey
+	if scopeKey != nil {
+		// Element instance keys are unique across instances, so the scope
+		// alone identifies the variables
+		fetch = r.Fetcher.GetVariablesForScope
+		key = *scopeKey
+	}
+
+	dbVariables, err := fetch(ctx,
 		model.ToStoragePagination(pagination),
 		model.VariableFilterToStorageFilter(filter),
-		obj.InstanceKey,
+		key,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch variables: %w", err)
@@ -77,6 +339,49 @@ func (r *instanceResolver) Variables(ctx context.Context, obj *model.Instance, p
 	}, nil
 }
 
+// VariableChanges is the resolver for the variableChanges field.
+func (r *instanceResolver) VariableChanges(ctx context.Context, obj *model.Instance, pagination *model.Pagination, filter *model.VariableFilter) (*model.PaginatedVariableChanges, error) {
+	dbChanges, err := r.Fetcher.GetVariableChangesForInstance(ctx,
+		model.ToStoragePagination(pagination),
+		model.VariableFilterToStorageFilter(filter),
+		obj.InstanceKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variable changes: %w", err)
+	}
+
+	return &model.PaginatedVariableChanges{
+		Items:      model.Map(dbChanges.Items, model.FromStorageVariableChange),
+		TotalCount: dbChanges.TotalCount,
+	}, nil
+}
+
+// UserTasks is the resolver for the userTasks field.
+func (r *instanceResolver) UserTasks(ctx context.Context, obj *model.Instance, pagination *model.Pagination, filter *model.UserTaskFilter) (*model.PaginatedUserTasks, error) {
+	dbUserTasks, err := r.Fetcher.GetUserTasksForInstance(ctx, model.ToStoragePagination(pagination), model.UserTaskFilterToStorageFilter(filter), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user tasks: %w", err)
+	}
+
+	return &model.PaginatedUserTasks{
+		Items:      model.Map(dbUserTasks.Items, model.FromStorageUserTask),
+		TotalCount: dbUserTasks.TotalCount,
+	}, nil
+}
+
+// DecisionEvaluations is the resolver for the decisionEvaluations field.
+func (r *instanceResolver) DecisionEvaluations(ctx context.Context, obj *model.Instance, pagination *model.Pagination) (*model.PaginatedDecisionEvaluations, error) {
+	dbEvaluations, err := r.Fetcher.GetDecisionEvaluationsForInstance(ctx, model.ToStoragePagination(pagination), obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision evaluations: %w", err)
+	}
+
+	return &model.PaginatedDecisionEvaluations{
+		Items:      model.Map(dbEvaluations.Items, model.FromStorageDecisionEvaluation),
+		TotalCount: dbEvaluations.TotalCount,
+	}, nil
+}
+
 // Process is the resolver for the process field.
 func (r *instanceResolver) Process(ctx context.Context, obj *model.Instance) (*model.Process, error) {
 	dbProcess, err := r.Fetcher.GetProcess(ctx, obj.ProcessKey)
@@ -97,6 +402,100 @@ func (r *jobResolver) Instance(ctx context.Context, obj *model.Job) (*model.Inst
 	return model.FromStorageInstance(dbInstance), nil
 }
 
+// History is the resolver for the history field.
+func (r *jobResolver) History(ctx context.Context, obj *model.Job, pagination *model.Pagination) (*model.PaginatedJobEvents, error) {
+	dbEvents, err := r.Fetcher.GetJobHistory(ctx, model.ToStoragePagination(pagination), obj.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job history: %w", err)
+	}
+
+	return &model.PaginatedJobEvents{
+		Items:      model.Map(dbEvents.Items, model.FromStorageJobEvent),
+		TotalCount: dbEvents.TotalCount,
+	}, nil
+}
+
+// Subscriptions is the resolver for the subscriptions field.
+func (r *messageResolver) Subscriptions(ctx context.Context, obj *model.Message, pagination *model.Pagination) (*model.PaginatedMessageSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetMessageSubscriptionsForMessage(ctx, model.ToStoragePagination(pagination), obj.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message subscriptions: %w", err)
+	}
+
+	return &model.PaginatedMessageSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageMessageSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
+// Instance is the resolver for the instance field.
+func (r *messageSubscriptionResolver) Instance(ctx context.Context, obj *model.MessageSubscription) (*model.Instance, error) {
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// Message is the resolver for the message field.
+func (r *messageSubscriptionResolver) Message(ctx context.Context, obj *model.MessageSubscription) (*model.Message, error) {
+	if obj.MessageKey == nil {
+		return nil, nil
+	}
+
+	// Messages published before we started consuming aren't stored
+	dbMessage, err := r.Fetcher.GetMessage(ctx, *obj.MessageKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message: %w", err)
+	}
+
+	return model.FromStorageMessage(dbMessage), nil
+}
+
+// RetryDeadLetter is the resolver for the retryDeadLetter field.
+func (r *mutationResolver) RetryDeadLetter(ctx context.Context, id int64) (bool, error) {
+	applied, err := r.DeadLetters.Retry(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to retry dead letter: %w", err)
+	}
+
+	return applied, nil
+}
+
+// DiscardDeadLetter is the resolver for the discardDeadLetter field.
+func (r *mutationResolver) DiscardDeadLetter(ctx context.Context, id int64) (bool, error) {
+	err := r.DeadLetters.Discard(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to discard dead letter: %w", err)
+	}
+
+	return true, nil
+}
+
+// RetryPendingRecord is the resolver for the retryPendingRecord field.
+func (r *mutationResolver) RetryPendingRecord(ctx context.Context, id int64) (bool, error) {
+	applied, err := r.PendingRecords.Retry(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to retry pending record: %w", err)
+	}
+
+	return applied, nil
+}
+
+// DiscardPendingRecord is the resolver for the discardPendingRecord field.
+func (r *mutationResolver) DiscardPendingRecord(ctx context.Context, id int64) (bool, error) {
+	err := r.PendingRecords.Discard(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to discard pending record: %w", err)
+	}
+
+	return true, nil
+}
+
 // BpmnResource is the resolver for the bpmnResource field.
 func (r *processResolver) BpmnResource(ctx context.Context, obj *model.Process) (string, error) {
 	dbBpmnResource, err := r.Fetcher.GetBpmnResource(ctx, obj.ProcessKey)
@@ -120,6 +519,32 @@ func (r *processResolver) Instances(ctx context.Context, obj *model.Process, pag
 	}, nil
 }
 
+// Timers is the resolver for the timers field.
+func (r *processResolver) Timers(ctx context.Context, obj *model.Process, pagination *model.Pagination) (*model.PaginatedTimers, error) {
+	dbTimers, err := r.Fetcher.GetTimersForProcess(ctx, model.ToStoragePagination(pagination), obj.ProcessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timers: %w", err)
+	}
+
+	return &model.PaginatedTimers{
+		Items:      model.Map(dbTimers.Items, model.FromStorageTimer),
+		TotalCount: dbTimers.TotalCount,
+	}, nil
+}
+
+// SignalSubscriptions is the resolver for the signalSubscriptions field.
+func (r *processResolver) SignalSubscriptions(ctx context.Context, obj *model.Process, pagination *model.Pagination) (*model.PaginatedSignalSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetSignalSubscriptionsForProcess(ctx, model.ToStoragePagination(pagination), obj.ProcessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signal subscriptions: %w", err)
+	}
+
+	return &model.PaginatedSignalSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageSignalSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
 // Processes is the resolver for the processes field.
 func (r *queryResolver) Processes(ctx context.Context, pagination *model.Pagination) (*model.PaginatedProcesses, error) {
 	dbProcesses, err := r.Fetcher.GetProcesses(ctx, model.ToStoragePagination(pagination))
@@ -166,6 +591,29 @@ func (r *queryResolver) Instance(ctx context.Context, instanceKey int64) (*model
 	return model.FromStorageInstance(dbInstance), nil
 }
 
+// Deployments is the resolver for the deployments field.
+func (r *queryResolver) Deployments(ctx context.Context, pagination *model.Pagination) (*model.PaginatedDeployments, error) {
+	dbDeployments, err := r.Fetcher.GetDeployments(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployments: %w", err)
+	}
+
+	return &model.PaginatedDeployments{
+		Items:      model.Map(dbDeployments.Items, model.FromStorageDeployment),
+		TotalCount: dbDeployments.TotalCount,
+	}, nil
+}
+
+// Deployment is the resolver for the deployment field.
+func (r *queryResolver) Deployment(ctx context.Context, deploymentKey int64) (*model.Deployment, error) {
+	dbDeployment, err := r.Fetcher.GetDeployment(ctx, deploymentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch deployment: %w", err)
+	}
+
+	return model.FromStorageDeployment(dbDeployment), nil
+}
+
 // Incidents is the resolver for the incidents field.
 func (r *queryResolver) Incidents(ctx context.Context, pagination *model.Pagination) (*model.PaginatedIncidents, error) {
 	dbIncidents, err := r.Fetcher.GetIncidents(ctx, model.ToStoragePagination(pagination))
@@ -192,6 +640,358 @@ func (r *queryResolver) Jobs(ctx context.Context, pagination *model.Pagination)
 	}, nil
 }
 
+// Rejections is the resolver for the rejections field.
+func (r *queryResolver) Rejections(ctx context.Context, pagination *model.Pagination, filter *model.RejectionFilter) (*model.PaginatedRejections, error) {
+	dbRejections, err := r.Fetcher.GetRejections(ctx,
+		model.ToStoragePagination(pagination),
+		model.RejectionFilterToStorageFilter(filter),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rejections: %w", err)
+	}
+
+	return &model.PaginatedRejections{
+		Items:      model.Map(dbRejections.Items, model.FromStorageRejection),
+		TotalCount: dbRejections.TotalCount,
+	}, nil
+}
+
+// Messages is the resolver for the messages field.
+func (r *queryResolver) Messages(ctx context.Context, pagination *model.Pagination, filter *model.MessageFilter) (*model.PaginatedMessages, error) {
+	dbMessages, err := r.Fetcher.GetMessages(ctx,
+		model.ToStoragePagination(pagination),
+		model.MessageFilterToStorageFilter(filter),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	return &model.PaginatedMessages{
+		Items:      model.Map(dbMessages.Items, model.FromStorageMessage),
+		TotalCount: dbMessages.TotalCount,
+	}, nil
+}
+
+// MessageSubscriptions is the resolver for the messageSubscriptions field.
+func (r *queryResolver) MessageSubscriptions(ctx context.Context, pagination *model.Pagination) (*model.PaginatedMessageSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetMessageSubscriptions(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message subscriptions: %w", err)
+	}
+
+	return &model.PaginatedMessageSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageMessageSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
+// Timers is the resolver for the timers field.
+func (r *queryResolver) Timers(ctx context.Context, pagination *model.Pagination) (*model.PaginatedTimers, error) {
+	dbTimers, err := r.Fetcher.GetTimers(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timers: %w", err)
+	}
+
+	return &model.PaginatedTimers{
+		Items:      model.Map(dbTimers.Items, model.FromStorageTimer),
+		TotalCount: dbTimers.TotalCount,
+	}, nil
+}
+
+// UserTasks is the resolver for the userTasks field.
+func (r *queryResolver) UserTasks(ctx context.Context, pagination *model.Pagination, filter *model.UserTaskFilter) (*model.PaginatedUserTasks, error) {
+	dbUserTasks, err := r.Fetcher.GetUserTasks(ctx, model.ToStoragePagination(pagination), model.UserTaskFilterToStorageFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user tasks: %w", err)
+	}
+
+	return &model.PaginatedUserTasks{
+		Items:      model.Map(dbUserTasks.Items, model.FromStorageUserTask),
+		TotalCount: dbUserTasks.TotalCount,
+	}, nil
+}
+
+// UserTask is the resolver for the userTask field.
+func (r *queryResolver) UserTask(ctx context.Context, userTaskKey int64) (*model.UserTask, error) {
+	dbUserTask, err := r.Fetcher.GetUserTask(ctx, userTaskKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user task: %w", err)
+	}
+
+	return model.FromStorageUserTask(dbUserTask), nil
+}
+
+// Signals is the resolver for the signals field.
+func (r *queryResolver) Signals(ctx context.Context, pagination *model.Pagination) (*model.PaginatedSignals, error) {
+	dbSignals, err := r.Fetcher.GetSignals(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signals: %w", err)
+	}
+
+	return &model.PaginatedSignals{
+		Items:      model.Map(dbSignals.Items, model.FromStorageSignal),
+		TotalCount: dbSignals.TotalCount,
+	}, nil
+}
+
+// SignalSubscriptions is the resolver for the signalSubscriptions field.
+func (r *queryResolver) SignalSubscriptions(ctx context.Context, pagination *model.Pagination) (*model.PaginatedSignalSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetSignalSubscriptions(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signal subscriptions: %w", err)
+	}
+
+	return &model.PaginatedSignalSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageSignalSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
+// Errors is the resolver for the errors field.
+func (r *queryResolver) Errors(ctx context.Context, pagination *model.Pagination, instanceKey *int64) (*model.PaginatedBrokerErrors, error) {
+	fetcher := r.Fetcher.GetBrokerErrors
+	if instanceKey != nil {
+		fetcher = func(ctx context.Context, pagination *storage.Pagination) (storage.Paginated[storage.BrokerError], error) {
+			return r.Fetcher.GetBrokerErrorsForInstance(ctx, pagination, *instanceKey)
+		}
+	}
+
+	dbBrokerErrors, err := fetcher(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch errors: %w", err)
+	}
+
+	return &model.PaginatedBrokerErrors{
+		Items:      model.Map(dbBrokerErrors.Items, model.FromStorageBrokerError),
+		TotalCount: dbBrokerErrors.TotalCount,
+	}, nil
+}
+
+// DecisionRequirements is the resolver for the decisionRequirements field.
+func (r *queryResolver) DecisionRequirements(ctx context.Context, pagination *model.Pagination) (*model.PaginatedDecisionRequirements, error) {
+	dbDecisionRequirements, err := r.Fetcher.GetAllDecisionRequirements(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision requirements: %w", err)
+	}
+
+	return &model.PaginatedDecisionRequirements{
+		Items:      model.Map(dbDecisionRequirements.Items, model.FromStorageDecisionRequirements),
+		TotalCount: dbDecisionRequirements.TotalCount,
+	}, nil
+}
+
+// Decisions is the resolver for the decisions field.
+func (r *queryResolver) Decisions(ctx context.Context, pagination *model.Pagination) (*model.PaginatedDecisions, error) {
+	dbDecisions, err := r.Fetcher.GetDecisions(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decisions: %w", err)
+	}
+
+	return &model.PaginatedDecisions{
+		Items:      model.Map(dbDecisions.Items, model.FromStorageDecision),
+		TotalCount: dbDecisions.TotalCount,
+	}, nil
+}
+
+// Decision is the resolver for the decision field.
+func (r *queryResolver) Decision(ctx context.Context, decisionKey int64) (*model.Decision, error) {
+	dbDecision, err := r.Fetcher.GetDecision(ctx, decisionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision: %w", err)
+	}
+
+	return model.FromStorageDecision(dbDecision), nil
+}
+
+// DecisionEvaluations is the resolver for the decisionEvaluations field.
+func (r *queryResolver) DecisionEvaluations(ctx context.Context, pagination *model.Pagination) (*model.PaginatedDecisionEvaluations, error) {
+	dbEvaluations, err := r.Fetcher.GetDecisionEvaluations(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch decision evaluations: %w", err)
+	}
+
+	return &model.PaginatedDecisionEvaluations{
+		Items:      model.Map(dbEvaluations.Items, model.FromStorageDecisionEvaluation),
+		TotalCount: dbEvaluations.TotalCount,
+	}, nil
+}
+
+// Workers is the resolver for the workers field.
+func (r *queryResolver) Workers(ctx context.Context, pagination *model.Pagination) (*model.PaginatedWorkers, error) {
+	dbWorkers, err := r.Fetcher.GetWorkers(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch workers: %w", err)
+	}
+
+	return &model.PaginatedWorkers{
+		Items:      model.Map(dbWorkers.Items, model.FromStorageWorker),
+		TotalCount: dbWorkers.TotalCount,
+	}, nil
+}
+
+// DeadLetters is the resolver for the deadLetters field.
+func (r *queryResolver) DeadLetters(ctx context.Context, pagination *model.Pagination) (*model.PaginatedDeadLetters, error) {
+	dbDeadLetters, err := r.Fetcher.GetDeadLetters(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dead letters: %w", err)
+	}
+
+	return &model.PaginatedDeadLetters{
+		Items:      model.Map(dbDeadLetters.Items, model.FromStorageDeadLetter),
+		TotalCount: dbDeadLetters.TotalCount,
+	}, nil
+}
+
+// PendingRecords is the resolver for the pendingRecords field.
+func (r *queryResolver) PendingRecords(ctx context.Context, pagination *model.Pagination) (*model.PaginatedPendingRecords, error) {
+	dbPendingRecords, err := r.Fetcher.GetPendingRecords(ctx, model.ToStoragePagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending records: %w", err)
+	}
+
+	return &model.PaginatedPendingRecords{
+		Items:      model.Map(dbPendingRecords.Items, model.FromStoragePendingRecord),
+		TotalCount: dbPendingRecords.TotalCount,
+	}, nil
+}
+
+// Instance is the resolver for the instance field.
+func (r *rejectionResolver) Instance(ctx context.Context, obj *model.Rejection) (*model.Instance, error) {
+	// Commands such as deployments aren't related to any instance
+	if obj.InstanceKey <= 0 {
+		return nil, nil
+	}
+
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// Subscriptions is the resolver for the subscriptions field.
+func (r *signalResolver) Subscriptions(ctx context.Context, obj *model.Signal, pagination *model.Pagination) (*model.PaginatedSignalSubscriptions, error) {
+	dbSubscriptions, err := r.Fetcher.GetSignalSubscriptionsForName(ctx, model.ToStoragePagination(pagination), obj.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signal subscriptions: %w", err)
+	}
+
+	return &model.PaginatedSignalSubscriptions{
+		Items:      model.Map(dbSubscriptions.Items, model.FromStorageSignalSubscription),
+		TotalCount: dbSubscriptions.TotalCount,
+	}, nil
+}
+
+// Process is the resolver for the process field.
+func (r *signalSubscriptionResolver) Process(ctx context.Context, obj *model.SignalSubscription) (*model.Process, error) {
+	dbProcess, err := r.Fetcher.GetProcess(ctx, obj.ProcessKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch process: %w", err)
+	}
+
+	return model.FromStorageProcess(dbProcess), nil
+}
+
+// Instance is the resolver for the instance field.
+func (r *timerResolver) Instance(ctx context.Context, obj *model.Timer) (*model.Instance, error) {
+	// Timers of timer start events don't belong to an instance
+	if obj.InstanceKey <= 0 {
+		return nil, nil
+	}
+
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// Instance is the resolver for the instance field.
+func (r *userTaskResolver) Instance(ctx context.Context, obj *model.UserTask) (*model.Instance, error) {
+	dbInstance, err := r.Fetcher.GetInstance(ctx, obj.InstanceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance: %w", err)
+	}
+
+	return model.FromStorageInstance(dbInstance), nil
+}
+
+// ElementInstance is the resolver for the elementInstance field.
+func (r *variableResolver) ElementInstance(ctx context.Context, obj *model.Variable) (*model.ElementInstance, error) {
+	// Variables stored before scopes were tracked have no scope
+	if obj.ScopeKey <= 0 {
+		return nil, nil
+	}
+
+	dbElementInstance, err := r.Fetcher.GetElementInstance(ctx, obj.ScopeKey)
+	if storage.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch element instance: %w", err)
+	}
+
+	return model.FromStorageElementInstance(dbElementInstance), nil
+}
+
+// History is the resolver for the history field.
+func (r *variableResolver) History(ctx context.Context, obj *model.Variable, pagination *model.Pagination) (*model.PaginatedVariableChanges, error) {
+	// Variables stored before scopes were tracked have no history
+	if obj.ScopeKey <= 0 {
+		return &model.PaginatedVariableChanges{Items: []*model.VariableChange{}}, nil
+	}
+
+	dbChanges, err := r.Fetcher.GetVariableHistory(ctx, model.ToStoragePagination(pagination), obj.ScopeKey, obj.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variable history: %w", err)
+	}
+
+	return &model.PaginatedVariableChanges{
+		Items:      model.Map(dbChanges.Items, model.FromStorageVariableChange),
+		TotalCount: dbChanges.TotalCount,
+	}, nil
+}
+
+// JobTypes is the resolver for the jobTypes field.
+func (r *workerResolver) JobTypes(ctx context.Context, obj *model.Worker) ([]string, error) {
+	jobTypes, err := r.Fetcher.GetWorkerJobTypes(ctx, obj.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job types: %w", err)
+	}
+
+	return jobTypes, nil
+}
+
+// BrokerError returns BrokerErrorResolver implementation.
+func (r *Resolver) BrokerError() BrokerErrorResolver { return &brokerErrorResolver{r} }
+
+// Decision returns DecisionResolver implementation.
+func (r *Resolver) Decision() DecisionResolver { return &decisionResolver{r} }
+
+// DecisionEvaluation returns DecisionEvaluationResolver implementation.
+func (r *Resolver) DecisionEvaluation() DecisionEvaluationResolver {
+	return &decisionEvaluationResolver{r}
+}
+
+// DecisionRequirements returns DecisionRequirementsResolver implementation.
+func (r *Resolver) DecisionRequirements() DecisionRequirementsResolver {
+	return &decisionRequirementsResolver{r}
+}
+
+// Deployment returns DeploymentResolver implementation.
+func (r *Resolver) Deployment() DeploymentResolver { return &deploymentResolver{r} }
+
+// ElementInstance returns ElementInstanceResolver implementation.
+func (r *Resolver) ElementInstance() ElementInstanceResolver { return &elementInstanceResolver{r} }
+
 // Incident returns IncidentResolver implementation.
 func (r *Resolver) Incident() IncidentResolver { return &incidentResolver{r} }
 
@@ -201,14 +1001,64 @@ func (r *Resolver) Instance() InstanceResolver { return &instanceResolver{r} }
 // Job returns JobResolver implementation.
 func (r *Resolver) Job() JobResolver { return &jobResolver{r} }
 
+// Message returns MessageResolver implementation.
+func (r *Resolver) Message() MessageResolver { return &messageResolver{r} }
+
+// MessageSubscription returns MessageSubscriptionResolver implementation.
+func (r *Resolver) MessageSubscription() MessageSubscriptionResolver {
+	return &messageSubscriptionResolver{r}
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
 // Process returns ProcessResolver implementation.
 func (r *Resolver) Process() ProcessResolver { return &processResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Rejection returns RejectionResolver implementation.
+func (r *Resolver) Rejection() RejectionResolver { return &rejectionResolver{r} }
+
+// Signal returns SignalResolver implementation.
+func (r *Resolver) Signal() SignalResolver { return &signalResolver{r} }
+
+// SignalSubscription returns SignalSubscriptionResolver implementation.
+func (r *Resolver) SignalSubscription() SignalSubscriptionResolver {
+	return &signalSubscriptionResolver{r}
+}
+
+// Timer returns TimerResolver implementation.
+func (r *Resolver) Timer() TimerResolver { return &timerResolver{r} }
+
+// UserTask returns UserTaskResolver implementation.
+func (r *Resolver) UserTask() UserTaskResolver { return &userTaskResolver{r} }
+
+// Variable returns VariableResolver implementation.
+func (r *Resolver) Variable() VariableResolver { return &variableResolver{r} }
+
+// Worker returns WorkerResolver implementation.
+func (r *Resolver) Worker() WorkerResolver { return &workerResolver{r} }
+
+type brokerErrorResolver struct{ *Resolver }
+type decisionResolver struct{ *Resolver }
+type decisionEvaluationResolver struct{ *Resolver }
+type decisionRequirementsResolver struct{ *Resolver }
+type deploymentResolver struct{ *Resolver }
+type elementInstanceResolver struct{ *Resolver }
 type incidentResolver struct{ *Resolver }
 type instanceResolver struct{ *Resolver }
 type jobResolver struct{ *Resolver }
+type messageResolver struct{ *Resolver }
+type messageSubscriptionResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
 type processResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type rejectionResolver struct{ *Resolver }
+type signalResolver struct{ *Resolver }
+type signalSubscriptionResolver struct{ *Resolver }
+type timerResolver struct{ *Resolver }
+type userTaskResolver struct{ *Resolver }
+type variableResolver struct{ *Resolver }
+type workerResolver struct{ *Resolver }
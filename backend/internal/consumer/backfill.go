@@ -0,0 +1,144 @@
+package consumer
+
+import (
+	"log"
+	"sort"
+	"sync"
+)
+
+// BackfillMode defines whether the consumer rebuilds storage from the oldest
+// records still retained by Kafka on startup, and what it does afterwards.
+type BackfillMode string
+
+const (
+	// Don't backfill, only resume from the committed offsets.
+	BackfillNone BackfillMode = ""
+	// Backfill and keep consuming new records afterwards.
+	BackfillTail BackfillMode = "tail"
+	// Backfill and stop once every partition has caught up.
+	BackfillExit BackfillMode = "exit"
+)
+
+// Range of offsets a partition needs to be applied over for the backfill to
+// be done with it.
+type backfillTarget struct {
+	// Oldest offset available when the backfill started.
+	start int64
+	// High-water mark when the backfill started.
+	end int64
+}
+
+// Keeps track of a running backfill. Safe to use from multiple goroutines.
+type backfill struct {
+	mutex   sync.Mutex
+	targets map[topicPartition]backfillTarget
+
+	done        bool
+	doneChannel signalChannelType
+}
+
+func newBackfill() *backfill {
+	return &backfill{
+		targets:     map[topicPartition]backfillTarget{},
+		doneChannel: make(signalChannelType),
+	}
+}
+
+// Returns whether a partition still needs to be added to the backfill. Once
+// the backfill is done no new partitions are added to it.
+func (b *backfill) needsTarget(topic string, partition int32) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, ok := b.targets[topicPartition{topic, partition}]
+	return !b.done && !ok
+}
+
+// Add a partition to the backfill.
+func (b *backfill) addTarget(topic string, partition int32, start int64, end int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.targets[topicPartition{topic, partition}] = backfillTarget{start, end}
+}
+
+// Check whether every partition has been applied up to its target and mark
+// the backfill done if so.
+func (b *backfill) check(progress []PartitionProgress) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.done {
+		return true
+	}
+
+	applied := map[topicPartition]int64{}
+	for _, p := range progress {
+		applied[topicPartition{p.Topic, p.Partition}] = p.AppliedOffset
+	}
+
+	for key, target := range b.targets {
+		appliedOffset, ok := applied[key]
+		if target.end > target.start && (!ok || appliedOffset < target.end-1) {
+			return false
+		}
+	}
+
+	b.done = true
+	close(b.doneChannel)
+	return true
+}
+
+// Progress of a backfill over all partitions of a topic.
+type topicBackfillProgress struct {
+	topic string
+	// Number of records applied out of `total`.
+	applied int64
+	total   int64
+}
+
+// Sum up the progress of the backfill per topic, ordered by topic.
+func (b *backfill) topicProgress(progress []PartitionProgress) []topicBackfillProgress {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	applied := map[topicPartition]int64{}
+	for _, p := range progress {
+		applied[topicPartition{p.Topic, p.Partition}] = p.AppliedOffset
+	}
+
+	perTopic := map[string]*topicBackfillProgress{}
+	for key, target := range b.targets {
+		topicProgress, ok := perTopic[key.topic]
+		if !ok {
+			topicProgress = &topicBackfillProgress{topic: key.topic}
+			perTopic[key.topic] = topicProgress
+		}
+
+		topicProgress.total += target.end - target.start
+		if appliedOffset, ok := applied[key]; ok {
+			// Clamp to the target range; records produced after the
+			// backfill started don't count towards it
+			appliedUntil := min(max(appliedOffset+1, target.start), target.end)
+			topicProgress.applied += appliedUntil - target.start
+		}
+	}
+
+	result := make([]topicBackfillProgress, 0, len(perTopic))
+	for _, topicProgress := range perTopic {
+		result = append(result, *topicProgress)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].topic < result[j].topic
+	})
+
+	return result
+}
+
+// Log the progress of the backfill for each topic.
+func (b *backfill) logProgress(progress []PartitionProgress) {
+	for _, topicProgress := range b.topicProgress(progress) {
+		log.Printf("[%s] Backfill applied %d/%d records",
+			topicProgress.topic, topicProgress.applied, topicProgress.total)
+	}
+}
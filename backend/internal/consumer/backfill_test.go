@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfill(t *testing.T) {
+	b := newBackfill()
+	tracker := newProgressTracker()
+
+	// A partition with records, one that is empty and one whose oldest
+	// records have been removed by retention
+	b.addTarget("zeebe-job", 0, 0, 10)
+	tracker.started("zeebe-job", 0, 0, 10)
+	b.addTarget("zeebe-job", 1, 5, 5)
+	tracker.started("zeebe-job", 1, 5, 5)
+	b.addTarget("zeebe-variable", 0, 20, 30)
+	tracker.started("zeebe-variable", 0, 20, 30)
+
+	t.Run("needs target", func(t *testing.T) {
+		assert.False(t, b.needsTarget("zeebe-job", 0))
+		assert.True(t, b.needsTarget("zeebe-job", 2))
+	})
+
+	t.Run("not done initially", func(t *testing.T) {
+		assert.False(t, b.check(tracker.snapshot()))
+
+		progress := b.topicProgress(tracker.snapshot())
+		assert.Equal(t, []topicBackfillProgress{
+			{topic: "zeebe-job", applied: 0, total: 10},
+			{topic: "zeebe-variable", applied: 0, total: 10},
+		}, progress)
+	})
+
+	t.Run("partially applied", func(t *testing.T) {
+		tracker.applied("zeebe-job", 0, 9)
+		tracker.applied("zeebe-variable", 0, 24)
+		assert.False(t, b.check(tracker.snapshot()))
+
+		progress := b.topicProgress(tracker.snapshot())
+		assert.Equal(t, []topicBackfillProgress{
+			{topic: "zeebe-job", applied: 10, total: 10},
+			{topic: "zeebe-variable", applied: 5, total: 10},
+		}, progress)
+	})
+
+	t.Run("done", func(t *testing.T) {
+		// Records newer than the target don't count towards progress
+		tracker.applied("zeebe-variable", 0, 35)
+		assert.True(t, b.check(tracker.snapshot()))
+
+		progress := b.topicProgress(tracker.snapshot())
+		assert.Equal(t, int64(10), progress[1].applied)
+
+		select {
+		case <-b.doneChannel:
+		default:
+			assert.Fail(t, "done channel not closed")
+		}
+	})
+
+	t.Run("no new targets once done", func(t *testing.T) {
+		assert.False(t, b.needsTarget("zeebe-job", 2))
+	})
+}
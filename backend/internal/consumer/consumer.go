@@ -0,0 +1,251 @@
+package consumer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/environment"
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+// Raw record read from a topic partition. The origin of the record is kept
+// alongside it so that its offset can be committed once the record has been
+// applied.
+type message struct {
+	topic     string
+	partition int32
+	offset    int64
+	value     []byte
+	// Encoding of the value, detected when parsing if not known.
+	encoding RecordEncoding
+}
+
+// RecordEncoding defines how raw records are encoded.
+type RecordEncoding string
+
+const (
+	// Detect the encoding of each record.
+	RecordEncodingAuto RecordEncoding = ""
+	// Records are JSON, as written by e.g. the Kafka and file exporters.
+	RecordEncodingJSON RecordEncoding = "json"
+	// Records are protobuf messages of Zeebe's exporter protocol.
+	RecordEncodingProtobuf RecordEncoding = "protobuf"
+)
+
+// Detect the encoding of a raw record. JSON records are objects, while
+// protobuf records start with a length-delimited field 1, which is never '{'.
+func detectEncoding(value []byte) RecordEncoding {
+	if trimmed := bytes.TrimLeft(value, " \t\r\n"); len(trimmed) != 0 && trimmed[0] == '{' {
+		return RecordEncodingJSON
+	}
+
+	return RecordEncodingProtobuf
+}
+
+type msgChannelType = chan message
+type signalChannelType = chan struct{}
+
+type listenOnlyMsgChannel = <-chan message
+type listenOnlySignalChannel = <-chan struct{}
+
+// Source reads raw Zeebe records from somewhere and sends them to the storage
+// updater. Records are read from Kafka when running normally, but they can
+// also be imported from files.
+//
+// Records are grouped by topic, and each topic is applied in order in a
+// goroutine of its own. Sources which don't have topics of their own can use
+// any names for them, e.g. file names.
+type Source interface {
+	// Topics records are read from and the value types on each of them.
+	topics() Topics
+	// Start reading records and sending them to the sink. Reading goes on
+	// in the background until the sink is closed.
+	start(sink *sink) error
+	// Returns a channel which is closed once the source has caught up,
+	// i.e. every record it had when it was started has been applied.
+	done() <-chan struct{}
+	// Wait for the background reading to stop after the sink has been
+	// closed and release any resources held by the source.
+	close() error
+}
+
+// Everything a source needs to pass records on to the storage updater.
+type sink struct {
+	storer   storage.Storer
+	progress *progressTracker
+
+	// Channel of each topic of the source.
+	msgChannels map[string]msgChannelType
+	// Closed when the consumer is closed.
+	closeChannel listenOnlySignalChannel
+}
+
+// Send a record to be applied. Blocks until the storage updater takes the
+// record and returns false if the consumer was closed before that.
+func (s *sink) send(msg message) bool {
+	select {
+	case s.msgChannels[msg.topic] <- msg:
+		return true
+	case <-s.closeChannel:
+		return false
+	}
+}
+
+// Consumer reads Zeebe records from a source and passes them on to storage.
+type Consumer struct {
+	source Source
+
+	storageUpdater *storageUpdater
+	progress       *progressTracker
+	deadLetters    *DeadLetterQueue
+
+	msgChannels  map[string]msgChannelType
+	closeChannel chan struct{}
+
+	wg *sync.WaitGroup
+}
+
+// Create a new Kafka consumer from environment variables.
+func NewFromEnv(storer storage.Storer, maxRetries int, retryDelay time.Duration) (*Consumer, error) {
+	topics := DefaultTopics(environment.KafkaTopicPrefix())
+	if configured := environment.KafkaTopics(); len(configured) != 0 {
+		topics = Topics{}
+		for topic, valueTypes := range configured {
+			topics[topic] = []ValueType{}
+			for _, valueType := range valueTypes {
+				topics[topic] = append(topics[topic], ValueType(valueType))
+			}
+		}
+	}
+
+	conf := Config{
+		Brokers:  []string{environment.KafkaAddress()},
+		Topics:   topics,
+		Backfill: BackfillMode(environment.Backfill()),
+		Encoding: RecordEncoding(environment.RecordEncoding()),
+	}
+
+	return NewConsumer(storer, conf, maxRetries, retryDelay)
+}
+
+// Create a new Kafka consumer.
+func NewConsumer(storer storage.Storer, conf Config, maxRetries int, retryDelay time.Duration) (*Consumer, error) {
+	// wrap NewFromSource with retry handling
+	var err error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		var kafkaConsumer *Consumer
+		kafkaConsumer, err = NewFromSource(storer, NewKafkaSource(conf))
+		if err == nil {
+			return kafkaConsumer, nil
+		}
+		time.Sleep(retryDelay)
+	}
+	return nil, fmt.Errorf("maximum number of retries reached: %w", err)
+}
+
+// Create a new consumer applying the records read from `source` to storage.
+func NewFromSource(storer storage.Storer, source Source) (*Consumer, error) {
+	topics := source.topics()
+
+	msgChannels := map[string]msgChannelType{}
+	for topic := range topics {
+		// The optimal buffer size is an open question; it could be as low as 0
+		// if we're okay yielding the consumer goroutine whenever we get to
+		// that point
+		bufSize := 10
+		msgChannel := make(msgChannelType, bufSize)
+		msgChannels[topic] = msgChannel
+	}
+
+	closeChannel := make(signalChannelType)
+
+	var wg sync.WaitGroup
+
+	progress := newProgressTracker()
+	storageUpdater := newDatabaseUpdater(storer, progress, topics, msgChannels, closeChannel, &wg)
+
+	result := Consumer{
+		source: source,
+
+		storageUpdater: storageUpdater,
+		progress:       progress,
+		deadLetters:    newDeadLetterQueue(storageUpdater),
+
+		msgChannels:  msgChannels,
+		closeChannel: closeChannel,
+
+		wg: &wg,
+	}
+
+	err := source.start(&sink{
+		storer:   storer,
+		progress: progress,
+
+		msgChannels:  msgChannels,
+		closeChannel: closeChannel,
+	})
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result.retryDeadLetters()
+	}()
+
+	return &result, nil
+}
+
+// Periodically retry the dead letters until the consumer is closed.
+func (consumer *Consumer) retryDeadLetters() {
+	ticker := time.NewTicker(DeadLetterRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-consumer.closeChannel:
+			return
+		case <-ticker.C:
+			applied, err := consumer.deadLetters.RetryAll(MaxDeadLetterAttempts)
+			if err != nil {
+				log.Printf("Failed to retry dead letters: %v", err)
+			}
+			if applied != 0 {
+				log.Printf("Applied %d dead letters", applied)
+			}
+		}
+	}
+}
+
+// DeadLetters returns the dead letter queue of the consumer. Retrying dead
+// letters through it releases the records the consumer has parked.
+func (consumer *Consumer) DeadLetters() *DeadLetterQueue {
+	return consumer.deadLetters
+}
+
+// Progress returns the progress of every partition being consumed.
+func (consumer *Consumer) Progress() []PartitionProgress {
+	return consumer.progress.snapshot()
+}
+
+// Done returns a channel which is closed once the source has caught up. For
+// Kafka this is when the backfill is done, or right away if not backfilling.
+// For files it's when every record in them has been applied.
+func (consumer *Consumer) Done() <-chan struct{} {
+	return consumer.source.done()
+}
+
+// Close stops reading from the source and stops the storage updater.
+func (consumer *Consumer) Close() error {
+	// Sending a message to closeChannel would just close *one* goroutine -
+	// closing it will make all goroutines read a nil from it instead.
+	close(consumer.closeChannel)
+	consumer.wg.Wait()
+
+	return consumer.source.close()
+}
@@ -0,0 +1,119 @@
+package consumer
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+const (
+	// How often dead letters are retried automatically.
+	DeadLetterRetryInterval = time.Minute
+	// Dead letters are retried automatically until they've been attempted
+	// this many times. They can still be retried manually after that.
+	MaxDeadLetterAttempts = 5
+)
+
+// DeadLetterQueue retries and discards records which failed to be applied.
+//
+// Records often fail because they arrived before the records they depend
+// on, so retrying them later is likely to succeed.
+type DeadLetterQueue struct {
+	storer  storage.Storer
+	updater *storageUpdater
+}
+
+// Create a new dead letter queue which applies retried records to storage
+// through `storer`.
+func NewDeadLetterQueue(storer storage.Storer) *DeadLetterQueue {
+	// Only used for applying records, so no goroutines are needed
+	return newDeadLetterQueue(&storageUpdater{
+		storer:     storer,
+		reconciler: newReconciler(),
+	})
+}
+
+// Create a dead letter queue which applies retried records through
+// `updater`, so that records it has parked are released when a retried
+// record creates the row they're waiting for.
+func newDeadLetterQueue(updater *storageUpdater) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		storer:  updater.storer,
+		updater: updater,
+	}
+}
+
+// Retry applying a dead letter. Returns whether applying succeeded. On
+// success the dead letter is removed, otherwise its attempt count goes up.
+func (q *DeadLetterQueue) Retry(id int64) (bool, error) {
+	deadLetter, err := q.storer.DeadLetter(id)
+	if err != nil {
+		return false, err
+	}
+
+	return q.retry(deadLetter)
+}
+
+// Retry applying every dead letter attempted fewer than `maxAttempts` times.
+// Returns the number of dead letters applied successfully.
+func (q *DeadLetterQueue) RetryAll(maxAttempts int64) (int, error) {
+	deadLetters, err := q.storer.DeadLettersToRetry(maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, deadLetter := range deadLetters {
+		ok, err := q.retry(deadLetter)
+		if err != nil {
+			return applied, err
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// Discard a dead letter without applying it.
+func (q *DeadLetterQueue) Discard(id int64) error {
+	return q.storer.DeadLetterDiscarded(id)
+}
+
+func (q *DeadLetterQueue) retry(deadLetter storage.DeadLetter) (bool, error) {
+	untypedRecord, err := parseRecord(deadLetter.Payload)
+	if err == nil {
+		err = q.updater.applyInTransaction(untypedRecord)
+	}
+
+	if err != nil {
+		log.Printf("Retrying dead letter %d failed: %v", deadLetter.ID, err)
+		err = q.storer.DeadLetterStored(
+			deadLetter.Topic,
+			deadLetter.Partition,
+			deadLetter.Offset,
+			deadLetter.Payload,
+			err.Error(),
+			time.Now(),
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to update dead letter: %w", err)
+		}
+		return false, nil
+	}
+
+	log.Printf("Retrying dead letter %d succeeded", deadLetter.ID)
+	err = q.storer.DeadLetterDiscarded(deadLetter.ID)
+	if err != nil {
+		return true, fmt.Errorf("failed to remove dead letter: %w", err)
+	}
+
+	if createsRow(untypedRecord) {
+		q.updater.release(rowKey(untypedRecord))
+	}
+
+	return true, nil
+}
@@ -0,0 +1,116 @@
+package consumer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Storer returning a fixed dead letter.
+type deadLetterStorer struct {
+	*fixedErrStorer
+	deadLetter storage.DeadLetter
+}
+
+func (s *deadLetterStorer) DeadLetter(int64) (storage.DeadLetter, error) {
+	s.touched["DeadLetter"] = true
+	return s.deadLetter, nil
+}
+
+func TestDeadLetterRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		applied bool
+		touched []string
+	}{
+		{
+			"Applied",
+			`{
+				"valueType": "VARIABLE",
+				"intent": "CREATED",
+				"recordType": "EVENT",
+				"timestamp": 1000,
+				"value": {
+					"processInstanceKey": 1,
+					"name": "testName",
+					"value": "testValue"
+				}
+			}`,
+			true,
+			[]string{"DeadLetter", "VariableCreated", "VariableChanged", "DeadLetterDiscarded", "PendingRecords"},
+		},
+		{
+			"Invalid",
+			`{`,
+			false,
+			[]string{"DeadLetter", "DeadLetterStored"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storer := &deadLetterStorer{
+				newFixedErrStorer(nil),
+				storage.DeadLetter{ID: 1, Payload: []byte(test.payload)},
+			}
+			queue := NewDeadLetterQueue(storer)
+
+			applied, err := queue.Retry(1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.applied, applied)
+
+			for _, touched := range test.touched {
+				assert.True(t, storer.touched[touched], touched)
+				delete(storer.touched, touched)
+			}
+			assert.Empty(t, storer.touched)
+		})
+	}
+}
+
+// Storer returning a fixed dead letter and only finding jobs which have been
+// created.
+type deadLetterJobStorer struct {
+	*jobOrderStorer
+	deadLetter storage.DeadLetter
+}
+
+func (s *deadLetterJobStorer) DeadLetter(int64) (storage.DeadLetter, error) {
+	return s.deadLetter, nil
+}
+
+func (s *deadLetterJobStorer) Transaction(fn func(storage.Storer) error) error {
+	return fn(s)
+}
+
+func TestDeadLetterRetryReleasesParkedRecords(t *testing.T) {
+	created, err := json.Marshal(newJobTestRecord("JobCreated", IntentCreated, nil, nil).record)
+	assert.NoError(t, err)
+
+	storer := &deadLetterJobStorer{
+		newJobOrderStorer(),
+		storage.DeadLetter{ID: 1, Payload: created},
+	}
+	updater := &storageUpdater{
+		storer:     storer,
+		reconciler: newReconciler(),
+	}
+
+	// The job was never created because creating it failed, so the
+	// update waits for it
+	updated := newJobTestRecord("JobCompleted", IntentCompleted, nil, nil)
+	err = updater.apply(message{}, updated.record, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, updater.reconciler.parked[updated.record.Key], 1)
+
+	applied, err := newDeadLetterQueue(updater).Retry(1)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.True(t, storer.touched["JobCreated"])
+	assert.True(t, storer.touched["JobUpdated"])
+	assert.Empty(t, updater.reconciler.parked)
+}
@@ -0,0 +1,224 @@
+package consumer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// How often an import is checked for having applied every record.
+	FileImportCheckInterval = 100 * time.Millisecond
+
+	// Prefix of the topic names of files. Kafka topic names can't contain
+	// ':', so the committed offsets of files never mix with those of
+	// Kafka topics.
+	FileTopicPrefix = "file:"
+)
+
+// Magic number at the start of every gzip file.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// fileSource reads Zeebe records from files with one JSON encoded record per
+// line, like the ones written by Zeebe's exporters. Files can be gzip
+// compressed.
+//
+// Each file is treated as a topic of its own with a single partition, the
+// offset of a record being its line number. The records of a file are thus
+// applied in order, and files can be imported again safely. Topics of files
+// are named after their paths with `FileTopicPrefix` in front.
+type fileSource struct {
+	paths []string
+
+	sink        *sink
+	doneChannel signalChannelType
+
+	wg sync.WaitGroup
+}
+
+// Create a new source reading records from the files at `paths`.
+func NewFileSource(paths ...string) Source {
+	return &fileSource{
+		paths:       paths,
+		doneChannel: make(signalChannelType),
+	}
+}
+
+func (source *fileSource) topics() Topics {
+	topics := Topics{}
+	for _, path := range source.paths {
+		// Files can contain records of any value type
+		topics[fileTopic(path)] = nil
+	}
+
+	return topics
+}
+
+// Open every file and start reading them. Fails if any of the files can't be
+// opened.
+func (source *fileSource) start(sink *sink) error {
+	source.sink = sink
+
+	readers := make([]io.ReadCloser, 0, len(source.paths))
+	for _, path := range source.paths {
+		reader, err := openRecordFile(path)
+		if err != nil {
+			for _, opened := range readers {
+				_ = opened.Close()
+			}
+			return err
+		}
+		readers = append(readers, reader)
+	}
+
+	var readWg sync.WaitGroup
+	for i, reader := range readers {
+		path := source.paths[i]
+		reader := reader
+
+		readWg.Add(1)
+		go func() {
+			defer readWg.Done()
+			defer reader.Close()
+
+			err := source.read(path, reader)
+			if err != nil {
+				log.Printf("[%s] Failed to read records: %v", path, err)
+			}
+		}()
+	}
+
+	source.wg.Add(1)
+	go func() {
+		defer source.wg.Done()
+
+		readWg.Wait()
+		source.waitApplied()
+	}()
+
+	return nil
+}
+
+// Open a record file, decompressing it if it's gzip compressed.
+func openRecordFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open record file: %w", err)
+	}
+
+	buffered := bufio.NewReader(file)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read record file: %w", err)
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return struct {
+			io.Reader
+			io.Closer
+		}{buffered, file}, nil
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to decompress record file: %w", err)
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{decompressed, file}, nil
+}
+
+// Returns the name of the topic the records of a file are read into.
+func fileTopic(path string) string {
+	return FileTopicPrefix + path
+}
+
+// Send every record in a file to the sink. Empty lines are skipped.
+func (source *fileSource) read(path string, reader io.Reader) error {
+	topic := fileTopic(path)
+	progress := source.sink.progress
+	progress.started(topic, 0, 0, 0)
+
+	// Records can be large, e.g. deployments containing whole BPMN files,
+	// so don't use a bufio.Scanner with its limited line length
+	buffered := bufio.NewReader(reader)
+	records := 0
+	for line := int64(0); ; line++ {
+		value, err := buffered.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		value = bytes.TrimSpace(value)
+		if len(value) != 0 {
+			progress.consumed(topic, 0, line, line+1)
+			ok := source.sink.send(message{
+				topic:     topic,
+				partition: 0,
+				offset:    line,
+				value:     value,
+			})
+			if !ok {
+				return nil
+			}
+			records++
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	log.Printf("[%s] Read %d records", path, records)
+	return nil
+}
+
+// Wait until every record read has been applied, then signal that the
+// source is done.
+func (source *fileSource) waitApplied() {
+	ticker := time.NewTicker(FileImportCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		caughtUp := true
+		for _, progress := range source.sink.progress.snapshot() {
+			if progress.Lag() != 0 {
+				caughtUp = false
+				break
+			}
+		}
+		if caughtUp {
+			close(source.doneChannel)
+			return
+		}
+
+		select {
+		case <-source.sink.closeChannel:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns a channel which is closed once every record in the files has been
+// applied.
+func (source *fileSource) done() <-chan struct{} {
+	return source.doneChannel
+}
+
+// Wait for reading the files to stop.
+func (source *fileSource) close() error {
+	source.wg.Wait()
+	return nil
+}
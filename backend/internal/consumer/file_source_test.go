@@ -0,0 +1,61 @@
+package consumer
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRecordFile = `{"valueType": "VARIABLE", "intent": "CREATED", "recordType": "EVENT", "key": 1, "position": 1, "value": {"processInstanceKey": 1, "name": "a", "value": "1"}}
+
+{"valueType": "VARIABLE", "intent": "UPDATED", "recordType": "EVENT", "key": 1, "position": 2, "value": {"processInstanceKey": 1, "name": "a", "value": "2"}}
+`
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+
+	plainPath := filepath.Join(dir, "records.jsonl")
+	err := os.WriteFile(plainPath, []byte(testRecordFile), 0o600)
+	assert.NoError(t, err)
+
+	gzipPath := filepath.Join(dir, "records.jsonl.gz")
+	file, err := os.Create(gzipPath)
+	assert.NoError(t, err)
+	writer := gzip.NewWriter(file)
+	_, err = writer.Write([]byte(testRecordFile))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, file.Close())
+
+	for _, path := range []string{plainPath, gzipPath} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			storer := newFixedErrStorer(nil)
+			fileConsumer, err := NewFromSource(storer, NewFileSource(path))
+			assert.NoError(t, err)
+
+			select {
+			case <-fileConsumer.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("import didn't finish")
+			}
+			assert.NoError(t, fileConsumer.Close())
+
+			assert.True(t, storer.touched["VariableCreated"])
+			assert.True(t, storer.touched["VariableUpdated"])
+
+			progress := fileConsumer.Progress()
+			assert.Len(t, progress, 1)
+			assert.Equal(t, "file:"+path, progress[0].Topic)
+			assert.Equal(t, int64(2), progress[0].AppliedOffset)
+		})
+	}
+}
+
+func TestFileSourceMissingFile(t *testing.T) {
+	_, err := NewFromSource(newFixedErrStorer(nil), NewFileSource("missing.jsonl"))
+	assert.ErrorContains(t, err, "failed to open record file")
+}
@@ -0,0 +1,106 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+// Topic name used for records pushed to the ingester, e.g. when storing them
+// as dead letters or pending records.
+const IngestTopic = "ingest"
+
+// ErrInvalidRecord is returned when a pushed record can't be parsed.
+var ErrInvalidRecord = errors.New("invalid record")
+
+// Ingester applies records pushed to us instead of consumed from Kafka, e.g.
+// by Zeebe's HTTP exporters.
+//
+// Records are applied synchronously, so once Ingest returns successfully the
+// records are in storage. Records updating rows that don't exist yet are
+// stored in the pending queue.
+type Ingester struct {
+	updater *storageUpdater
+
+	// Batches are applied one at a time so that each batch is durable
+	// by the time it's acknowledged
+	mutex sync.Mutex
+}
+
+// Create a new ingester applying records to storage through `storer`.
+func NewIngester(storer storage.Storer) *Ingester {
+	return &Ingester{
+		// Only used for applying records, so no goroutines are needed
+		updater: &storageUpdater{
+			storer:     storer,
+			reconciler: newReconciler(),
+		},
+	}
+}
+
+// Ingest applies a batch of raw records in order. Nothing is applied if any of
+// the records can't be parsed. Records that fail to apply are stored as dead
+// letters, as when consuming from Kafka, so that one bad record doesn't block
+// the ones after it. Returns the number of records stored as dead letters.
+//
+// An error is returned only if a record couldn't be stored at all; the
+// records are then safe to push again as a whole since applying a record
+// again does nothing.
+func (i *Ingester) Ingest(values [][]byte) (int, error) {
+	records := make([]*UntypedRecord, 0, len(values))
+	for index, value := range values {
+		record, err := parseRecord(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w at index %d: %w", ErrInvalidRecord, index, err)
+		}
+		if record.ValueType == "" {
+			return 0, fmt.Errorf("%w at index %d: missing value type", ErrInvalidRecord, index)
+		}
+		records = append(records, record)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	// Parked records are already in the pending queue, from where they're
+	// applied once their rows are created. Nothing retries them from memory
+	// here.
+	defer i.updater.reconciler.takeAll()
+
+	deadLetters := 0
+	for index, record := range records {
+		// Records are identified by their position, which is unique
+		// within a Zeebe partition
+		msg := message{
+			topic:     IngestTopic,
+			partition: int32(record.PartitionID),
+			offset:    record.Position,
+			value:     values[index],
+		}
+
+		err := i.updater.apply(msg, record, time.Now())
+		if err == nil {
+			continue
+		}
+
+		log.Printf("Handling ingested record failed: %v", err)
+		err = i.updater.storer.DeadLetterStored(
+			msg.topic,
+			msg.partition,
+			msg.offset,
+			msg.value,
+			err.Error(),
+			time.Now(),
+		)
+		if err != nil {
+			return deadLetters, fmt.Errorf("failed to store dead letter for record at index %d: %w", index, err)
+		}
+		deadLetters++
+	}
+
+	return deadLetters, nil
+}
@@ -0,0 +1,72 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngest(t *testing.T) {
+	created := []byte(`{"valueType": "JOB", "intent": "CREATED", "key": 5, "position": 1, "value": {}}`)
+	completed := []byte(`{"valueType": "JOB", "intent": "COMPLETED", "key": 5, "position": 2, "value": {}}`)
+
+	t.Run("apply batch", func(t *testing.T) {
+		storer := newJobOrderStorer()
+		ingester := NewIngester(storer)
+
+		deadLetters, err := ingester.Ingest([][]byte{created, completed})
+		assert.NoError(t, err)
+		assert.Zero(t, deadLetters)
+		assert.True(t, storer.touched["JobCreated"])
+		assert.True(t, storer.touched["JobUpdated"])
+		assert.False(t, storer.touched["PendingRecordStored"])
+	})
+
+	t.Run("missing row is pending", func(t *testing.T) {
+		storer := newJobOrderStorer()
+		ingester := NewIngester(storer)
+
+		_, err := ingester.Ingest([][]byte{completed})
+		assert.NoError(t, err)
+		assert.False(t, storer.touched["JobUpdated"])
+		assert.True(t, storer.touched["PendingRecordStored"])
+	})
+
+	t.Run("invalid record", func(t *testing.T) {
+		storer := newJobOrderStorer()
+		ingester := NewIngester(storer)
+
+		_, err := ingester.Ingest([][]byte{created, []byte(`{`)})
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+		assert.Empty(t, storer.touched)
+
+		_, err = ingester.Ingest([][]byte{[]byte(`{}`)})
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+		assert.Empty(t, storer.touched)
+	})
+
+	t.Run("storing pending record fails", func(t *testing.T) {
+		// The record can't be acknowledged if it isn't in storage
+		storer := newJobOrderStorer()
+		storer.err = errTest
+		ingester := NewIngester(storer)
+
+		_, err := ingester.Ingest([][]byte{completed})
+		assert.ErrorContains(t, err, errTest.Error())
+	})
+
+	t.Run("failing record is a dead letter", func(t *testing.T) {
+		// The value of a job can't be a string
+		failing := []byte(`{"valueType": "JOB", "intent": "CREATED", "key": 6, "position": 3, "value": "job"}`)
+
+		storer := newJobOrderStorer()
+		ingester := NewIngester(storer)
+
+		deadLetters, err := ingester.Ingest([][]byte{failing, created})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, deadLetters)
+		assert.True(t, storer.touched["DeadLetterStored"])
+		// Records after the failing one are still applied
+		assert.True(t, storer.touched["JobCreated"])
+	})
+}
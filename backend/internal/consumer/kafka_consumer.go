@@ -1,152 +1,373 @@
 package consumer
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
-	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
 )
 
-type msgChannelType = chan []byte
-type signalChannelType = chan struct{}
-
-type listenOnlyMsgChannel = <-chan []byte
-type listenOnlySignalChannel = <-chan struct{}
-
-// We know what topics we can handle inside this package; this does not really
-// need to be exposed on the outside
-var knownTopics = []string{
-	"zeebe-deployment",
-	"zeebe-deployment-distribution",
-	"zeebe-error",
-	"zeebe-incident",
-	"zeebe-job",
-	"zeebe-job-batch",
-	"zeebe-message",
-	"zeebe-message-subscription",
-	"zeebe-message-subscription-start-event",
-	"zeebe-process",
-	"zeebe-process-event",
-	"zeebe-process-instance",
-	"zeebe-process-instance-result",
-	"zeebe-process-message-subscription",
-	"zeebe-timer",
-	"zeebe-variable",
-}
-
-// TODO: we need a method of reconnecting when the connection drops. I'm not
-// entirely sure how to *detect* this, except perhaps by adding a condition
-// variable of some kind to the Consumer object where, upon seeing it, a
-// housekeeping goroutine would proceed to close down and restart all the
-// topics (since we keep track of every topic this would not be excessively
-// difficult). The housekeeping goroutine could also be in charge of retrying
-// and should close down when the externally visible Close method is called (so
-// we should have an internal close method it calls to clean up after
-// signalling the housekeeping goroutine to close first)
-// TODO: we likely need synchronisation on at least some of the methods of this
-// struct... or a restriction on calling any of them from outside the main
-// goroutine?
-type Consumer struct {
+const (
+	// How often the topics are checked for partitions added after we
+	// started consuming them.
+	PartitionRefreshInterval = 30 * time.Second
+	// How often the progress of lagging partitions is logged.
+	ProgressLogInterval = time.Minute
+
+	// Delay before the first attempt to reconnect after the connection to
+	// Kafka fails. The delay doubles after each failed attempt.
+	ReconnectMinBackoff = time.Second
+	// Upper limit for the delay between attempts to reconnect.
+	ReconnectMaxBackoff = time.Minute
+
+	// How often a running backfill is checked for completion.
+	BackfillCheckInterval = time.Second
+	// How often the progress of a running backfill is logged.
+	BackfillLogInterval = 10 * time.Second
+)
+
+var errNotConnected = errors.New("not connected to kafka")
+
+// Configuration used to create a new consumer.
+type Config struct {
+	// Addresses of the Kafka brokers to connect to.
+	Brokers []string
+	// Topics to consume and the value types of the records on them.
+	Topics Topics
+	// Defines whether storage is rebuilt from the oldest retained records
+	// on startup.
+	Backfill BackfillMode
+	// Encoding of the records, detected for each record by default.
+	Encoding RecordEncoding
+}
+
+// kafkaSource reads Zeebe records from Kafka.
+//
+// The connection to Kafka is held in a session. When any partition consumer
+// reports an error a supervisor goroutine tears the session down and keeps
+// trying to create a new one, with backoff, until it succeeds or the consumer
+// is closed. The new session resumes consuming every topic that was being
+// consumed before.
+type kafkaSource struct {
 	brokers []string
-	topics  []string
+	// Topics to consume and the value types of the records on them.
+	topicLayout Topics
+	// Encoding of the records on the topics.
+	encoding RecordEncoding
+	// Every topic that has been consumed, so that consuming can be resumed
+	// after reconnecting.
+	consumedTopics []string
+
+	sink *sink
 
-	storageUpdater *storageUpdater
+	// Signalled when the current session has failed.
+	failureChannel signalChannelType
 
-	consumer     sarama.Consumer
-	msgChannels  map[string]msgChannelType
-	closeChannel chan struct{}
+	// Running or finished backfill, nil if not backfilling.
+	backfill *backfill
 
-	wg *sync.WaitGroup
+	// Current connection to Kafka, nil while reconnecting.
+	session *session
+	mutex   sync.Mutex
+
+	wg sync.WaitGroup
 }
 
-func NewConsumer(storer storage.Storer, brokers []string, maxRetries int, retryDelay time.Duration) (*Consumer, error) {
-	// wrap newConsumer with retry handling
-	var err error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		kafkaConsumer, err := newConsumer(storer, brokers)
-		if err == nil {
-			return kafkaConsumer, nil
-		}
-		time.Sleep(retryDelay)
+// Connection to Kafka along with everything consuming through it. A session
+// is torn down as a whole when the connection fails.
+type session struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+
+	// Partitions of each topic that are already being consumed.
+	partitions map[string]map[int32]bool
+
+	closeChannel signalChannelType
+	wg           sync.WaitGroup
+}
+
+// Create a new source reading records from Kafka.
+func NewKafkaSource(conf Config) Source {
+	source := &kafkaSource{
+		brokers:        conf.Brokers,
+		topicLayout:    conf.Topics,
+		encoding:       conf.Encoding,
+		consumedTopics: conf.Topics.names(),
+
+		// Buffered so that failing partitions never block on
+		// signalling; one pending signal is enough
+		failureChannel: make(signalChannelType, 1),
+	}
+
+	if conf.Backfill != BackfillNone {
+		log.Printf("Backfilling from the oldest offsets (mode %s)", conf.Backfill)
+		source.backfill = newBackfill()
 	}
-	return nil, fmt.Errorf("maximum number of retries reached: %w", err)
+
+	return source
 }
 
-func newConsumer(storer storage.Storer, brokers []string) (*Consumer, error) {
+func (consumer *kafkaSource) topics() Topics {
+	return consumer.topicLayout
+}
+
+// Connect to Kafka and start consuming every topic.
+func (consumer *kafkaSource) start(sink *sink) error {
+	consumer.sink = sink
+
+	err := consumer.connect()
+	if err != nil {
+		return err
+	}
+
+	consumer.wg.Add(1)
+	go func() {
+		defer consumer.wg.Done()
+		consumer.supervise()
+	}()
+
+	if consumer.backfill != nil {
+		consumer.wg.Add(1)
+		go func() {
+			defer consumer.wg.Done()
+			consumer.watchBackfill()
+		}()
+	}
+
+	return nil
+}
+
+// Create a new session and start consuming every tracked topic through it.
+func (consumer *kafkaSource) connect() error {
 	config := sarama.NewConfig()
+	// Have partition consumers report their errors to us so that we notice
+	// when the connection drops
+	config.Consumer.Return.Errors = true
 
-	consumer, err := sarama.NewConsumer(brokers, config)
-	for err != nil {
-		return nil, err
+	// Use a client of our own so that we can refresh the topic metadata
+	// to find new partitions
+	client, err := sarama.NewClient(consumer.brokers, config)
+	if err != nil {
+		return err
 	}
 
-	msgChannels := map[string]msgChannelType{}
-	for _, topic := range knownTopics {
-		// The optimal buffer size is an open question; it could be as low as 0
-		// if we're okay yielding the consumer goroutine whenever we get to
-		// that point
-		bufSize := 10
-		msgChannel := make(msgChannelType, bufSize)
-		msgChannels[topic] = msgChannel
+	saramaConsumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return err
 	}
 
-	closeChannel := make(signalChannelType)
+	s := &session{
+		client:     client,
+		consumer:   saramaConsumer,
+		partitions: map[string]map[int32]bool{},
 
-	var wg sync.WaitGroup
+		closeChannel: make(signalChannelType),
+	}
 
-	storageUpdater := newDatabaseUpdater(storer, msgChannels, closeChannel, &wg)
+	consumer.mutex.Lock()
+	consumer.session = s
+	topics := append([]string{}, consumer.consumedTopics...)
+	consumer.mutex.Unlock()
 
-	result := Consumer{
-		brokers: brokers,
-		topics:  []string{},
+	for _, topic := range topics {
+		err = consumer.consumeAllPartitions(topic)
+		if isMissingTopic(err) {
+			// Topics of value types only newer brokers export may not
+			// exist yet; they're picked up once they've been created
+			log.Printf("Topic %s doesn't exist yet, consuming it once it does", topic)
+			continue
+		}
+		if err != nil {
+			// Don't leave a half-working session behind
+			return errors.Join(err, consumer.teardown())
+		}
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		consumer.watchPartitions(s)
+	}()
+
+	return nil
+}
+
+// Close the current session and wait for everything using it to finish.
+func (consumer *kafkaSource) teardown() error {
+	consumer.mutex.Lock()
+	s := consumer.session
+	consumer.session = nil
+	consumer.mutex.Unlock()
+
+	if s == nil {
+		return nil
+	}
+
+	close(s.closeChannel)
+	s.wg.Wait()
+
+	err := s.consumer.Close()
+	return errors.Join(err, s.client.Close())
+}
+
+// Housekeeping loop which recreates the session whenever it fails until the
+// consumer is closed.
+func (consumer *kafkaSource) supervise() {
+	for {
+		select {
+		case <-consumer.sink.closeChannel:
+			return
+		case <-consumer.failureChannel:
+			consumer.reconnect()
+		}
+	}
+}
 
-		storageUpdater: storageUpdater,
+// Tear down the failed session and keep trying to create a new one with
+// exponential backoff. Gives up only when the consumer is closed.
+func (consumer *kafkaSource) reconnect() {
+	log.Printf("Kafka connection failed, reconnecting")
+	if err := consumer.teardown(); err != nil {
+		log.Printf("Failed to close Kafka connection: %v", err)
+	}
+
+	backoff := ReconnectMinBackoff
+	for {
+		// Failures signalled by the old session are no longer relevant
+		select {
+		case <-consumer.failureChannel:
+		default:
+		}
+
+		err := consumer.connect()
+		if err == nil {
+			log.Printf("Reconnected to Kafka")
+			return
+		}
+		log.Printf("Failed to reconnect to Kafka, retrying in %v: %v",
+			backoff, err)
 
-		consumer:     consumer,
-		msgChannels:  msgChannels,
-		closeChannel: closeChannel,
+		select {
+		case <-consumer.sink.closeChannel:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, ReconnectMaxBackoff)
+	}
+}
 
-		wg: &wg,
+// Signal the supervisor that the current session has failed.
+func (consumer *kafkaSource) signalFailure() {
+	select {
+	case consumer.failureChannel <- struct{}{}:
+	default:
+		// A failure is already pending
 	}
+}
 
-	for _, topic := range knownTopics {
-		err = result.ConsumeTopic(0, topic)
+// Returns whether consuming failed because a topic doesn't exist.
+func isMissingTopic(err error) bool {
+	return errors.Is(err, sarama.ErrUnknownTopicOrPartition)
+}
+
+// Consume every partition of a topic that isn't being
+// consumed yet.
+func (consumer *kafkaSource) consumeAllPartitions(topic string) error {
+	consumer.mutex.Lock()
+	s := consumer.session
+	consumer.mutex.Unlock()
+
+	if s == nil {
+		return errNotConnected
+	}
+
+	partitions, err := s.client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions for %s: %w", topic, err)
+	}
+
+	for _, partition := range partitions {
+		err = consumer.consumePartition(partition, topic)
 		if err != nil {
-			// If consuming a topic fails, close consumer and
-			// return error
-			result.Close()
-			return nil, err
+			return err
 		}
 	}
 
-	return &result, err
+	return nil
 }
 
-// ConsumeTopic creates a sarama.PartitionConsumer to consume a particular topic.
-func (consumer *Consumer) ConsumeTopic(partition int32, topic string) (err error) {
-	partitionConsumer, err := consumer.consumer.ConsumePartition(
-		topic, partition, sarama.OffsetNewest)
+// Create a sarama.PartitionConsumer to consume a particular topic partition.
+// Partitions which are already being consumed are skipped.
+//
+// Consuming resumes after the last record applied from the partition. If
+// nothing has been applied yet only new records are consumed.
+//
+// The lock is only held while claiming the partition and registering its
+// consumer, not while talking to the brokers, so that a slow partition
+// doesn't hold up the others.
+func (consumer *kafkaSource) consumePartition(partition int32, topic string) error {
+	consumer.mutex.Lock()
+	s := consumer.session
+	if s == nil {
+		consumer.mutex.Unlock()
+		return errNotConnected
+	}
+	if s.partitions[topic][partition] {
+		consumer.mutex.Unlock()
+		return nil
+	}
+	// Claim the partition so that nobody else starts consuming it while
+	// we're not holding the lock
+	if s.partitions[topic] == nil {
+		s.partitions[topic] = map[int32]bool{}
+	}
+	s.partitions[topic][partition] = true
+	consumer.mutex.Unlock()
 
+	partitionConsumer, offset, err := consumer.openPartition(s, topic, partition)
 	if err != nil {
+		consumer.mutex.Lock()
+		delete(s.partitions[topic], partition)
+		consumer.mutex.Unlock()
 		return err
 	}
-	msgChannel := consumer.msgChannels[topic]
-	closeChannel := consumer.closeChannel
-	wg := consumer.wg
+
+	consumer.mutex.Lock()
+	defer consumer.mutex.Unlock()
+
+	if consumer.session != s {
+		// The session was torn down while we weren't holding the lock
+		_ = partitionConsumer.Close()
+		return errNotConnected
+	}
+	if !slices.Contains(consumer.consumedTopics, topic) {
+		consumer.consumedTopics = append(consumer.consumedTopics, topic)
+	}
+
+	progress := consumer.sink.progress
+	progress.started(topic, partition, offset, partitionConsumer.HighWaterMarkOffset())
+
+	msgChannel := consumer.sink.msgChannels[topic]
+	closeChannel := consumer.sink.closeChannel
+	sessionCloseChannel := s.closeChannel
+	wg := &s.wg
 
 	// We're launching a new goroutine, increment the waitgroup counter
-	// TODO this and the goroutine creation might need to be locked? Unsure
-	// about the synchronisation here as a whole
 	wg.Add(1)
 	go func() {
 		// When this goroutine ends, signal the waitgroup that we're done
 		defer wg.Done()
 		defer func() {
+			// Closing returns any errors that were left unread,
+			// there's nothing to do about them at this point
 			if err := partitionConsumer.Close(); err != nil {
-				log.Fatal("partition consumer close error:", err)
+				log.Printf("[%s/%d] Partition consumer close error: %v",
+					topic, partition, err)
 			}
 		}()
 
@@ -156,12 +377,34 @@ func (consumer *Consumer) ConsumeTopic(partition int32, topic string) (err error
 			case <-closeChannel:
 				// Synchronise reader closes on a channel
 				break readLoop
-			case msg := <-partitionConsumer.Messages():
+			case <-sessionCloseChannel:
+				break readLoop
+			case err := <-partitionConsumer.Errors():
+				log.Printf("[%s/%d] Partition consumer error: %v",
+					topic, partition, err)
+				consumer.signalFailure()
+				break readLoop
+			case msg, ok := <-partitionConsumer.Messages():
+				if !ok {
+					// Sarama gave up on the partition
+					consumer.signalFailure()
+					break readLoop
+				}
+
+				progress.consumed(topic, partition, msg.Offset,
+					partitionConsumer.HighWaterMarkOffset())
+
 				// If we got a message do not block on writing
 				// it to our own message channel
 				// TODO: don't necessarily log every message like this
 				select {
-				case msgChannel <- msg.Value:
+				case msgChannel <- message{
+					topic:     msg.Topic,
+					partition: msg.Partition,
+					offset:    msg.Offset,
+					value:     msg.Value,
+					encoding:  consumer.encoding,
+				}:
 					log.Printf("[%s/%d] Consumed message offset %d\n",
 						topic, partition, msg.Offset)
 				case <-closeChannel:
@@ -171,7 +414,8 @@ func (consumer *Consumer) ConsumeTopic(partition int32, topic string) (err error
 					// to read messages before reading more
 					// from kafka ourselves)
 					break readLoop
-
+				case <-sessionCloseChannel:
+					break readLoop
 				}
 				// No default case so we will simply wait when there's
 				// nothing to do
@@ -179,17 +423,193 @@ func (consumer *Consumer) ConsumeTopic(partition int32, topic string) (err error
 		}
 	}()
 
-	return err
+	return nil
 }
 
-func (consumer *Consumer) Close() error {
-	// Close the partitionconsumers (they will simply log any errors when
-	// closing; it's hard to retry that)
-	// Sending a message to closeChannel would just close *one* goroutine -
-	// closing it will make all goroutines read a nil from it instead.
-	close(consumer.closeChannel)
+// Find the offset to start consuming a topic partition from and start
+// consuming it through the session.
+func (consumer *kafkaSource) openPartition(s *session, topic string, partition int32) (sarama.PartitionConsumer, int64, error) {
+	offset, err := consumer.startingOffset(s, topic, partition)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	partitionConsumer, err := s.consumer.ConsumePartition(
+		topic, partition, offset)
+	if errors.Is(err, sarama.ErrOffsetOutOfRange) {
+		// The records after our committed offset have already been
+		// removed by Kafka's retention policy, so the best we can do is
+		// to start from the oldest record still available
+		log.Printf("[%s/%d] Offset %d out of range, consuming from oldest",
+			topic, partition, offset)
+		offset, err = s.client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get oldest offset: %w", err)
+		}
+		partitionConsumer, err = s.consumer.ConsumePartition(
+			topic, partition, offset)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return partitionConsumer, offset, nil
+}
+
+// Periodically look for new partitions in the consumed topics, including
+// topics that didn't exist yet, and log the progress of lagging partitions
+// until the session is closed.
+func (consumer *kafkaSource) watchPartitions(s *session) {
+	refreshTicker := time.NewTicker(PartitionRefreshInterval)
+	defer refreshTicker.Stop()
+	progressTicker := time.NewTicker(ProgressLogInterval)
+	defer progressTicker.Stop()
+
+	for {
+		select {
+		case <-consumer.sink.closeChannel:
+			return
+		case <-s.closeChannel:
+			return
+		case <-refreshTicker.C:
+			consumer.refreshPartitions(s)
+		case <-progressTicker.C:
+			consumer.logProgress()
+		}
+	}
+}
+
+// Refresh topic metadata, start consuming any new partitions and update the
+// high-water marks of the partitions already being consumed.
+func (consumer *kafkaSource) refreshPartitions(s *session) {
+	consumer.mutex.Lock()
+	topics := append([]string{}, consumer.consumedTopics...)
+	consumer.mutex.Unlock()
+
+	// The metadata of the other topics is refreshed even if some don't
+	// exist yet
+	err := s.client.RefreshMetadata(topics...)
+	if err != nil && !isMissingTopic(err) {
+		log.Printf("Failed to refresh metadata: %v", err)
+		// Not being able to reach any broker for metadata means the
+		// connection is likely gone
+		consumer.signalFailure()
+		return
+	}
+
+	for _, topic := range topics {
+		err := consumer.consumeAllPartitions(topic)
+		if isMissingTopic(err) {
+			// Already logged when connecting
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to consume new partitions of %s: %v", topic, err)
+		}
+	}
+
+	for _, progress := range consumer.sink.progress.snapshot() {
+		highWaterMark, err := s.client.GetOffset(
+			progress.Topic, progress.Partition, sarama.OffsetNewest)
+		if err != nil {
+			log.Printf("[%s/%d] Failed to get high-water mark: %v",
+				progress.Topic, progress.Partition, err)
+			continue
+		}
+		consumer.sink.progress.highWaterMark(
+			progress.Topic, progress.Partition, highWaterMark)
+	}
+}
+
+// Log the progress of every partition that hasn't been fully applied.
+func (consumer *kafkaSource) logProgress() {
+	for _, progress := range consumer.sink.progress.snapshot() {
+		if progress.Lag() == 0 {
+			continue
+		}
+		log.Printf("[%s/%d] Applied offset %d, high-water mark %d, lag %d",
+			progress.Topic, progress.Partition, progress.AppliedOffset,
+			progress.HighWaterMark, progress.Lag())
+	}
+}
+
+// Returns a channel which is closed once the backfill has caught up with
+// every partition. If not backfilling the channel is already closed.
+func (consumer *kafkaSource) done() <-chan struct{} {
+	if consumer.backfill == nil {
+		done := make(signalChannelType)
+		close(done)
+		return done
+	}
+	return consumer.backfill.doneChannel
+}
+
+// Wait for the backfill to finish, logging its progress in the meantime.
+func (consumer *kafkaSource) watchBackfill() {
+	checkTicker := time.NewTicker(BackfillCheckInterval)
+	defer checkTicker.Stop()
+	logTicker := time.NewTicker(BackfillLogInterval)
+	defer logTicker.Stop()
+
+	for {
+		select {
+		case <-consumer.sink.closeChannel:
+			return
+		case <-checkTicker.C:
+			progress := consumer.sink.progress.snapshot()
+			if consumer.backfill.check(progress) {
+				consumer.backfill.logProgress(progress)
+				log.Printf("Backfill complete")
+				return
+			}
+		case <-logTicker.C:
+			consumer.backfill.logProgress(consumer.sink.progress.snapshot())
+		}
+	}
+}
+
+// Find the offset to start consuming a topic partition from.
+func (consumer *kafkaSource) startingOffset(s *session, topic string, partition int32) (int64, error) {
+	if consumer.backfill != nil && consumer.backfill.needsTarget(topic, partition) {
+		// Ignore any committed offsets; the backfill covers
+		// everything Kafka still has
+		start, err := s.client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get oldest offset: %w", err)
+		}
+		end, err := s.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get newest offset: %w", err)
+		}
+		consumer.backfill.addTarget(topic, partition, start, end)
+		return start, nil
+	}
+
+	offset, ok, err := consumer.sink.storer.LastCommittedOffset(topic, partition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get committed offset: %w", err)
+	}
+	if !ok {
+		// Resolve the actual offset so that progress tracking knows
+		// where we started from
+		offset, err = s.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get newest offset: %w", err)
+		}
+		return offset, nil
+	}
+
+	// The committed offset is the last record we applied, so continue
+	// from the one after it
+	return offset + 1, nil
+}
+
+// Wait for the supervisor and the partition consumers to stop, and close the
+// connection to Kafka.
+func (consumer *kafkaSource) close() error {
 	consumer.wg.Wait()
 
-	err := consumer.consumer.Close()
-	return err
+	// Close the partitionconsumers (they will simply log any errors when
+	// closing; it's hard to retry that)
+	return consumer.teardown()
 }
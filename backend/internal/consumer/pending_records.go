@@ -0,0 +1,47 @@
+package consumer
+
+import (
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+// PendingRecordQueue retries and discards records waiting for rows that
+// haven't been created.
+//
+// Pending records are applied automatically once the rows they're waiting
+// for are created. The rows may however never show up, e.g. when consuming
+// started after the records creating them, so pending records can also be
+// retried and discarded by hand.
+type PendingRecordQueue struct {
+	storer  storage.Storer
+	updater *storageUpdater
+}
+
+// Create a new pending record queue which applies retried records to storage
+// through `storer`.
+func NewPendingRecordQueue(storer storage.Storer) *PendingRecordQueue {
+	return &PendingRecordQueue{
+		storer: storer,
+		// Only used for applying records, so no goroutines are needed
+		updater: &storageUpdater{
+			storer:     storer,
+			reconciler: newReconciler(),
+		},
+	}
+}
+
+// Retry applying a pending record. Returns whether applying succeeded. The
+// record stays pending if the row it's waiting for still doesn't exist, and
+// is moved to the dead letters if it fails otherwise.
+func (q *PendingRecordQueue) Retry(id int64) (bool, error) {
+	pendingRecord, err := q.storer.PendingRecord(id)
+	if err != nil {
+		return false, err
+	}
+
+	return q.updater.retryPending(pendingRecord)
+}
+
+// Discard a pending record without applying it.
+func (q *PendingRecordQueue) Discard(id int64) error {
+	return q.storer.PendingRecordRemoved(id)
+}
@@ -0,0 +1,88 @@
+package consumer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Storer returning a fixed pending record and only finding jobs which have
+// been created.
+type pendingRecordStorer struct {
+	*jobOrderStorer
+	pendingRecord storage.PendingRecord
+}
+
+func (s *pendingRecordStorer) PendingRecord(int64) (storage.PendingRecord, error) {
+	s.touched["PendingRecord"] = true
+	return s.pendingRecord, nil
+}
+
+func (s *pendingRecordStorer) Transaction(fn func(storage.Storer) error) error {
+	return fn(s)
+}
+
+func TestPendingRecordRetry(t *testing.T) {
+	payload := func(intent Intent) string {
+		value, err := json.Marshal(newJobTestRecord("Job", intent, nil, nil).record)
+		assert.NoError(t, err)
+		return string(value)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		applied bool
+		touched []string
+	}{
+		{
+			"Applied",
+			payload(IntentCreated),
+			true,
+			[]string{"PendingRecord", "JobCreated", "JobEventOccurred", "PendingRecordRemoved", "PendingRecords"},
+		},
+		{
+			"Still waiting",
+			payload(IntentCompleted),
+			false,
+			[]string{"PendingRecord"},
+		},
+		{
+			"Invalid",
+			`{`,
+			false,
+			[]string{"PendingRecord", "DeadLetterStored", "PendingRecordRemoved"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storer := &pendingRecordStorer{
+				newJobOrderStorer(),
+				storage.PendingRecord{ID: 1, Payload: []byte(test.payload)},
+			}
+			queue := NewPendingRecordQueue(storer)
+
+			applied, err := queue.Retry(1)
+			assert.NoError(t, err)
+			assert.Equal(t, test.applied, applied)
+
+			for _, touched := range test.touched {
+				assert.True(t, storer.touched[touched], touched)
+				delete(storer.touched, touched)
+			}
+			assert.Empty(t, storer.touched)
+		})
+	}
+}
+
+func TestPendingRecordDiscard(t *testing.T) {
+	storer := newFixedErrStorer(nil)
+	queue := NewPendingRecordQueue(storer)
+
+	err := queue.Discard(1)
+	assert.NoError(t, err)
+	assert.True(t, storer.touched["PendingRecordRemoved"])
+}
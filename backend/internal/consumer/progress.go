@@ -0,0 +1,117 @@
+package consumer
+
+import (
+	"sort"
+	"sync"
+)
+
+// PartitionProgress describes how far along consuming a single topic
+// partition is.
+type PartitionProgress struct {
+	Topic     string
+	Partition int32
+	// Offset of the last record read from the partition.
+	ConsumedOffset int64
+	// Offset of the last record applied to storage.
+	AppliedOffset int64
+	// Offset the next record produced to the partition will get.
+	HighWaterMark int64
+}
+
+// Lag returns the number of records in the partition that haven't been
+// applied to storage yet.
+func (p PartitionProgress) Lag() int64 {
+	lag := p.HighWaterMark - p.AppliedOffset - 1
+	if lag < 0 {
+		// The high-water mark is only refreshed periodically, so it can
+		// fall behind what we've already applied
+		return 0
+	}
+	return lag
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// Keeps track of the progress of every consumed partition. Safe to use from
+// multiple goroutines.
+type progressTracker struct {
+	mutex      sync.Mutex
+	partitions map[topicPartition]*PartitionProgress
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		partitions: map[topicPartition]*PartitionProgress{},
+	}
+}
+
+// Start tracking a partition which is going to be consumed from offset
+// `start` onwards.
+func (t *progressTracker) started(topic string, partition int32, start int64, highWaterMark int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.partitions[topicPartition{topic, partition}] = &PartitionProgress{
+		Topic:          topic,
+		Partition:      partition,
+		ConsumedOffset: start - 1,
+		AppliedOffset:  start - 1,
+		HighWaterMark:  highWaterMark,
+	}
+}
+
+// Record that a record has been read from a partition.
+func (t *progressTracker) consumed(topic string, partition int32, offset int64, highWaterMark int64) {
+	t.update(topic, partition, func(p *PartitionProgress) {
+		p.ConsumedOffset = offset
+		p.HighWaterMark = highWaterMark
+	})
+}
+
+// Record that a record read from a partition has been applied to storage.
+func (t *progressTracker) applied(topic string, partition int32, offset int64) {
+	t.update(topic, partition, func(p *PartitionProgress) {
+		p.AppliedOffset = offset
+	})
+}
+
+// Record the latest known high-water mark of a partition.
+func (t *progressTracker) highWaterMark(topic string, partition int32, highWaterMark int64) {
+	t.update(topic, partition, func(p *PartitionProgress) {
+		p.HighWaterMark = highWaterMark
+	})
+}
+
+// Apply `fn` to a tracked partition. Untracked partitions are ignored.
+func (t *progressTracker) update(topic string, partition int32, fn func(*PartitionProgress)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if progress, ok := t.partitions[topicPartition{topic, partition}]; ok {
+		fn(progress)
+	}
+}
+
+// Returns a snapshot of the progress of all tracked partitions ordered by
+// topic and partition.
+func (t *progressTracker) snapshot() []PartitionProgress {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	result := make([]PartitionProgress, 0, len(t.partitions))
+	for _, progress := range t.partitions {
+		result = append(result, *progress)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Topic != result[j].Topic {
+			return result[i].Topic < result[j].Topic
+		}
+		return result[i].Partition < result[j].Partition
+	})
+
+	return result
+}
@@ -0,0 +1,56 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTracker(t *testing.T) {
+	tracker := newProgressTracker()
+
+	tracker.started("zeebe-job", 1, 10, 15)
+	tracker.started("zeebe-job", 0, 0, 5)
+	tracker.started("zeebe-deployment", 0, 3, 3)
+
+	t.Run("sorted snapshot", func(t *testing.T) {
+		snapshot := tracker.snapshot()
+		assert.Len(t, snapshot, 3)
+
+		assert.Equal(t, "zeebe-deployment", snapshot[0].Topic)
+		assert.Equal(t, "zeebe-job", snapshot[1].Topic)
+		assert.Equal(t, int32(0), snapshot[1].Partition)
+		assert.Equal(t, "zeebe-job", snapshot[2].Topic)
+		assert.Equal(t, int32(1), snapshot[2].Partition)
+	})
+
+	t.Run("initial lag", func(t *testing.T) {
+		snapshot := tracker.snapshot()
+		assert.Equal(t, int64(0), snapshot[0].Lag())
+		assert.Equal(t, int64(5), snapshot[1].Lag())
+		assert.Equal(t, int64(5), snapshot[2].Lag())
+	})
+
+	t.Run("consumed and applied", func(t *testing.T) {
+		tracker.consumed("zeebe-job", 1, 12, 16)
+		tracker.applied("zeebe-job", 1, 11)
+
+		progress := tracker.snapshot()[2]
+		assert.Equal(t, int64(12), progress.ConsumedOffset)
+		assert.Equal(t, int64(11), progress.AppliedOffset)
+		assert.Equal(t, int64(16), progress.HighWaterMark)
+		assert.Equal(t, int64(4), progress.Lag())
+	})
+
+	t.Run("stale high-water mark", func(t *testing.T) {
+		tracker.applied("zeebe-deployment", 0, 5)
+
+		progress := tracker.snapshot()[0]
+		assert.Equal(t, int64(0), progress.Lag())
+	})
+
+	t.Run("untracked partition", func(t *testing.T) {
+		tracker.applied("zeebe-job", 2, 1)
+		assert.Len(t, tracker.snapshot(), 3)
+	})
+}
@@ -0,0 +1,1095 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Zeebe's exporter protocol encodes every record as a protobuf message of its
+// own value type, e.g. JobRecord, with the common fields of the record in a
+// RecordMetadata message in field 1. Records may also be wrapped in a Record
+// message holding them in a google.protobuf.Any.
+//
+// Field numbers and enum values below follow schema.proto of the exporter
+// protocol. Only the value types below are decoded; the value of any other
+// record is left nil, so that the storage updater can't mistake it for an
+// empty value. Handling such a record fails, which makes it a dead letter,
+// while value types the updater doesn't handle are ignored as usual.
+
+// Field numbers of the RecordMetadata message.
+const (
+	protoMetadataPartitionID          protowire.Number = 1
+	protoMetadataPosition             protowire.Number = 2
+	protoMetadataKey                  protowire.Number = 3
+	protoMetadataTimestamp            protowire.Number = 4
+	protoMetadataRecordType           protowire.Number = 5
+	protoMetadataIntent               protowire.Number = 6
+	protoMetadataValueType            protowire.Number = 7
+	protoMetadataSourceRecordPosition protowire.Number = 8
+	protoMetadataRejectionType        protowire.Number = 9
+	protoMetadataRejectionReason      protowire.Number = 10
+)
+
+// Field number of the metadata in every typed record, and of the wrapped
+// record in the Record message.
+const protoRecordMetadata protowire.Number = 1
+
+// Field numbers of the google.protobuf.Any message.
+const (
+	protoAnyTypeURL protowire.Number = 1
+	protoAnyValue   protowire.Number = 2
+)
+
+// RecordMetadata.RecordType enum values.
+var protoRecordTypes = []RecordType{
+	RecordTypeEvent,
+	RecordTypeCommand,
+	RecordTypeCommandRejection,
+}
+
+// RecordMetadata.ValueType enum values.
+var protoValueTypes = []ValueType{
+	ValueTypeJob,
+	ValueTypeDeployment,
+	ValueTypeProcessInstance,
+	ValueTypeIncident,
+	ValueTypeMessage,
+	ValueTypeMessageSubscription,
+	ValueTypeProcessMessageSubscription,
+	ValueTypeJobBatch,
+	ValueTypeTimer,
+	ValueTypeMessageStartEventSubscription,
+	ValueTypeVariable,
+	ValueTypeVariableDocument,
+	ValueTypeProcessInstanceCreation,
+	ValueTypeError,
+	ValueTypeProcess,
+	ValueTypeDeploymentDistribution,
+	ValueTypeProcessEvent,
+	ValueTypeDecision,
+	ValueTypeDecisionRequirements,
+	ValueTypeDecisionEvaluation,
+	ValueTypeProcessInstanceModification,
+	ValueTypeCheckpoint,
+	ValueTypeEscalation,
+	ValueTypeSignalSubscription,
+	ValueTypeSignal,
+	ValueTypeResourceDeletion,
+	ValueTypeCommandDistribution,
+	ValueTypeProcessInstanceBatch,
+	ValueTypeMessageBatch,
+	ValueTypeForm,
+	ValueTypeUserTask,
+	ValueTypeProcessInstanceMigration,
+}
+
+// Decoders of the values of each value type, keyed by value type.
+var protoValueDecoders = map[ValueType]func(protoMessage) (any, error){
+	ValueTypeDecisionEvaluation:            decodeProtoDecisionEvaluation,
+	ValueTypeDeployment:                    decodeProtoDeployment,
+	ValueTypeError:                         decodeProtoError,
+	ValueTypeEscalation:                    decodeProtoEscalation,
+	ValueTypeForm:                          decodeProtoForm,
+	ValueTypeIncident:                      decodeProtoIncident,
+	ValueTypeJob:                           decodeProtoJob,
+	ValueTypeJobBatch:                      decodeProtoJobBatch,
+	ValueTypeMessage:                       decodeProtoMessageValue,
+	ValueTypeMessageStartEventSubscription: decodeProtoMessageStartEventSubscription,
+	ValueTypeMessageSubscription:           decodeProtoMessageSubscription,
+	ValueTypeProcess:                       decodeProtoProcess,
+	ValueTypeProcessInstance:               decodeProtoProcessInstance,
+	ValueTypeProcessInstanceMigration:      decodeProtoProcessInstanceMigration,
+	ValueTypeProcessMessageSubscription:    decodeProtoProcessMessageSubscription,
+	ValueTypeResourceDeletion:              decodeProtoResourceDeletion,
+	ValueTypeSignal:                        decodeProtoSignal,
+	ValueTypeSignalSubscription:            decodeProtoSignalSubscription,
+	ValueTypeTimer:                         decodeProtoTimer,
+	ValueTypeUserTask:                      decodeProtoUserTask,
+	ValueTypeVariable:                      decodeProtoVariable,
+}
+
+// Parse a protobuf encoded record. The value is converted to JSON so that it
+// can be turned into a typed record with WithTypedValue like any other.
+func parseProtobufRecord(value []byte) (*UntypedRecord, error) {
+	record, err := decodeProtoMessage(value)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := record.message(protoRecordMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Field 1 of a Record wrapper is an Any, which starts with a type URL
+	// instead of the partition ID of metadata
+	if metadata.has(protoAnyTypeURL, protowire.BytesType) {
+		return parseProtobufRecord(metadata.bytes(protoAnyValue))
+	}
+
+	untypedRecord := UntypedRecord{
+		PartitionID:          metadata.int64(protoMetadataPartitionID),
+		Position:             metadata.int64(protoMetadataPosition),
+		Key:                  metadata.int64(protoMetadataKey),
+		Timestamp:            metadata.int64(protoMetadataTimestamp),
+		Intent:               Intent(metadata.string(protoMetadataIntent)),
+		SourceRecordPosition: metadata.int64(protoMetadataSourceRecordPosition),
+		RejectionType:        RejectionType(metadata.string(protoMetadataRejectionType)),
+		RejectionReason:      metadata.string(protoMetadataRejectionReason),
+	}
+
+	untypedRecord.RecordType, err = protoEnum(protoRecordTypes, metadata.int64(protoMetadataRecordType))
+	if err != nil {
+		return nil, fmt.Errorf("unknown record type: %w", err)
+	}
+	untypedRecord.ValueType, err = protoEnum(protoValueTypes, metadata.int64(protoMetadataValueType))
+	if err != nil {
+		return nil, fmt.Errorf("unknown value type: %w", err)
+	}
+
+	decoder, ok := protoValueDecoders[untypedRecord.ValueType]
+	if !ok {
+		return &untypedRecord, nil
+	}
+
+	typedValue, err := decoder(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %v value: %w", untypedRecord.ValueType, err)
+	}
+	untypedRecord.Value, err = json.Marshal(typedValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %v value: %w", untypedRecord.ValueType, err)
+	}
+
+	return &untypedRecord, nil
+}
+
+// Look up an enum value by its number.
+func protoEnum[T any](values []T, number int64) (T, error) {
+	if number < 0 || number >= int64(len(values)) {
+		var zero T
+		return zero, fmt.Errorf("%d", number)
+	}
+
+	return values[number], nil
+}
+
+func decodeProtoDecisionEvaluation(record protoMessage) (any, error) {
+	const (
+		decisionKey              protowire.Number = 2
+		decisionID               protowire.Number = 3
+		decisionName             protowire.Number = 4
+		decisionVersion          protowire.Number = 5
+		decisionRequirementsID   protowire.Number = 6
+		decisionRequirementsKey  protowire.Number = 7
+		decisionOutput           protowire.Number = 8
+		bpmnProcessID            protowire.Number = 9
+		processDefinitionKey     protowire.Number = 10
+		processInstanceKey       protowire.Number = 11
+		elementID                protowire.Number = 12
+		elementInstanceKey       protowire.Number = 13
+		evaluatedDecisions       protowire.Number = 14
+		evaluationFailureMessage protowire.Number = 15
+		failedDecisionID         protowire.Number = 16
+
+		evaluatedDecisionID      protowire.Number = 1
+		evaluatedDecisionName    protowire.Number = 2
+		evaluatedDecisionKey     protowire.Number = 3
+		evaluatedDecisionVersion protowire.Number = 4
+		evaluatedDecisionType    protowire.Number = 5
+		evaluatedDecisionOutput  protowire.Number = 6
+		evaluatedInputs          protowire.Number = 7
+		matchedRules             protowire.Number = 8
+
+		inputID    protowire.Number = 1
+		inputName  protowire.Number = 2
+		inputValue protowire.Number = 3
+
+		ruleID           protowire.Number = 1
+		ruleIndex        protowire.Number = 2
+		evaluatedOutputs protowire.Number = 3
+
+		outputID    protowire.Number = 1
+		outputName  protowire.Number = 2
+		outputValue protowire.Number = 3
+	)
+
+	decisionMessages, err := record.messages(evaluatedDecisions)
+	if err != nil {
+		return nil, err
+	}
+	decisions := []DecisionEvaluationValueEvaluatedDecision{}
+	for _, decision := range decisionMessages {
+		inputMessages, err := decision.messages(evaluatedInputs)
+		if err != nil {
+			return nil, err
+		}
+		inputs := []DecisionEvaluationValueEvaluatedInput{}
+		for _, input := range inputMessages {
+			inputs = append(inputs, DecisionEvaluationValueEvaluatedInput{
+				InputID:    input.string(inputID),
+				InputName:  input.string(inputName),
+				InputValue: input.string(inputValue),
+			})
+		}
+
+		ruleMessages, err := decision.messages(matchedRules)
+		if err != nil {
+			return nil, err
+		}
+		rules := []DecisionEvaluationValueMatchedRule{}
+		for _, rule := range ruleMessages {
+			outputMessages, err := rule.messages(evaluatedOutputs)
+			if err != nil {
+				return nil, err
+			}
+			outputs := []DecisionEvaluationValueEvaluatedOutput{}
+			for _, output := range outputMessages {
+				outputs = append(outputs, DecisionEvaluationValueEvaluatedOutput{
+					OutputID:    output.string(outputID),
+					OutputName:  output.string(outputName),
+					OutputValue: output.string(outputValue),
+				})
+			}
+
+			rules = append(rules, DecisionEvaluationValueMatchedRule{
+				RuleID:           rule.string(ruleID),
+				RuleIndex:        rule.int64(ruleIndex),
+				EvaluatedOutputs: outputs,
+			})
+		}
+
+		decisions = append(decisions, DecisionEvaluationValueEvaluatedDecision{
+			DecisionID:      decision.string(evaluatedDecisionID),
+			DecisionName:    decision.string(evaluatedDecisionName),
+			DecisionKey:     decision.int64(evaluatedDecisionKey),
+			DecisionVersion: decision.int64(evaluatedDecisionVersion),
+			DecisionType:    decision.string(evaluatedDecisionType),
+			DecisionOutput:  decision.string(evaluatedDecisionOutput),
+			EvaluatedInputs: inputs,
+			MatchedRules:    rules,
+		})
+	}
+
+	return DecisionEvaluationValue{
+		DecisionKey:              record.int64(decisionKey),
+		DecisionID:               record.string(decisionID),
+		DecisionName:             record.string(decisionName),
+		DecisionVersion:          record.int64(decisionVersion),
+		DecisionRequirementsID:   record.string(decisionRequirementsID),
+		DecisionRequirementsKey:  record.int64(decisionRequirementsKey),
+		DecisionOutput:           record.string(decisionOutput),
+		BpmnProcessID:            record.string(bpmnProcessID),
+		ProcessDefinitionKey:     record.int64(processDefinitionKey),
+		ProcessInstanceKey:       record.int64(processInstanceKey),
+		ElementID:                record.string(elementID),
+		ElementInstanceKey:       record.int64(elementInstanceKey),
+		EvaluatedDecisions:       decisions,
+		EvaluationFailureMessage: record.string(evaluationFailureMessage),
+		FailedDecisionID:         record.string(failedDecisionID),
+	}, nil
+}
+
+func decodeProtoDeployment(record protoMessage) (any, error) {
+	const (
+		resources                    protowire.Number = 2
+		processMetadata              protowire.Number = 3
+		decisionMetadata             protowire.Number = 4
+		decisionRequirementsMetadata protowire.Number = 5
+		formMetadata                 protowire.Number = 7
+
+		resourceResource     protowire.Number = 1
+		resourceResourceName protowire.Number = 3
+
+		metadataBpmnProcessID        protowire.Number = 1
+		metadataVersion              protowire.Number = 2
+		metadataProcessDefinitionKey protowire.Number = 3
+		metadataResourceName         protowire.Number = 5
+		metadataChecksum             protowire.Number = 6
+		metadataIsDuplicate          protowire.Number = 7
+
+		decisionID                      protowire.Number = 1
+		decisionVersion                 protowire.Number = 2
+		decisionKey                     protowire.Number = 3
+		decisionName                    protowire.Number = 4
+		decisionDecisionRequirementsID  protowire.Number = 5
+		decisionDecisionRequirementsKey protowire.Number = 6
+		decisionIsDuplicate             protowire.Number = 7
+
+		drgID           protowire.Number = 1
+		drgName         protowire.Number = 2
+		drgVersion      protowire.Number = 3
+		drgKey          protowire.Number = 4
+		drgNamespace    protowire.Number = 5
+		drgResourceName protowire.Number = 6
+		drgChecksum     protowire.Number = 7
+		drgIsDuplicate  protowire.Number = 8
+
+		formID           protowire.Number = 1
+		formVersion      protowire.Number = 2
+		formKey          protowire.Number = 3
+		formResourceName protowire.Number = 4
+		formChecksum     protowire.Number = 5
+		formIsDuplicate  protowire.Number = 6
+	)
+
+	value := DeploymentValue{
+		Resources:                    []DeploymentValueResource{},
+		ProcessesMetadata:            []DeploymentValueProcessesMetadata{},
+		DecisionRequirementsMetadata: []DeploymentValueDecisionRequirementsMetadata{},
+		DecisionsMetadata:            []DeploymentValueDecisionsMetadata{},
+		FormMetadata:                 []DeploymentValueFormMetadata{},
+	}
+
+	resourceMessages, err := record.messages(resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resourceMessages {
+		value.Resources = append(value.Resources, DeploymentValueResource{
+			Resource:     resource.bytes(resourceResource),
+			ResourceName: resource.string(resourceResourceName),
+		})
+	}
+
+	metadataMessages, err := record.messages(processMetadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, metadata := range metadataMessages {
+		value.ProcessesMetadata = append(value.ProcessesMetadata, DeploymentValueProcessesMetadata{
+			BpmnProcessID:        metadata.string(metadataBpmnProcessID),
+			Version:              metadata.int64(metadataVersion),
+			ProcessDefinitionKey: metadata.int64(metadataProcessDefinitionKey),
+			ResourceName:         metadata.string(metadataResourceName),
+			Checksum:             metadata.bytes(metadataChecksum),
+			Duplicate:            metadata.bool(metadataIsDuplicate),
+		})
+	}
+
+	drgMessages, err := record.messages(decisionRequirementsMetadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, drg := range drgMessages {
+		value.DecisionRequirementsMetadata = append(value.DecisionRequirementsMetadata, DeploymentValueDecisionRequirementsMetadata{
+			DecisionRequirementsID:      drg.string(drgID),
+			DecisionRequirementsName:    drg.string(drgName),
+			DecisionRequirementsVersion: drg.int64(drgVersion),
+			DecisionRequirementsKey:     drg.int64(drgKey),
+			Namespace:                   drg.string(drgNamespace),
+			ResourceName:                drg.string(drgResourceName),
+			Checksum:                    drg.bytes(drgChecksum),
+			Duplicate:                   drg.bool(drgIsDuplicate),
+		})
+	}
+
+	decisionMessages, err := record.messages(decisionMetadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, decision := range decisionMessages {
+		value.DecisionsMetadata = append(value.DecisionsMetadata, DeploymentValueDecisionsMetadata{
+			DecisionID:              decision.string(decisionID),
+			Version:                 decision.int64(decisionVersion),
+			DecisionKey:             decision.int64(decisionKey),
+			DecisionName:            decision.string(decisionName),
+			DecisionRequirementsID:  decision.string(decisionDecisionRequirementsID),
+			DecisionRequirementsKey: decision.int64(decisionDecisionRequirementsKey),
+			Duplicate:               decision.bool(decisionIsDuplicate),
+		})
+	}
+
+	formMessages, err := record.messages(formMetadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, form := range formMessages {
+		value.FormMetadata = append(value.FormMetadata, DeploymentValueFormMetadata{
+			FormID:       form.string(formID),
+			Version:      form.int64(formVersion),
+			FormKey:      form.int64(formKey),
+			ResourceName: form.string(formResourceName),
+			Checksum:     form.bytes(formChecksum),
+			Duplicate:    form.bool(formIsDuplicate),
+		})
+	}
+
+	return value, nil
+}
+
+func decodeProtoError(record protoMessage) (any, error) {
+	const (
+		exceptionMessage   protowire.Number = 2
+		stacktrace         protowire.Number = 3
+		errorEventPosition protowire.Number = 4
+		processInstanceKey protowire.Number = 5
+	)
+
+	return ErrorValue{
+		ExceptionMessage:   record.string(exceptionMessage),
+		Stacktrace:         record.string(stacktrace),
+		ErrorEventPosition: record.int64(errorEventPosition),
+		ProcessInstanceKey: record.int64(processInstanceKey),
+	}, nil
+}
+
+func decodeProtoEscalation(record protoMessage) (any, error) {
+	const (
+		processInstanceKey protowire.Number = 2
+		escalationCode     protowire.Number = 3
+		throwElementID     protowire.Number = 4
+		catchElementID     protowire.Number = 5
+		tenantID           protowire.Number = 6
+	)
+
+	return EscalationValue{
+		ProcessInstanceKey: record.int64(processInstanceKey),
+		EscalationCode:     record.string(escalationCode),
+		ThrowElementID:     record.string(throwElementID),
+		CatchElementID:     record.string(catchElementID),
+		TenantID:           record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoForm(record protoMessage) (any, error) {
+	const (
+		formID       protowire.Number = 2
+		version      protowire.Number = 3
+		formKey      protowire.Number = 4
+		resourceName protowire.Number = 5
+		checksum     protowire.Number = 6
+		resource     protowire.Number = 7
+		isDuplicate  protowire.Number = 8
+		tenantID     protowire.Number = 9
+	)
+
+	return FormValue{
+		FormID:       record.string(formID),
+		Version:      record.int64(version),
+		FormKey:      record.int64(formKey),
+		ResourceName: record.string(resourceName),
+		Checksum:     record.bytes(checksum),
+		Resource:     record.bytes(resource),
+		Duplicate:    record.bool(isDuplicate),
+		TenantID:     record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoIncident(record protoMessage) (any, error) {
+	const (
+		errorType            protowire.Number = 2
+		errorMessage         protowire.Number = 3
+		bpmnProcessID        protowire.Number = 4
+		processInstanceKey   protowire.Number = 5
+		elementID            protowire.Number = 6
+		elementInstanceKey   protowire.Number = 7
+		jobKey               protowire.Number = 8
+		processDefinitionKey protowire.Number = 9
+		variableScopeKey     protowire.Number = 10
+	)
+
+	return IncidentValue{
+		ErrorType:            record.string(errorType),
+		ErrorMessage:         record.string(errorMessage),
+		BpmnProcessID:        record.string(bpmnProcessID),
+		ProcessInstanceKey:   record.int64(processInstanceKey),
+		ElementID:            record.string(elementID),
+		ElementInstanceKey:   record.int64(elementInstanceKey),
+		JobKey:               record.int64(jobKey),
+		ProcessDefinitionKey: record.int64(processDefinitionKey),
+		VariableScopeKey:     record.int64(variableScopeKey),
+	}, nil
+}
+
+func decodeProtoJob(record protoMessage) (any, error) {
+	const (
+		jobType              protowire.Number = 2
+		worker               protowire.Number = 3
+		retries              protowire.Number = 4
+		deadline             protowire.Number = 5
+		errorMessage         protowire.Number = 6
+		customHeaders        protowire.Number = 7
+		variables            protowire.Number = 8
+		elementID            protowire.Number = 9
+		elementInstanceKey   protowire.Number = 10
+		bpmnProcessID        protowire.Number = 11
+		version              protowire.Number = 12
+		processDefinitionKey protowire.Number = 13
+		processInstanceKey   protowire.Number = 14
+		errorCode            protowire.Number = 15
+		retryBackoff         protowire.Number = 16
+		recurringTime        protowire.Number = 17
+	)
+
+	headerValues, err := record.structValue(customHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom headers: %w", err)
+	}
+	headers := map[string]string{}
+	for name, header := range headerValues {
+		headers[name] = fmt.Sprint(header)
+	}
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return JobValue{
+		Deadline:                 record.int64(deadline),
+		ProcessInstanceKey:       record.int64(processInstanceKey),
+		Retries:                  record.int64(retries),
+		RetryBackoff:             record.int64(retryBackoff),
+		RecurringTime:            record.int64(recurringTime),
+		ProcessDefinitionVersion: record.int64(version),
+		ProcessDefinitionKey:     record.int64(processDefinitionKey),
+		ElementInstanceKey:       record.int64(elementInstanceKey),
+		ElementID:                record.string(elementID),
+		ErrorMessage:             record.string(errorMessage),
+		CustomHeaders:            headers,
+		BpmnProcessID:            record.string(bpmnProcessID),
+		Variables:                variableValues,
+		Type:                     record.string(jobType),
+		ErrorCode:                record.string(errorCode),
+		Worker:                   record.string(worker),
+	}, nil
+}
+
+func decodeProtoJobBatch(record protoMessage) (any, error) {
+	const (
+		jobType           protowire.Number = 2
+		worker            protowire.Number = 3
+		timeout           protowire.Number = 4
+		maxJobsToActivate protowire.Number = 5
+		jobKeys           protowire.Number = 6
+		jobs              protowire.Number = 7
+		truncated         protowire.Number = 8
+	)
+
+	keys, err := record.int64s(jobKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job keys: %w", err)
+	}
+
+	jobMessages, err := record.messages(jobs)
+	if err != nil {
+		return nil, err
+	}
+	jobValues := []JobValue{}
+	for _, job := range jobMessages {
+		jobValue, err := decodeProtoJob(job)
+		if err != nil {
+			return nil, err
+		}
+		jobValues = append(jobValues, jobValue.(JobValue))
+	}
+
+	return JobBatchValue{
+		Truncated:         record.bool(truncated),
+		MaxJobsToActivate: record.int64(maxJobsToActivate),
+		Jobs:              jobValues,
+		Type:              record.string(jobType),
+		Timeout:           record.int64(timeout),
+		Worker:            record.string(worker),
+		JobKeys:           keys,
+	}, nil
+}
+
+func decodeProtoMessageValue(record protoMessage) (any, error) {
+	const (
+		name           protowire.Number = 2
+		correlationKey protowire.Number = 3
+		messageID      protowire.Number = 4
+		timeToLive     protowire.Number = 5
+		variables      protowire.Number = 6
+	)
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return MessageValue{
+		MessageID:      record.string(messageID),
+		Variables:      variableValues,
+		CorrelationKey: record.string(correlationKey),
+		Name:           record.string(name),
+		TimeToLive:     record.int64(timeToLive),
+	}, nil
+}
+
+func decodeProtoMessageStartEventSubscription(record protoMessage) (any, error) {
+	const (
+		processDefinitionKey protowire.Number = 2
+		startEventID         protowire.Number = 3
+		messageName          protowire.Number = 4
+		bpmnProcessID        protowire.Number = 5
+		correlationKey       protowire.Number = 6
+		messageKey           protowire.Number = 7
+		processInstanceKey   protowire.Number = 8
+		variables            protowire.Number = 9
+	)
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return MessageStartEventSubscriptionValue{
+		ProcessDefinitionKey: record.int64(processDefinitionKey),
+		StartEventID:         record.string(startEventID),
+		MessageName:          record.string(messageName),
+		BpmnProcessID:        record.string(bpmnProcessID),
+		CorrelationKey:       record.string(correlationKey),
+		MessageKey:           record.int64(messageKey),
+		ProcessInstanceKey:   record.int64(processInstanceKey),
+		Variables:            variableValues,
+	}, nil
+}
+
+func decodeProtoMessageSubscription(record protoMessage) (any, error) {
+	const (
+		processInstanceKey protowire.Number = 2
+		elementInstanceKey protowire.Number = 3
+		messageName        protowire.Number = 4
+		correlationKey     protowire.Number = 5
+		bpmnProcessID      protowire.Number = 6
+		messageKey         protowire.Number = 7
+		variables          protowire.Number = 8
+		isInterrupting     protowire.Number = 9
+	)
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return MessageSubscriptionValue{
+		ProcessInstanceKey: record.int64(processInstanceKey),
+		ElementInstanceKey: record.int64(elementInstanceKey),
+		MessageKey:         record.int64(messageKey),
+		MessageName:        record.string(messageName),
+		Interrupting:       record.bool(isInterrupting),
+		BpmnProcessID:      record.string(bpmnProcessID),
+		Variables:          variableValues,
+		CorrelationKey:     record.string(correlationKey),
+	}, nil
+}
+
+func decodeProtoProcessInstanceMigration(record protoMessage) (any, error) {
+	const (
+		processInstanceKey         protowire.Number = 2
+		targetProcessDefinitionKey protowire.Number = 3
+		mappingInstructions        protowire.Number = 4
+
+		instructionSourceElementID protowire.Number = 1
+		instructionTargetElementID protowire.Number = 2
+	)
+
+	value := ProcessInstanceMigrationValue{
+		ProcessInstanceKey:         record.int64(processInstanceKey),
+		TargetProcessDefinitionKey: record.int64(targetProcessDefinitionKey),
+		MappingInstructions:        []ProcessInstanceMigrationValueMappingInstruction{},
+	}
+
+	instructions, err := record.messages(mappingInstructions)
+	if err != nil {
+		return nil, err
+	}
+	for _, instruction := range instructions {
+		value.MappingInstructions = append(value.MappingInstructions, ProcessInstanceMigrationValueMappingInstruction{
+			SourceElementID: instruction.string(instructionSourceElementID),
+			TargetElementID: instruction.string(instructionTargetElementID),
+		})
+	}
+
+	return value, nil
+}
+
+func decodeProtoProcessMessageSubscription(record protoMessage) (any, error) {
+	const (
+		processInstanceKey protowire.Number = 2
+		elementInstanceKey protowire.Number = 3
+		messageName        protowire.Number = 4
+		variables          protowire.Number = 5
+		correlationKey     protowire.Number = 6
+		bpmnProcessID      protowire.Number = 7
+		messageKey         protowire.Number = 8
+		elementID          protowire.Number = 9
+		isInterrupting     protowire.Number = 10
+	)
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return ProcessMessageSubscriptionValue{
+		BpmnProcessID:      record.string(bpmnProcessID),
+		ElementInstanceKey: record.int64(elementInstanceKey),
+		ElementID:          record.string(elementID),
+		Variables:          variableValues,
+		MessageKey:         record.int64(messageKey),
+		MessageName:        record.string(messageName),
+		Interrupting:       record.bool(isInterrupting),
+		CorrelationKey:     record.string(correlationKey),
+		ProcessInstanceKey: record.int64(processInstanceKey),
+	}, nil
+}
+
+func decodeProtoProcess(record protoMessage) (any, error) {
+	const (
+		bpmnProcessID        protowire.Number = 2
+		version              protowire.Number = 3
+		processDefinitionKey protowire.Number = 4
+		resourceName         protowire.Number = 5
+		checksum             protowire.Number = 6
+		resource             protowire.Number = 7
+	)
+
+	return ProcessValue{
+		BpmnProcessID:        record.string(bpmnProcessID),
+		Version:              record.int64(version),
+		ProcessDefinitionKey: record.int64(processDefinitionKey),
+		ResourceName:         record.string(resourceName),
+		Checksum:             record.bytes(checksum),
+		Resource:             record.bytes(resource),
+	}, nil
+}
+
+func decodeProtoProcessInstance(record protoMessage) (any, error) {
+	const (
+		bpmnProcessID            protowire.Number = 2
+		version                  protowire.Number = 3
+		processDefinitionKey     protowire.Number = 4
+		processInstanceKey       protowire.Number = 5
+		elementID                protowire.Number = 6
+		flowScopeKey             protowire.Number = 7
+		bpmnElementType          protowire.Number = 8
+		parentProcessInstanceKey protowire.Number = 9
+		parentElementInstanceKey protowire.Number = 10
+	)
+
+	return ProcessInstanceValue{
+		BpmnProcessID:            record.string(bpmnProcessID),
+		ProcessInstanceKey:       record.int64(processInstanceKey),
+		ProcessDefinitionKey:     record.int64(processDefinitionKey),
+		ElementID:                record.string(elementID),
+		FlowScopeKey:             record.int64(flowScopeKey),
+		ParentProcessInstanceKey: record.int64(parentProcessInstanceKey),
+		ParentElementInstanceKey: record.int64(parentElementInstanceKey),
+		BpmnElementType:          BpmnElementType(record.string(bpmnElementType)),
+		Version:                  record.int64(version),
+	}, nil
+}
+
+func decodeProtoResourceDeletion(record protoMessage) (any, error) {
+	const (
+		resourceKey protowire.Number = 2
+		tenantID    protowire.Number = 3
+	)
+
+	return ResourceDeletionValue{
+		ResourceKey: record.int64(resourceKey),
+		TenantID:    record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoSignal(record protoMessage) (any, error) {
+	const (
+		signalName protowire.Number = 2
+		variables  protowire.Number = 3
+		tenantID   protowire.Number = 4
+	)
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return SignalValue{
+		SignalName: record.string(signalName),
+		Variables:  variableValues,
+		TenantID:   record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoSignalSubscription(record protoMessage) (any, error) {
+	const (
+		processDefinitionKey  protowire.Number = 2
+		bpmnProcessID         protowire.Number = 3
+		catchEventID          protowire.Number = 4
+		signalName            protowire.Number = 5
+		catchEventInstanceKey protowire.Number = 6
+		tenantID              protowire.Number = 7
+	)
+
+	return SignalSubscriptionValue{
+		ProcessDefinitionKey:  record.int64(processDefinitionKey),
+		BpmnProcessID:         record.string(bpmnProcessID),
+		CatchEventID:          record.string(catchEventID),
+		CatchEventInstanceKey: record.int64(catchEventInstanceKey),
+		SignalName:            record.string(signalName),
+		TenantID:              record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoTimer(record protoMessage) (any, error) {
+	const (
+		elementInstanceKey   protowire.Number = 2
+		dueDate              protowire.Number = 3
+		repetitions          protowire.Number = 4
+		targetElementID      protowire.Number = 5
+		processInstanceKey   protowire.Number = 6
+		processDefinitionKey protowire.Number = 7
+	)
+
+	return TimerValue{
+		TargetElementID:      record.string(targetElementID),
+		ProcessInstanceKey:   record.int64(processInstanceKey),
+		ProcessDefinitionKey: record.int64(processDefinitionKey),
+		ElementInstanceKey:   record.int64(elementInstanceKey),
+		DueDate:              record.int64(dueDate),
+		Repetitions:          record.int64(repetitions),
+	}, nil
+}
+
+func decodeProtoUserTask(record protoMessage) (any, error) {
+	const (
+		userTaskKey              protowire.Number = 2
+		assignee                 protowire.Number = 3
+		candidateGroups          protowire.Number = 4
+		candidateUsers           protowire.Number = 5
+		dueDate                  protowire.Number = 6
+		followUpDate             protowire.Number = 7
+		formKey                  protowire.Number = 8
+		variables                protowire.Number = 9
+		customHeaders            protowire.Number = 10
+		bpmnProcessID            protowire.Number = 11
+		processDefinitionVersion protowire.Number = 12
+		processDefinitionKey     protowire.Number = 13
+		processInstanceKey       protowire.Number = 14
+		elementID                protowire.Number = 15
+		elementInstanceKey       protowire.Number = 16
+		tenantID                 protowire.Number = 17
+		changedAttributes        protowire.Number = 18
+		action                   protowire.Number = 19
+		externalFormReference    protowire.Number = 20
+	)
+
+	headerValues, err := record.structValue(customHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom headers: %w", err)
+	}
+	headers := map[string]string{}
+	for name, header := range headerValues {
+		headers[name] = fmt.Sprint(header)
+	}
+
+	variableValues, err := record.structValue(variables)
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables: %w", err)
+	}
+
+	return UserTaskValue{
+		UserTaskKey:              record.int64(userTaskKey),
+		Assignee:                 record.string(assignee),
+		CandidateGroupsList:      record.strings(candidateGroups),
+		CandidateUsersList:       record.strings(candidateUsers),
+		DueDate:                  record.string(dueDate),
+		FollowUpDate:             record.string(followUpDate),
+		FormKey:                  record.int64(formKey),
+		ExternalFormReference:    record.string(externalFormReference),
+		ChangedAttributes:        record.strings(changedAttributes),
+		Variables:                variableValues,
+		CustomHeaders:            headers,
+		Action:                   record.string(action),
+		ElementID:                record.string(elementID),
+		ElementInstanceKey:       record.int64(elementInstanceKey),
+		BpmnProcessID:            record.string(bpmnProcessID),
+		ProcessDefinitionVersion: record.int64(processDefinitionVersion),
+		ProcessDefinitionKey:     record.int64(processDefinitionKey),
+		ProcessInstanceKey:       record.int64(processInstanceKey),
+		TenantID:                 record.string(tenantID),
+	}, nil
+}
+
+func decodeProtoVariable(record protoMessage) (any, error) {
+	const (
+		name                 protowire.Number = 2
+		value                protowire.Number = 3
+		scopeKey             protowire.Number = 4
+		processInstanceKey   protowire.Number = 5
+		processDefinitionKey protowire.Number = 6
+		bpmnProcessID        protowire.Number = 7
+	)
+
+	return VariableValue{
+		ProcessInstanceKey:   record.int64(processInstanceKey),
+		ProcessDefinitionKey: record.int64(processDefinitionKey),
+		BpmnProcessID:        record.string(bpmnProcessID),
+		ScopeKey:             record.int64(scopeKey),
+		Name:                 record.string(name),
+		Value:                record.string(value),
+	}, nil
+}
+
+// Fields of a decoded protobuf message. As in protobuf, the last value of a
+// scalar field wins, and missing fields have their zero values.
+type protoMessage struct {
+	// Every value of varint fields, in order.
+	varints map[protowire.Number][]uint64
+	// Every value of length-delimited fields, in order.
+	lengthDelimited map[protowire.Number][][]byte
+}
+
+// Decode the fields of a protobuf message without a schema.
+func decodeProtoMessage(data []byte) (protoMessage, error) {
+	message := protoMessage{
+		varints:         map[protowire.Number][]uint64{},
+		lengthDelimited: map[protowire.Number][][]byte{},
+	}
+
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protoMessage{}, fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch wireType {
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return protoMessage{}, fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+			}
+			message.varints[number] = append(message.varints[number], value)
+			data = data[n:]
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return protoMessage{}, fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+			}
+			message.lengthDelimited[number] = append(message.lengthDelimited[number], value)
+			data = data[n:]
+		default:
+			// None of the fields we read use other wire types
+			n := protowire.ConsumeFieldValue(number, wireType, data)
+			if n < 0 {
+				return protoMessage{}, fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+
+	return message, nil
+}
+
+// Returns whether the message has a field of the given wire type.
+func (m protoMessage) has(number protowire.Number, wireType protowire.Type) bool {
+	switch wireType { // nolint:exhaustive
+	case protowire.VarintType:
+		_, ok := m.varints[number]
+		return ok
+	case protowire.BytesType:
+		_, ok := m.lengthDelimited[number]
+		return ok
+	default:
+		return false
+	}
+}
+
+// Integer fields of any size, negative int32 values being sign extended to
+// 64 bits on the wire.
+func (m protoMessage) int64(number protowire.Number) int64 {
+	values := m.varints[number]
+	if len(values) == 0 {
+		return 0
+	}
+
+	return int64(values[len(values)-1])
+}
+
+func (m protoMessage) bool(number protowire.Number) bool {
+	return m.int64(number) != 0
+}
+
+// Repeated integer field, which is packed into length-delimited values by
+// default but may also be encoded as separate varints. Empty if the field is
+// missing.
+func (m protoMessage) int64s(number protowire.Number) ([]int64, error) {
+	values := []int64{}
+	for _, packed := range m.lengthDelimited[number] {
+		for len(packed) > 0 {
+			value, n := protowire.ConsumeVarint(packed)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+			}
+			values = append(values, int64(value))
+			packed = packed[n:]
+		}
+	}
+	for _, value := range m.varints[number] {
+		values = append(values, int64(value))
+	}
+
+	return values, nil
+}
+
+func (m protoMessage) bytes(number protowire.Number) []byte {
+	values := m.lengthDelimited[number]
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values[len(values)-1]
+}
+
+func (m protoMessage) string(number protowire.Number) string {
+	return string(m.bytes(number))
+}
+
+// Repeated string field. Empty if the field is missing.
+func (m protoMessage) strings(number protowire.Number) []string {
+	values := []string{}
+	for _, value := range m.lengthDelimited[number] {
+		values = append(values, string(value))
+	}
+
+	return values
+}
+
+// Nested message field. Empty if the field is missing.
+func (m protoMessage) message(number protowire.Number) (protoMessage, error) {
+	return decodeProtoMessage(m.bytes(number))
+}
+
+// Repeated nested message field.
+func (m protoMessage) messages(number protowire.Number) ([]protoMessage, error) {
+	var messages []protoMessage
+	for _, value := range m.lengthDelimited[number] {
+		message, err := decodeProtoMessage(value)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// google.protobuf.Struct field as a map. Empty if the field is missing.
+func (m protoMessage) structValue(number protowire.Number) (map[string]any, error) {
+	var value structpb.Struct
+	err := proto.Unmarshal(m.bytes(number), &value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.AsMap(), nil
+}
@@ -0,0 +1,1088 @@
+package consumer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+)
+
+// Excerpt of the exporter protocol's schema.proto. Fixtures are written by
+// field and enum name and encoded through it, so that the field numbers
+// hard-coded in protobuf.go are checked against the schema rather than
+// against themselves.
+const exporterProtocolSchema = "testdata/exporter_protocol.proto"
+
+var (
+	exporterProtocolOnce sync.Once
+	exporterProtocol     protoreflect.FileDescriptor
+	exporterProtocolErr  error
+)
+
+// Encode a record of the exporter protocol given in the protobuf text format,
+// e.g. encodeProtoRecord(t, "JobRecord", `type: "someType"`).
+func encodeProtoRecord(t *testing.T, messageName string, text string) []byte {
+	t.Helper()
+
+	value, err := proto.Marshal(newProtoRecord(t, messageName, text))
+	assert.NoError(t, err)
+	return value
+}
+
+// Encode a record wrapped in a Record message, as the exporter does when
+// configured to.
+func encodeWrappedProtoRecord(t *testing.T, messageName string, text string) []byte {
+	t.Helper()
+
+	wrapped, err := anypb.New(newProtoRecord(t, messageName, text))
+	assert.NoError(t, err)
+
+	record := newProtoRecord(t, "Record", "")
+	record.ProtoReflect().Set(
+		record.ProtoReflect().Descriptor().Fields().ByName("record"),
+		protoreflect.ValueOfMessage(wrapped.ProtoReflect()),
+	)
+
+	value, err := proto.Marshal(record)
+	assert.NoError(t, err)
+	return value
+}
+
+func newProtoRecord(t *testing.T, messageName string, text string) proto.Message {
+	t.Helper()
+
+	exporterProtocolOnce.Do(func() {
+		exporterProtocol, exporterProtocolErr = parseProtoSchema(exporterProtocolSchema)
+	})
+	if !assert.NoError(t, exporterProtocolErr) {
+		t.FailNow()
+	}
+
+	descriptor := exporterProtocol.Messages().ByName(protoreflect.Name(messageName))
+	if !assert.NotNil(t, descriptor, "no message %s in the schema", messageName) {
+		t.FailNow()
+	}
+
+	message := dynamicpb.NewMessage(descriptor)
+	if !assert.NoError(t, prototext.Unmarshal([]byte(text), message)) {
+		t.FailNow()
+	}
+	return message
+}
+
+// Tokens of a .proto file: identifiers and numbers, string literals, and
+// single-character symbols.
+var protoTokenPattern = regexp.MustCompile(`[\w.-]+|"[^"]*"|[{}=;]`)
+
+var protoScalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"bytes":  descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"double": descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"int32":  descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"uint64": descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+}
+
+// Build a file descriptor from a proto3 file using the subset of the language
+// the schema needs: imports, nested messages and enums, and plain or repeated
+// fields. Types are resolved by protodesc, like protoc would.
+func parseProtoSchema(path string) (protoreflect.FileDescriptor, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for _, line := range strings.Split(string(source), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		tokens = append(tokens, protoTokenPattern.FindAllString(line, -1)...)
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:   proto.String(path),
+		Syntax: proto.String("proto3"),
+	}
+	parser := protoParser{tokens: tokens}
+	for !parser.done() {
+		switch keyword := parser.next(); keyword {
+		case "syntax":
+			parser.expect("=")
+			parser.next()
+		case "package":
+			file.Package = proto.String(parser.next())
+		case "import":
+			file.Dependency = append(file.Dependency, strings.Trim(parser.next(), `"`))
+		case "message":
+			file.MessageType = append(file.MessageType, parser.message())
+		case "enum":
+			file.EnumType = append(file.EnumType, parser.enum())
+		default:
+			return nil, fmt.Errorf("unexpected %q in %s", keyword, path)
+		}
+		parser.expect(";")
+	}
+	if parser.err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, parser.err)
+	}
+
+	return protodesc.NewFile(file, protoregistry.GlobalFiles)
+}
+
+type protoParser struct {
+	tokens []string
+	err    error
+}
+
+func (p *protoParser) done() bool {
+	return len(p.tokens) == 0 || p.err != nil
+}
+
+func (p *protoParser) next() string {
+	if len(p.tokens) == 0 {
+		p.err = fmt.Errorf("unexpected end of file")
+		return ""
+	}
+
+	token := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return token
+}
+
+func (p *protoParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+
+	return p.tokens[0]
+}
+
+func (p *protoParser) expect(token string) {
+	// Declarations ending in a block have no semicolon
+	if token == ";" && p.peek() != ";" {
+		return
+	}
+	if next := p.next(); next != token && p.err == nil {
+		p.err = fmt.Errorf("expected %q, got %q", token, next)
+	}
+}
+
+func (p *protoParser) number() int32 {
+	token := p.next()
+	number, err := strconv.ParseInt(token, 10, 32)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid number %q", token)
+	}
+
+	return int32(number)
+}
+
+func (p *protoParser) message() *descriptorpb.DescriptorProto {
+	message := &descriptorpb.DescriptorProto{Name: proto.String(p.next())}
+	p.expect("{")
+	for !p.done() && p.peek() != "}" {
+		switch token := p.next(); token {
+		case "message":
+			message.NestedType = append(message.NestedType, p.message())
+		case "enum":
+			message.EnumType = append(message.EnumType, p.enum())
+		default:
+			message.Field = append(message.Field, p.field(token))
+		}
+		p.expect(";")
+	}
+	p.expect("}")
+
+	return message
+}
+
+func (p *protoParser) field(fieldType string) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if fieldType == "repeated" {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		fieldType = p.next()
+	}
+
+	if scalarType, ok := protoScalarTypes[fieldType]; ok {
+		field.Type = scalarType.Enum()
+	} else {
+		// Left to protodesc to resolve as a message or enum
+		field.TypeName = proto.String(fieldType)
+	}
+
+	field.Name = proto.String(p.next())
+	field.JsonName = proto.String(field.GetName())
+	p.expect("=")
+	field.Number = proto.Int32(p.number())
+
+	return field
+}
+
+func (p *protoParser) enum() *descriptorpb.EnumDescriptorProto {
+	enum := &descriptorpb.EnumDescriptorProto{Name: proto.String(p.next())}
+	p.expect("{")
+	for !p.done() && p.peek() != "}" {
+		value := &descriptorpb.EnumValueDescriptorProto{Name: proto.String(p.next())}
+		p.expect("=")
+		value.Number = proto.Int32(p.number())
+		enum.Value = append(enum.Value, value)
+		p.expect(";")
+	}
+	p.expect("}")
+
+	return enum
+}
+
+// JobRecord of the exporter protocol holding the same record as
+// jobRecordJSON.
+func jobRecordProtobuf(t *testing.T) []byte {
+	return encodeProtoRecord(t, "JobRecord", `
+		metadata {
+			partitionId: 14
+			position: 308991363
+			key: 31525197429944929
+			timestamp: 1696756438613
+			recordType: EVENT
+			intent: "CREATED"
+			valueType: JOB
+			sourceRecordPosition: 308989657
+			rejectionType: "NULL_VAL"
+		}
+		type: "someType"
+		retries: 3
+		deadline: -1
+		customHeaders {}
+		variables {}
+		elementId: "SomeElement"
+		elementInstanceKey: 31525197429944928
+		bpmnProcessId: "SomeBPMN"
+		workflowDefinitionVersion: 1
+		processDefinitionKey: 2251799813686516
+		processInstanceKey: 31525197427841255
+		recurringTime: -1
+	`)
+}
+
+// Fields of VariableRecord of the exporter protocol holding the same record
+// as variableRecordJSON.
+const variableRecordProtobufText = `
+	metadata {
+		partitionId: 5
+		position: 249961315
+		key: 11258999104522787
+		timestamp: 1696758123056
+		recordType: EVENT
+		intent: "CREATED"
+		valueType: VARIABLE
+		sourceRecordPosition: 249961163
+		rejectionType: "NULL_VAL"
+	}
+	name: "maximumDuration"
+	value: "\"PT168H\""
+	scopeKey: 11258999104522786
+	processInstanceKey: 11258999100189181
+	processDefinitionKey: 2251799813686310
+	bpmnProcessId: "SomeBPMN"
+`
+
+func TestProtobufEnums(t *testing.T) {
+	metadata := newProtoRecord(t, "RecordMetadata", "").ProtoReflect().Descriptor()
+
+	valueTypes := metadata.Enums().ByName("ValueType").Values()
+	assert.Equal(t, valueTypes.Len(), len(protoValueTypes))
+	for i := 0; i < valueTypes.Len(); i++ {
+		valueType, err := protoEnum(protoValueTypes, int64(valueTypes.Get(i).Number()))
+		assert.NoError(t, err)
+		assert.Equal(t, ValueType(valueTypes.Get(i).Name()), valueType)
+	}
+
+	recordTypes := metadata.Enums().ByName("RecordType").Values()
+	assert.Equal(t, recordTypes.Len(), len(protoRecordTypes))
+	for i := 0; i < recordTypes.Len(); i++ {
+		recordType, err := protoEnum(protoRecordTypes, int64(recordTypes.Get(i).Number()))
+		assert.NoError(t, err)
+		assert.Equal(t, RecordType(recordTypes.Get(i).Name()), recordType)
+	}
+}
+
+func TestProtobufJobRecord(t *testing.T) {
+	untypedJobRecord, err := parseRecordAs(RecordEncodingProtobuf, jobRecordProtobuf(t))
+	assert.NoError(t, err)
+
+	jobRecord, err := WithTypedValue[JobValue](*untypedJobRecord)
+	assert.NoError(t, err)
+
+	// The broker version isn't part of protobuf records
+	expected := expectedJobRecord
+	expected.BrokerVersion = ""
+	assert.Equal(t, expected, jobRecord)
+}
+
+func TestProtobufVariableRecord(t *testing.T) {
+	variableRecord := encodeProtoRecord(t, "VariableRecord", variableRecordProtobufText)
+	untypedVariableRecord, err := parseRecordAs(RecordEncodingProtobuf, variableRecord)
+	assert.NoError(t, err)
+
+	typedVariableRecord, err := WithTypedValue[VariableValue](*untypedVariableRecord)
+	assert.NoError(t, err)
+
+	expected := expectedVariableRecord
+	expected.BrokerVersion = ""
+	assert.Equal(t, expected, typedVariableRecord)
+}
+
+func TestProtobufWrappedRecord(t *testing.T) {
+	wrapped := encodeWrappedProtoRecord(t, "VariableRecord", variableRecordProtobufText)
+
+	untypedVariableRecord, err := parseRecordAs(RecordEncodingProtobuf, wrapped)
+	assert.NoError(t, err)
+
+	variableRecord, err := WithTypedValue[VariableValue](*untypedVariableRecord)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedVariableRecord.Value, variableRecord.Value)
+	assert.Equal(t, expectedVariableRecord.Key, variableRecord.Key)
+}
+
+func TestProtobufRejection(t *testing.T) {
+	untypedRecord, err := parseRecordAs(RecordEncodingProtobuf, encodeProtoRecord(t, "JobRecord", `
+		metadata {
+			key: 3
+			recordType: COMMAND_REJECTION
+			intent: "COMPLETE"
+			valueType: JOB
+			rejectionType: "NOT_FOUND"
+			rejectionReason: "Expected to complete job, but no such job was found"
+		}
+	`))
+	assert.NoError(t, err)
+	assert.Equal(t, RecordTypeCommandRejection, untypedRecord.RecordType)
+	assert.Equal(t, IntentComplete, untypedRecord.Intent)
+	assert.Equal(t, RejectionTypeNotFound, untypedRecord.RejectionType)
+	assert.Equal(t, "Expected to complete job, but no such job was found", untypedRecord.RejectionReason)
+}
+
+func TestProtobufRecordValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageName string
+		text        string
+		valueType   ValueType
+		typed       func(UntypedRecord) (any, error)
+		expected    any
+	}{
+		{
+			name:        "Decision evaluation",
+			messageName: "DecisionEvaluationRecord",
+			text: `
+				metadata { valueType: DECISION_EVALUATION intent: "EVALUATED" }
+				decisionKey: 2251799813685251
+				decisionId: "discount"
+				decisionName: "Discount"
+				decisionVersion: 2
+				decisionRequirementsId: "pricing"
+				decisionRequirementsKey: 2251799813685250
+				decisionOutput: "0.1"
+				bpmnProcessId: "order"
+				processDefinitionKey: 2251799813685249
+				processInstanceKey: 2251799813686310
+				elementId: "Price"
+				elementInstanceKey: 2251799813686316
+				evaluatedDecisions {
+					decisionId: "discount"
+					decisionName: "Discount"
+					decisionKey: 2251799813685251
+					decisionVersion: 2
+					decisionType: "DECISION_TABLE"
+					decisionOutput: "0.1"
+					evaluatedInputs { inputId: "Input_1" inputName: "Total" inputValue: "120" }
+					matchedRules {
+						ruleId: "Rule_2"
+						ruleIndex: 2
+						evaluatedOutputs { outputId: "Output_1" outputName: "discount" outputValue: "0.1" }
+					}
+				}
+			`,
+			valueType: ValueTypeDecisionEvaluation,
+			typed:     typedProtoValue[DecisionEvaluationValue],
+			expected: DecisionEvaluationValue{
+				DecisionKey:             2251799813685251,
+				DecisionID:              "discount",
+				DecisionName:            "Discount",
+				DecisionVersion:         2,
+				DecisionRequirementsID:  "pricing",
+				DecisionRequirementsKey: 2251799813685250,
+				DecisionOutput:          "0.1",
+				BpmnProcessID:           "order",
+				ProcessDefinitionKey:    2251799813685249,
+				ProcessInstanceKey:      2251799813686310,
+				ElementID:               "Price",
+				ElementInstanceKey:      2251799813686316,
+				EvaluatedDecisions: []DecisionEvaluationValueEvaluatedDecision{{
+					DecisionID:      "discount",
+					DecisionName:    "Discount",
+					DecisionKey:     2251799813685251,
+					DecisionVersion: 2,
+					DecisionType:    "DECISION_TABLE",
+					DecisionOutput:  "0.1",
+					EvaluatedInputs: []DecisionEvaluationValueEvaluatedInput{
+						{InputID: "Input_1", InputName: "Total", InputValue: "120"},
+					},
+					MatchedRules: []DecisionEvaluationValueMatchedRule{{
+						RuleID:    "Rule_2",
+						RuleIndex: 2,
+						EvaluatedOutputs: []DecisionEvaluationValueEvaluatedOutput{
+							{OutputID: "Output_1", OutputName: "discount", OutputValue: "0.1"},
+						},
+					}},
+				}},
+			},
+		},
+		{
+			name:        "Failed decision evaluation",
+			messageName: "DecisionEvaluationRecord",
+			text: `
+				metadata { valueType: DECISION_EVALUATION intent: "FAILED" }
+				decisionId: "discount"
+				processInstanceKey: 2251799813686310
+				evaluationFailureMessage: "no variable found for name 'total'"
+				failedDecisionId: "discount"
+			`,
+			valueType: ValueTypeDecisionEvaluation,
+			typed:     typedProtoValue[DecisionEvaluationValue],
+			expected: DecisionEvaluationValue{
+				DecisionID:               "discount",
+				ProcessInstanceKey:       2251799813686310,
+				EvaluatedDecisions:       []DecisionEvaluationValueEvaluatedDecision{},
+				EvaluationFailureMessage: "no variable found for name 'total'",
+				FailedDecisionID:         "discount",
+			},
+		},
+		{
+			name:        "Deployment",
+			messageName: "DeploymentRecord",
+			text: `
+				metadata { valueType: DEPLOYMENT intent: "CREATED" }
+				resources { resource: "<bpmn/>" resourceName: "order.bpmn" }
+				resources { resource: "<dmn/>" resourceName: "pricing.dmn" }
+				resources { resource: "{}" resourceName: "approval.form" }
+				processMetadata {
+					bpmnProcessId: "order"
+					version: 2
+					processDefinitionKey: 2251799813685249
+					resourceName: "order.bpmn"
+					checksum: "sum"
+					isDuplicate: true
+				}
+				decisionRequirementsMetadata {
+					decisionRequirementsId: "pricing"
+					decisionRequirementsName: "Pricing"
+					decisionRequirementsVersion: 1
+					decisionRequirementsKey: 2251799813685250
+					namespace: "http://camunda.org/schema/1.0/dmn"
+					resourceName: "pricing.dmn"
+					checksum: "dmnsum"
+				}
+				decisionMetadata {
+					decisionId: "discount"
+					version: 1
+					decisionKey: 2251799813685251
+					decisionName: "Discount"
+					decisionRequirementsId: "pricing"
+					decisionRequirementsKey: 2251799813685250
+				}
+				formMetadata {
+					formId: "approval"
+					version: 3
+					formKey: 2251799813685260
+					resourceName: "approval.form"
+					checksum: "formsum"
+					isDuplicate: true
+				}
+			`,
+			valueType: ValueTypeDeployment,
+			typed:     typedProtoValue[DeploymentValue],
+			expected: DeploymentValue{
+				Resources: []DeploymentValueResource{
+					{Resource: []byte("<bpmn/>"), ResourceName: "order.bpmn"},
+					{Resource: []byte("<dmn/>"), ResourceName: "pricing.dmn"},
+					{Resource: []byte("{}"), ResourceName: "approval.form"},
+				},
+				ProcessesMetadata: []DeploymentValueProcessesMetadata{{
+					BpmnProcessID:        "order",
+					Version:              2,
+					ProcessDefinitionKey: 2251799813685249,
+					ResourceName:         "order.bpmn",
+					Checksum:             []byte("sum"),
+					Duplicate:            true,
+				}},
+				DecisionRequirementsMetadata: []DeploymentValueDecisionRequirementsMetadata{{
+					DecisionRequirementsID:      "pricing",
+					DecisionRequirementsName:    "Pricing",
+					DecisionRequirementsVersion: 1,
+					DecisionRequirementsKey:     2251799813685250,
+					Namespace:                   "http://camunda.org/schema/1.0/dmn",
+					ResourceName:                "pricing.dmn",
+					Checksum:                    []byte("dmnsum"),
+				}},
+				DecisionsMetadata: []DeploymentValueDecisionsMetadata{{
+					DecisionID:              "discount",
+					Version:                 1,
+					DecisionKey:             2251799813685251,
+					DecisionName:            "Discount",
+					DecisionRequirementsID:  "pricing",
+					DecisionRequirementsKey: 2251799813685250,
+				}},
+				FormMetadata: []DeploymentValueFormMetadata{{
+					FormID:       "approval",
+					Version:      3,
+					FormKey:      2251799813685260,
+					ResourceName: "approval.form",
+					Checksum:     []byte("formsum"),
+					Duplicate:    true,
+				}},
+			},
+		},
+		{
+			name:        "Error",
+			messageName: "ErrorRecord",
+			text: `
+				metadata { valueType: ERROR intent: "CREATED" }
+				exceptionMessage: "Expected a number"
+				stacktrace: "at Engine.process"
+				errorEventPosition: 1234
+				processInstanceKey: 2251799813686310
+			`,
+			valueType: ValueTypeError,
+			typed:     typedProtoValue[ErrorValue],
+			expected: ErrorValue{
+				ExceptionMessage:   "Expected a number",
+				Stacktrace:         "at Engine.process",
+				ErrorEventPosition: 1234,
+				ProcessInstanceKey: 2251799813686310,
+			},
+		},
+		{
+			name:        "Escalation",
+			messageName: "EscalationRecord",
+			text: `
+				metadata { valueType: ESCALATION intent: "ESCALATED" }
+				processInstanceKey: 2251799813686310
+				escalationCode: "LATE"
+				throwElementId: "Throw"
+				catchElementId: "Catch"
+				tenantId: "<default>"
+			`,
+			valueType: ValueTypeEscalation,
+			typed:     typedProtoValue[EscalationValue],
+			expected: EscalationValue{
+				ProcessInstanceKey: 2251799813686310,
+				EscalationCode:     "LATE",
+				ThrowElementID:     "Throw",
+				CatchElementID:     "Catch",
+				TenantID:           "<default>",
+			},
+		},
+		{
+			name:        "Form",
+			messageName: "FormRecord",
+			text: `
+				metadata { valueType: FORM intent: "CREATED" }
+				formId: "approval"
+				version: 3
+				formKey: 2251799813685260
+				resourceName: "approval.form"
+				checksum: "sum"
+				resource: "{}"
+				isDuplicate: true
+				tenantId: "<default>"
+			`,
+			valueType: ValueTypeForm,
+			typed:     typedProtoValue[FormValue],
+			expected: FormValue{
+				FormID:       "approval",
+				Version:      3,
+				FormKey:      2251799813685260,
+				ResourceName: "approval.form",
+				Checksum:     []byte("sum"),
+				Resource:     []byte("{}"),
+				Duplicate:    true,
+				TenantID:     "<default>",
+			},
+		},
+		{
+			name:        "Incident",
+			messageName: "IncidentRecord",
+			text: `
+				metadata { valueType: INCIDENT intent: "CREATED" }
+				errorType: "JOB_NO_RETRIES"
+				errorMessage: "No more retries left"
+				bpmnProcessId: "order"
+				processInstanceKey: 2251799813686310
+				elementId: "Ship"
+				elementInstanceKey: 2251799813686315
+				jobKey: 2251799813686320
+				processDefinitionKey: 2251799813685249
+				variableScopeKey: 2251799813686315
+			`,
+			valueType: ValueTypeIncident,
+			typed:     typedProtoValue[IncidentValue],
+			expected: IncidentValue{
+				ErrorType:            "JOB_NO_RETRIES",
+				ErrorMessage:         "No more retries left",
+				BpmnProcessID:        "order",
+				ProcessInstanceKey:   2251799813686310,
+				ElementID:            "Ship",
+				ElementInstanceKey:   2251799813686315,
+				JobKey:               2251799813686320,
+				ProcessDefinitionKey: 2251799813685249,
+				VariableScopeKey:     2251799813686315,
+			},
+		},
+		{
+			name:        "Job",
+			messageName: "JobRecord",
+			text: `
+				metadata { valueType: JOB intent: "FAILED" }
+				type: "ship"
+				worker: "shipper"
+				retries: 2
+				errorMessage: "Out of boxes"
+				customHeaders {
+					fields { key: "carrier" value { string_value: "post" } }
+				}
+				retryBackoff: 5000
+				errorCode: "NO_BOXES"
+			`,
+			valueType: ValueTypeJob,
+			typed:     typedProtoValue[JobValue],
+			expected: JobValue{
+				Retries:       2,
+				RetryBackoff:  5000,
+				ErrorMessage:  "Out of boxes",
+				CustomHeaders: map[string]string{"carrier": "post"},
+				Variables:     map[string]any{},
+				Type:          "ship",
+				ErrorCode:     "NO_BOXES",
+				Worker:        "shipper",
+			},
+		},
+		{
+			name:        "Job batch",
+			messageName: "JobBatchRecord",
+			text: `
+				metadata { valueType: JOB_BATCH intent: "ACTIVATED" }
+				type: "ship"
+				worker: "shipper"
+				timeout: 300000
+				maxJobsToActivate: 32
+				jobKeys: [2251799813686320, 2251799813686321]
+				jobs { type: "ship" worker: "shipper" retries: 3 processInstanceKey: 2251799813686310 }
+				jobs { type: "ship" worker: "shipper" retries: 1 processInstanceKey: 2251799813686311 }
+				truncated: true
+			`,
+			valueType: ValueTypeJobBatch,
+			typed:     typedProtoValue[JobBatchValue],
+			expected: JobBatchValue{
+				Truncated:         true,
+				MaxJobsToActivate: 32,
+				Jobs: []JobValue{
+					{
+						ProcessInstanceKey: 2251799813686310,
+						Retries:            3,
+						CustomHeaders:      map[string]string{},
+						Variables:          map[string]any{},
+						Type:               "ship",
+						Worker:             "shipper",
+					},
+					{
+						ProcessInstanceKey: 2251799813686311,
+						Retries:            1,
+						CustomHeaders:      map[string]string{},
+						Variables:          map[string]any{},
+						Type:               "ship",
+						Worker:             "shipper",
+					},
+				},
+				Type:    "ship",
+				Timeout: 300000,
+				Worker:  "shipper",
+				JobKeys: []int64{2251799813686320, 2251799813686321},
+			},
+		},
+		{
+			name:        "Message",
+			messageName: "MessageRecord",
+			text: `
+				metadata { valueType: MESSAGE intent: "PUBLISHED" }
+				name: "payment-received"
+				correlationKey: "order-1"
+				messageId: "payment-1"
+				timeToLive: 60000
+				variables {
+					fields { key: "amount" value { number_value: 42 } }
+				}
+			`,
+			valueType: ValueTypeMessage,
+			typed:     typedProtoValue[MessageValue],
+			expected: MessageValue{
+				MessageID:      "payment-1",
+				Variables:      map[string]any{"amount": float64(42)},
+				CorrelationKey: "order-1",
+				Name:           "payment-received",
+				TimeToLive:     60000,
+			},
+		},
+		{
+			name:        "Message start event subscription",
+			messageName: "MessageStartEventSubscriptionRecord",
+			text: `
+				metadata { valueType: MESSAGE_START_EVENT_SUBSCRIPTION intent: "CORRELATED" }
+				processDefinitionKey: 2251799813685249
+				startEventId: "OrderPlaced"
+				messageName: "order-placed"
+				bpmnProcessId: "order"
+				correlationKey: "order-1"
+				messageKey: 2251799813686320
+				processInstanceKey: 2251799813686310
+			`,
+			valueType: ValueTypeMessageStartEventSubscription,
+			typed:     typedProtoValue[MessageStartEventSubscriptionValue],
+			expected: MessageStartEventSubscriptionValue{
+				ProcessDefinitionKey: 2251799813685249,
+				StartEventID:         "OrderPlaced",
+				MessageName:          "order-placed",
+				BpmnProcessID:        "order",
+				CorrelationKey:       "order-1",
+				MessageKey:           2251799813686320,
+				ProcessInstanceKey:   2251799813686310,
+				Variables:            map[string]any{},
+			},
+		},
+		{
+			name:        "Message subscription",
+			messageName: "MessageSubscriptionRecord",
+			text: `
+				metadata { valueType: MESSAGE_SUBSCRIPTION intent: "CORRELATED" }
+				processInstanceKey: 2251799813686310
+				elementInstanceKey: 2251799813686315
+				messageName: "payment-received"
+				correlationKey: "order-1"
+				bpmnProcessId: "order"
+				messageKey: 2251799813686320
+				isInterrupting: true
+			`,
+			valueType: ValueTypeMessageSubscription,
+			typed:     typedProtoValue[MessageSubscriptionValue],
+			expected: MessageSubscriptionValue{
+				ProcessInstanceKey: 2251799813686310,
+				ElementInstanceKey: 2251799813686315,
+				MessageKey:         2251799813686320,
+				MessageName:        "payment-received",
+				Interrupting:       true,
+				BpmnProcessID:      "order",
+				Variables:          map[string]any{},
+				CorrelationKey:     "order-1",
+			},
+		},
+		{
+			name:        "Process instance migration",
+			messageName: "ProcessInstanceMigrationRecord",
+			text: `
+				metadata { valueType: PROCESS_INSTANCE_MIGRATION intent: "MIGRATED" }
+				processInstanceKey: 2251799813686310
+				targetProcessDefinitionKey: 2251799813685250
+				mappingInstructions { sourceElementId: "Ship" targetElementId: "ShipOrder" }
+			`,
+			valueType: ValueTypeProcessInstanceMigration,
+			typed:     typedProtoValue[ProcessInstanceMigrationValue],
+			expected: ProcessInstanceMigrationValue{
+				ProcessInstanceKey:         2251799813686310,
+				TargetProcessDefinitionKey: 2251799813685250,
+				MappingInstructions: []ProcessInstanceMigrationValueMappingInstruction{
+					{SourceElementID: "Ship", TargetElementID: "ShipOrder"},
+				},
+			},
+		},
+		{
+			name:        "Process message subscription",
+			messageName: "ProcessMessageSubscriptionRecord",
+			text: `
+				metadata { valueType: PROCESS_MESSAGE_SUBSCRIPTION intent: "CORRELATED" }
+				processInstanceKey: 2251799813686310
+				elementInstanceKey: 2251799813686315
+				messageName: "payment-received"
+				variables {
+					fields { key: "orderId" value { string_value: "order-1" } }
+				}
+				correlationKey: "order-1"
+				bpmnProcessId: "SomeBPMN"
+				messageKey: 2251799813686320
+				elementId: "WaitForPayment"
+				isInterrupting: true
+			`,
+			valueType: ValueTypeProcessMessageSubscription,
+			typed:     typedProtoValue[ProcessMessageSubscriptionValue],
+			expected: ProcessMessageSubscriptionValue{
+				BpmnProcessID:      "SomeBPMN",
+				ElementInstanceKey: 2251799813686315,
+				ElementID:          "WaitForPayment",
+				Variables:          map[string]any{"orderId": "order-1"},
+				MessageKey:         2251799813686320,
+				MessageName:        "payment-received",
+				Interrupting:       true,
+				CorrelationKey:     "order-1",
+				ProcessInstanceKey: 2251799813686310,
+			},
+		},
+		{
+			name:        "Process",
+			messageName: "ProcessRecord",
+			text: `
+				metadata { valueType: PROCESS intent: "CREATED" }
+				bpmnProcessId: "order"
+				version: 2
+				processDefinitionKey: 2251799813685249
+				resourceName: "order.bpmn"
+				checksum: "sum"
+				resource: "<bpmn/>"
+			`,
+			valueType: ValueTypeProcess,
+			typed:     typedProtoValue[ProcessValue],
+			expected: ProcessValue{
+				BpmnProcessID:        "order",
+				Version:              2,
+				ProcessDefinitionKey: 2251799813685249,
+				ResourceName:         "order.bpmn",
+				Checksum:             []byte("sum"),
+				Resource:             []byte("<bpmn/>"),
+			},
+		},
+		{
+			name:        "Process instance",
+			messageName: "ProcessInstanceRecord",
+			text: `
+				metadata { valueType: PROCESS_INSTANCE intent: "ELEMENT_ACTIVATED" }
+				bpmnProcessId: "order"
+				version: 2
+				processDefinitionKey: 2251799813685249
+				processInstanceKey: 2251799813686310
+				elementId: "Ship"
+				flowScopeKey: 2251799813686310
+				bpmnElementType: "SERVICE_TASK"
+				parentProcessInstanceKey: -1
+				parentElementInstanceKey: -1
+			`,
+			valueType: ValueTypeProcessInstance,
+			typed:     typedProtoValue[ProcessInstanceValue],
+			expected: ProcessInstanceValue{
+				BpmnProcessID:            "order",
+				ProcessInstanceKey:       2251799813686310,
+				ProcessDefinitionKey:     2251799813685249,
+				ElementID:                "Ship",
+				FlowScopeKey:             2251799813686310,
+				ParentProcessInstanceKey: -1,
+				ParentElementInstanceKey: -1,
+				BpmnElementType:          BpmnElementTypeServiceTask,
+				Version:                  2,
+			},
+		},
+		{
+			name:        "Resource deletion",
+			messageName: "ResourceDeletionRecord",
+			text: `
+				metadata { valueType: RESOURCE_DELETION intent: "DELETED" }
+				resourceKey: 2251799813685249
+				tenantId: "<default>"
+			`,
+			valueType: ValueTypeResourceDeletion,
+			typed:     typedProtoValue[ResourceDeletionValue],
+			expected: ResourceDeletionValue{
+				ResourceKey: 2251799813685249,
+				TenantID:    "<default>",
+			},
+		},
+		{
+			name:        "Signal",
+			messageName: "SignalRecord",
+			text: `
+				metadata { valueType: SIGNAL intent: "BROADCASTED" }
+				signalName: "cancel-orders"
+				variables {
+					fields { key: "reason" value { string_value: "recall" } }
+				}
+				tenantId: "<default>"
+			`,
+			valueType: ValueTypeSignal,
+			typed:     typedProtoValue[SignalValue],
+			expected: SignalValue{
+				SignalName: "cancel-orders",
+				Variables:  map[string]any{"reason": "recall"},
+				TenantID:   "<default>",
+			},
+		},
+		{
+			name:        "Signal subscription",
+			messageName: "SignalSubscriptionRecord",
+			text: `
+				metadata { valueType: SIGNAL_SUBSCRIPTION intent: "CREATED" }
+				processDefinitionKey: 2251799813685249
+				bpmnProcessId: "order"
+				catchEventId: "Cancelled"
+				signalName: "cancel-orders"
+				catchEventInstanceKey: 2251799813686315
+				tenantId: "<default>"
+			`,
+			valueType: ValueTypeSignalSubscription,
+			typed:     typedProtoValue[SignalSubscriptionValue],
+			expected: SignalSubscriptionValue{
+				ProcessDefinitionKey:  2251799813685249,
+				BpmnProcessID:         "order",
+				CatchEventID:          "Cancelled",
+				CatchEventInstanceKey: 2251799813686315,
+				SignalName:            "cancel-orders",
+				TenantID:              "<default>",
+			},
+		},
+		{
+			name:        "Timer",
+			messageName: "TimerRecord",
+			text: `
+				metadata { valueType: TIMER intent: "CREATED" }
+				elementInstanceKey: 2251799813686315
+				dueDate: 1696758123056
+				repetitions: -1
+				targetElementId: "Wait"
+				processInstanceKey: 2251799813686310
+				processDefinitionKey: 2251799813686300
+			`,
+			valueType: ValueTypeTimer,
+			typed:     typedProtoValue[TimerValue],
+			expected: TimerValue{
+				TargetElementID:      "Wait",
+				ProcessInstanceKey:   2251799813686310,
+				ProcessDefinitionKey: 2251799813686300,
+				ElementInstanceKey:   2251799813686315,
+				DueDate:              1696758123056,
+				Repetitions:          -1,
+			},
+		},
+		{
+			name:        "User task",
+			messageName: "UserTaskRecord",
+			text: `
+				metadata { valueType: USER_TASK intent: "ASSIGNED" }
+				userTaskKey: 2251799813686330
+				assignee: "jane"
+				candidateGroups: "sales"
+				candidateGroups: "support"
+				dueDate: "2024-06-01T12:00:00Z"
+				formKey: 2251799813685260
+				customHeaders {
+					fields { key: "priority" value { string_value: "high" } }
+				}
+				bpmnProcessId: "order"
+				processDefinitionVersion: 2
+				processDefinitionKey: 2251799813685249
+				processInstanceKey: 2251799813686310
+				elementId: "Approve"
+				elementInstanceKey: 2251799813686315
+				tenantId: "<default>"
+				changedAttributes: "assignee"
+				action: "assign"
+			`,
+			valueType: ValueTypeUserTask,
+			typed:     typedProtoValue[UserTaskValue],
+			expected: UserTaskValue{
+				UserTaskKey:              2251799813686330,
+				Assignee:                 "jane",
+				CandidateGroupsList:      []string{"sales", "support"},
+				CandidateUsersList:       []string{},
+				DueDate:                  "2024-06-01T12:00:00Z",
+				FormKey:                  2251799813685260,
+				ChangedAttributes:        []string{"assignee"},
+				Variables:                map[string]any{},
+				CustomHeaders:            map[string]string{"priority": "high"},
+				Action:                   "assign",
+				ElementID:                "Approve",
+				ElementInstanceKey:       2251799813686315,
+				BpmnProcessID:            "order",
+				ProcessDefinitionVersion: 2,
+				ProcessDefinitionKey:     2251799813685249,
+				ProcessInstanceKey:       2251799813686310,
+				TenantID:                 "<default>",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			untypedRecord, err := parseRecordAs(RecordEncodingProtobuf, encodeProtoRecord(t, test.messageName, test.text))
+			assert.NoError(t, err)
+			assert.Equal(t, test.valueType, untypedRecord.ValueType)
+
+			value, err := test.typed(*untypedRecord)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func typedProtoValue[V NamedValueType](untypedRecord UntypedRecord) (any, error) {
+	record, err := WithTypedValue[V](untypedRecord)
+	return record.Value, err
+}
+
+func TestProtobufUndecodedValueType(t *testing.T) {
+	// Only the metadata of a DeploymentDistributionRecord, which has no
+	// decoder
+	distributionRecord := encodeProtoRecord(t, "JobRecord", `
+		metadata { key: 3 recordType: COMMAND intent: "DISTRIBUTE" valueType: DEPLOYMENT_DISTRIBUTION }
+	`)
+
+	untypedDistributionRecord, err := parseRecordAs(RecordEncodingProtobuf, distributionRecord)
+	assert.NoError(t, err)
+	assert.Equal(t, ValueTypeDeploymentDistribution, untypedDistributionRecord.ValueType)
+	assert.Equal(t, RecordTypeCommand, untypedDistributionRecord.RecordType)
+	assert.Equal(t, IntentDistribute, untypedDistributionRecord.Intent)
+	assert.Nil(t, untypedDistributionRecord.Value)
+
+	// Rather than being typed with zero values, it has no value at all
+	_, err = WithTypedValue[DeploymentDistributionValue](*untypedDistributionRecord)
+	assert.ErrorContains(t, err, "has no value")
+
+	// The updater doesn't handle the value type, so it's ignored as usual
+	storer := newFixedErrStorer(nil)
+	updater := &storageUpdater{storer: storer, reconciler: newReconciler()}
+	err = updater.applyMessage(message{
+		topic:    "zeebe",
+		value:    distributionRecord,
+		encoding: RecordEncodingProtobuf,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, storer.touched)
+}
+
+func TestProtobufInvalidRecord(t *testing.T) {
+	_, err := parseRecordAs(RecordEncodingProtobuf, []byte{0x0a, 0xff})
+	assert.Error(t, err)
+
+	// Enum values newer than the decoder
+	unknownValueType := encodeProtoRecord(t, "JobRecord", `metadata { valueType: 1000 }`)
+	_, err = parseRecordAs(RecordEncodingProtobuf, unknownValueType)
+	assert.ErrorContains(t, err, "unknown value type: 1000")
+}
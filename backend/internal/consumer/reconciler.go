@@ -0,0 +1,318 @@
+package consumer
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+)
+
+const (
+	// How often parked records are retried.
+	ReconcileInterval = 10 * time.Second
+	// How long a parked record is retried from memory. After that it's only
+	// retried from the pending queue in storage, once the row it's waiting
+	// for is created.
+	ReconcileTimeout = 5 * time.Minute
+)
+
+// Every topic is consumed in its own goroutine, so records can be applied
+// in a different order than they were produced in. A record updating a row
+// can for example arrive before the record creating it.
+//
+// Such records are parked under the key of the row they're waiting for and
+// applied once a record creating the row with that key is applied. Parked
+// records are stored in the pending queue in storage right away, in the same
+// transaction that commits their offset, so that they survive a restart. The
+// copy kept in memory only makes retrying them cheap until
+// `ReconcileTimeout` has passed.
+type parkedRecord struct {
+	msg    message
+	record *UntypedRecord
+	// When the record was first parked.
+	since time.Time
+}
+
+// Keeps track of parked records. Safe to use from multiple goroutines.
+type reconciler struct {
+	mutex  sync.Mutex
+	parked map[int64][]parkedRecord
+}
+
+func newReconciler() *reconciler {
+	return &reconciler{
+		parked: map[int64][]parkedRecord{},
+	}
+}
+
+// Park a record until the row with `key` is created.
+func (r *reconciler) park(key int64, record parkedRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.parked[key] = append(r.parked[key], record)
+}
+
+// Remove and return the records waiting for the row with `key`.
+func (r *reconciler) take(key int64) []parkedRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	records := r.parked[key]
+	delete(r.parked, key)
+	return records
+}
+
+// Remove and return all parked records.
+func (r *reconciler) takeAll() []parkedRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var records []parkedRecord
+	for _, parked := range r.parked {
+		records = append(records, parked...)
+	}
+	r.parked = map[int64][]parkedRecord{}
+	return records
+}
+
+// Returns whether applying a record creates the row identified by its row
+// key, which records updating the row may be waiting for.
+func createsRow(record *UntypedRecord) bool {
+	return record.Intent == IntentCreated ||
+		record.Intent == IntentElementActivating ||
+		record.Intent == IntentElementActivated ||
+		record.Intent == IntentPublished
+}
+
+// Returns the key of the row a record creates or updates. Records updating
+// a row are parked under this key, and the record creating the row releases
+// them by it.
+//
+// Process level records of an instance update the instance row, which is
+// keyed by the instance key. Subscription records correlating a message
+// update the message row, which is keyed by the message key. Other rows, e.g.
+// those of element instances, variables, jobs, incidents and timers, are
+// keyed by the key of the records creating and updating them. They don't
+// wait for the instance they belong to.
+func rowKey(record *UntypedRecord) int64 {
+	switch record.ValueType { // nolint:exhaustive
+	case ValueTypeProcessInstance:
+		instance, err := WithTypedValue[ProcessInstanceValue](*record)
+		if err == nil && instance.Value.BpmnElementType == BpmnElementTypeProcess {
+			return instance.Value.ProcessInstanceKey
+		}
+	case ValueTypeMessageSubscription:
+		subscription, err := WithTypedValue[MessageSubscriptionValue](*record)
+		if err == nil && record.Intent == IntentCorrelating && subscription.Value.MessageKey != 0 {
+			return subscription.Value.MessageKey
+		}
+	case ValueTypeMessageStartEventSubscription:
+		subscription, err := WithTypedValue[MessageStartEventSubscriptionValue](*record)
+		if err == nil && record.Intent == IntentCorrelated {
+			return subscription.Value.MessageKey
+		}
+	}
+
+	return record.Key
+}
+
+// Apply a record, parking it if the row it updates doesn't exist yet.
+func (u *storageUpdater) apply(msg message, record *UntypedRecord, since time.Time) error {
+	created, err := u.applyOrPark(msg, record, since)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		u.release(rowKey(record))
+	}
+
+	return nil
+}
+
+// Apply a record, parking it if the row it updates doesn't exist yet.
+// Returns whether the record created a row parked records may be waiting
+// for; releasing them is up to the caller.
+func (u *storageUpdater) applyOrPark(msg message, record *UntypedRecord, since time.Time) (bool, error) {
+	err := u.applyInTransaction(record)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return false, u.park(parkedRecord{msg, record, since})
+		}
+		return false, err
+	}
+
+	return createsRow(record), nil
+}
+
+// Park a record until the row it updates is created. The record is stored in
+// the pending queue through the updater's storer, so within a batch it's
+// committed along with the offset of the record.
+func (u *storageUpdater) park(parked parkedRecord) error {
+	key := rowKey(parked.record)
+	log.Printf("Parking %v record %d until row %d is created",
+		parked.record.ValueType, parked.record.Key, key)
+
+	err := u.storePending(parked)
+	if err != nil {
+		return err
+	}
+
+	u.reconciler.park(key, parked)
+	return nil
+}
+
+// Apply a record in a transaction of its own, so that a record failing
+// halfway leaves nothing behind. Within a batch this is a nested transaction.
+func (u *storageUpdater) applyInTransaction(record *UntypedRecord) error {
+	return u.storer.Transaction(func(storer storage.Storer) error {
+		return u.withStorer(storer).handlingDispatch(record)
+	})
+}
+
+// Retry applying a parked record from memory. Returns false if the row it's
+// waiting for still doesn't exist. Otherwise the record is removed from the
+// pending queue, having been applied or stored as a dead letter.
+func (u *storageUpdater) retryParked(parked parkedRecord) bool {
+	err := u.applyInTransaction(parked.record)
+	if storage.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Handling parked record failed: %v", err)
+		u.storeDeadLetter(parked.msg, err)
+	}
+
+	resolveErr := u.storer.PendingRecordResolved(
+		parked.msg.topic, parked.msg.partition, parked.msg.offset)
+	if resolveErr != nil {
+		log.Printf("Failed to remove pending record: %v", resolveErr)
+	}
+
+	if err == nil && createsRow(parked.record) {
+		u.release(rowKey(parked.record))
+	}
+
+	return true
+}
+
+// Apply the records waiting for the row with `key`, both parked and pending.
+func (u *storageUpdater) release(key int64) {
+	for _, parked := range u.reconciler.take(key) {
+		if !u.retryParked(parked) {
+			u.reconciler.park(key, parked)
+		}
+	}
+
+	pendingRecords, err := u.storer.PendingRecords(key)
+	if err != nil {
+		log.Printf("Failed to find pending records: %v", err)
+		return
+	}
+
+	for _, pendingRecord := range pendingRecords {
+		_, err := u.retryPending(pendingRecord)
+		if err != nil {
+			log.Printf("Failed to retry pending record: %v", err)
+		}
+	}
+}
+
+// Retry applying a record from the pending queue. Returns whether it was
+// applied, in which case it's removed from the queue. Records that fail for
+// any other reason than the missing row are moved to the dead letters.
+func (u *storageUpdater) retryPending(pendingRecord storage.PendingRecord) (bool, error) {
+	msg := message{
+		topic:     pendingRecord.Topic,
+		partition: pendingRecord.Partition,
+		offset:    pendingRecord.Offset,
+		value:     pendingRecord.Payload,
+	}
+
+	record, err := parseRecord(pendingRecord.Payload)
+	if err == nil {
+		err = u.applyInTransaction(record)
+	}
+	if storage.IsNotFound(err) {
+		// Still waiting, keep it pending
+		return false, nil
+	}
+	if err != nil {
+		log.Printf("Handling pending record failed: %v", err)
+		u.storeDeadLetter(msg, err)
+	}
+
+	removeErr := u.storer.PendingRecordRemoved(pendingRecord.ID)
+	if removeErr != nil {
+		return false, removeErr
+	}
+
+	if err != nil {
+		return false, nil
+	}
+	if createsRow(record) {
+		u.release(rowKey(record))
+	}
+
+	return true, nil
+}
+
+// Periodically retry parked records until the updater is closed. Records
+// that are still parked on close are already in the pending queue, so they
+// can simply be dropped from memory.
+func (u *storageUpdater) reconcileLoop() {
+	ticker := time.NewTicker(ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-u.closeChannel:
+			u.reconciler.takeAll()
+			return
+		case now := <-ticker.C:
+			u.reconcile(now)
+		}
+	}
+}
+
+// Retry every parked record. Records which still can't be applied and have
+// been parked for longer than `ReconcileTimeout` are left to the pending
+// queue.
+//
+// Retrying also covers records parked just after the row they were waiting
+// for was created.
+func (u *storageUpdater) reconcile(now time.Time) {
+	for _, parked := range u.reconciler.takeAll() {
+		if u.retryParked(parked) {
+			continue
+		}
+
+		if now.Sub(parked.since) >= ReconcileTimeout {
+			log.Printf("Leaving %v record %d to the pending queue",
+				parked.record.ValueType, parked.record.Key)
+			continue
+		}
+		u.reconciler.park(rowKey(parked.record), parked)
+	}
+}
+
+// Store a parked record in the pending queue. Storing the same record again
+// does nothing.
+func (u *storageUpdater) storePending(parked parkedRecord) error {
+	err := u.storer.PendingRecordStored(
+		parked.msg.topic,
+		parked.msg.partition,
+		parked.msg.offset,
+		parked.msg.value,
+		rowKey(parked.record),
+		parked.since,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store pending record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package consumer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Storer that only finds jobs which have been created.
+type jobOrderStorer struct {
+	*fixedErrStorer
+	jobs map[int64]bool
+}
+
+func newJobOrderStorer() *jobOrderStorer {
+	return &jobOrderStorer{
+		newFixedErrStorer(nil),
+		map[int64]bool{},
+	}
+}
+
+func (s *jobOrderStorer) Transaction(fn func(storage.Storer) error) error {
+	return fn(s)
+}
+
+func (s *jobOrderStorer) JobCreated(_ int64, key int64, _ string, _ int64, _ int64, _ string, _ int64, _ string, _ string, _ time.Time) error {
+	s.touched["JobCreated"] = true
+	s.jobs[key] = true
+	return nil
+}
+
+func (s *jobOrderStorer) JobUpdated(_ int64, key int64, _ int64, _ string, _ string, _ string, _ string, _ sql.NullTime, _ int64, _ time.Time) error {
+	if !s.jobs[key] {
+		return fmt.Errorf("failed to find job: %w", gorm.ErrRecordNotFound)
+	}
+	s.touched["JobUpdated"] = true
+	return nil
+}
+
+func TestParkedRecordReleased(t *testing.T) {
+	storer := newJobOrderStorer()
+	updater := &storageUpdater{
+		storer:     storer,
+		reconciler: newReconciler(),
+	}
+
+	updated := newJobTestRecord("JobCompleted", IntentCompleted, nil, nil)
+	err := updater.apply(message{}, updated.record, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, storer.touched["JobUpdated"])
+	assert.Len(t, updater.reconciler.parked[updated.record.Key], 1)
+	// Parked records are stored in the pending queue right away
+	assert.True(t, storer.touched["PendingRecordStored"])
+
+	created := newJobTestRecord("JobCreated", IntentCreated, nil, nil)
+	err = updater.apply(message{}, created.record, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, storer.touched["JobCreated"])
+	assert.True(t, storer.touched["PendingRecords"])
+
+	// The update is applied once the job exists
+	assert.True(t, storer.touched["JobUpdated"])
+	assert.True(t, storer.touched["PendingRecordResolved"])
+	assert.Empty(t, updater.reconciler.parked)
+}
+
+func TestReconcile(t *testing.T) {
+	storer := newJobOrderStorer()
+	updater := &storageUpdater{
+		storer:     storer,
+		reconciler: newReconciler(),
+	}
+
+	start := time.Now()
+	updated := newJobTestRecord("JobCompleted", IntentCompleted, nil, nil)
+	err := updater.apply(message{}, updated.record, start)
+	assert.NoError(t, err)
+
+	t.Run("still parked", func(t *testing.T) {
+		updater.reconcile(start.Add(ReconcileInterval))
+		assert.Len(t, updater.reconciler.parked[updated.record.Key], 1)
+	})
+
+	t.Run("left to pending queue", func(t *testing.T) {
+		updater.reconcile(start.Add(ReconcileTimeout))
+		assert.Empty(t, updater.reconciler.parked)
+		assert.False(t, storer.touched["PendingRecordResolved"])
+	})
+}
+
+// Storer that only finds process instances which have been activated.
+type instanceOrderStorer struct {
+	*fixedErrStorer
+	instances map[int64]bool
+}
+
+func (s *instanceOrderStorer) Transaction(fn func(storage.Storer) error) error {
+	return fn(s)
+}
+
+func (s *instanceOrderStorer) ProcessInstanceActivated(_ int64, processInstanceKey int64, _ int64, _ int64, _ int64, _ int64, _ time.Time) error {
+	s.touched["ProcessInstanceActivated"] = true
+	s.instances[processInstanceKey] = true
+	return nil
+}
+
+func (s *instanceOrderStorer) ProcessInstanceCompleted(_ int64, processInstanceKey int64, _ time.Time) error {
+	if !s.instances[processInstanceKey] {
+		return fmt.Errorf("failed to find process instance: %w", gorm.ErrRecordNotFound)
+	}
+	s.touched["ProcessInstanceCompleted"] = true
+	return nil
+}
+
+// Test that records updating an instance are parked under the instance key.
+func TestParkedByRowKey(t *testing.T) {
+	storer := &instanceOrderStorer{newFixedErrStorer(nil), map[int64]bool{}}
+	updater := &storageUpdater{
+		storer:     storer,
+		reconciler: newReconciler(),
+	}
+
+	record := func(intent Intent) *UntypedRecord {
+		value, err := json.Marshal(ProcessInstanceValue{
+			BpmnElementType:    BpmnElementTypeProcess,
+			ProcessInstanceKey: 100,
+		})
+		assert.NoError(t, err)
+		return &UntypedRecord{
+			ValueType: ValueTypeProcessInstance,
+			Intent:    intent,
+			// Not the key of the instance, the instance key is
+			// what counts
+			Key:   200,
+			Value: value,
+		}
+	}
+
+	completed := record(IntentElementCompleted)
+	err := updater.apply(message{}, completed, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, updater.reconciler.parked[100], 1)
+
+	err = updater.apply(message{}, record(IntentElementActivated), time.Now())
+	assert.NoError(t, err)
+	assert.True(t, storer.touched["ProcessInstanceCompleted"])
+	assert.Empty(t, updater.reconciler.parked)
+}
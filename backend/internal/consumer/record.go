@@ -45,13 +45,19 @@ const (
 	IntentActivate                 Intent = "ACTIVATE"
 	IntentActivated                Intent = "ACTIVATED"
 	IntentActivateElement          Intent = "ACTIVATE_ELEMENT"
+	IntentAssign                   Intent = "ASSIGN"
+	IntentAssigned                 Intent = "ASSIGNED"
+	IntentAssigning                Intent = "ASSIGNING"
 	IntentBroadcast                Intent = "BROADCAST"
 	IntentBroadcasted              Intent = "BROADCASTED"
 	IntentCancel                   Intent = "CANCEL"
 	IntentCanceled                 Intent = "CANCELED"
+	IntentCanceling                Intent = "CANCELING"
+	IntentClaim                    Intent = "CLAIM"
 	IntentComplete                 Intent = "COMPLETE"
 	IntentCompleted                Intent = "COMPLETED"
 	IntentCompleteElement          Intent = "COMPLETE_ELEMENT"
+	IntentCompleting               Intent = "COMPLETING"
 	IntentCorrelate                Intent = "CORRELATE"
 	IntentCorrelated               Intent = "CORRELATED"
 	IntentCorrelating              Intent = "CORRELATING"
@@ -81,6 +87,8 @@ const (
 	IntentFailed                   Intent = "FAILED"
 	IntentFinished                 Intent = "FINISHED"
 	IntentFullyDistributed         Intent = "FULLY_DISTRIBUTED"
+	IntentMigrate                  Intent = "MIGRATE"
+	IntentMigrated                 Intent = "MIGRATED"
 	IntentModified                 Intent = "MODIFIED"
 	IntentModify                   Intent = "MODIFY"
 	IntentNotEscalated             Intent = "NOT_ESCALATED"
@@ -106,6 +114,7 @@ const (
 	IntentUpdate                   Intent = "UPDATE"
 	IntentUpdated                  Intent = "UPDATED"
 	IntentUpdateRetries            Intent = "UPDATE_RETRIES"
+	IntentUpdating                 Intent = "UPDATING"
 	IntentYield                    Intent = "YIELD"
 	IntentYielded                  Intent = "YIELDED"
 )
@@ -149,6 +158,8 @@ func (v ValueType) String() string {
 }
 
 const (
+	ValueTypeCheckpoint                    ValueType = "CHECKPOINT"
+	ValueTypeCommandDistribution           ValueType = "COMMAND_DISTRIBUTION"
 	ValueTypeDecision                      ValueType = "DECISION"
 	ValueTypeDecisionEvaluation            ValueType = "DECISION_EVALUATION"
 	ValueTypeDecisionRequirements          ValueType = "DECISION_REQUIREMENTS"
@@ -156,20 +167,28 @@ const (
 	ValueTypeDeploymentDistribution        ValueType = "DEPLOYMENT_DISTRIBUTION"
 	ValueTypeError                         ValueType = "ERROR"
 	ValueTypeEscalation                    ValueType = "ESCALATION"
+	ValueTypeForm                          ValueType = "FORM"
 	ValueTypeIncident                      ValueType = "INCIDENT"
 	ValueTypeJob                           ValueType = "JOB"
 	ValueTypeJobBatch                      ValueType = "JOB_BATCH"
 	ValueTypeMessage                       ValueType = "MESSAGE"
+	ValueTypeMessageBatch                  ValueType = "MESSAGE_BATCH"
 	ValueTypeMessageStartEventSubscription ValueType = "MESSAGE_START_EVENT_SUBSCRIPTION"
 	ValueTypeMessageSubscription           ValueType = "MESSAGE_SUBSCRIPTION"
 	ValueTypeProcess                       ValueType = "PROCESS"
 	ValueTypeProcessEvent                  ValueType = "PROCESS_EVENT"
 	ValueTypeProcessInstance               ValueType = "PROCESS_INSTANCE"
+	ValueTypeProcessInstanceBatch          ValueType = "PROCESS_INSTANCE_BATCH"
 	ValueTypeProcessInstanceCreation       ValueType = "PROCESS_INSTANCE_CREATION"
+	ValueTypeProcessInstanceMigration      ValueType = "PROCESS_INSTANCE_MIGRATION"
 	ValueTypeProcessInstanceModification   ValueType = "PROCESS_INSTANCE_MODIFICATION"
 	ValueTypeProcessInstanceResult         ValueType = "PROCESS_INSTANCE_RESULT"
 	ValueTypeProcessMessageSubscription    ValueType = "PROCESS_MESSAGE_SUBSCRIPTION"
+	ValueTypeResourceDeletion              ValueType = "RESOURCE_DELETION"
+	ValueTypeSignal                        ValueType = "SIGNAL"
+	ValueTypeSignalSubscription            ValueType = "SIGNAL_SUBSCRIPTION"
 	ValueTypeTimer                         ValueType = "TIMER"
+	ValueTypeUserTask                      ValueType = "USER_TASK"
 	ValueTypeVariable                      ValueType = "VARIABLE"
 	ValueTypeVariableDocument              ValueType = "VARIABLE_DOCUMENT"
 )
@@ -208,6 +227,12 @@ func WithTypedValue[V NamedValueType](untyped UntypedRecord) (Record[V], error)
 		return Record[V]{}, fmt.Errorf("record: cannot convert '%s' into '%s'", untyped.ValueType, targetValueType)
 	}
 
+	// Records whose value couldn't be decoded, e.g. protobuf records of value
+	// types without a decoder, have none
+	if untyped.Value == nil {
+		return Record[V]{}, fmt.Errorf("record: '%s' record has no value", untyped.ValueType)
+	}
+
 	if err := json.Unmarshal(untyped.Value, &value); err != nil {
 		return Record[V]{}, fmt.Errorf("record: %w", err)
 	}
@@ -230,9 +255,12 @@ func WithTypedValue[V NamedValueType](untyped UntypedRecord) (Record[V], error)
 
 // All possible record types.
 
+type DecisionEvaluation = Record[DecisionEvaluationValue]
 type Deployment = Record[DeploymentValue]
 type DeploymentDistribution = Record[DeploymentDistributionValue]
 type Error = Record[ErrorValue]
+type Escalation = Record[EscalationValue]
+type Form = Record[FormValue]
 type Incident = Record[IncidentValue]
 type Job = Record[JobValue]
 type JobBatch = Record[JobBatchValue]
@@ -242,9 +270,14 @@ type MessageSubscription = Record[MessageSubscriptionValue]
 type Process = Record[ProcessValue]
 type ProcessEvent = Record[ProcessEventValue]
 type ProcessInstance = Record[ProcessInstanceValue]
+type ProcessInstanceMigration = Record[ProcessInstanceMigrationValue]
 type ProcessInstanceResult = Record[ProcessInstanceResultValue]
 type ProcessMessageSubscription = Record[ProcessMessageSubscriptionValue]
+type ResourceDeletion = Record[ResourceDeletionValue]
+type Signal = Record[SignalValue]
+type SignalSubscription = Record[SignalSubscriptionValue]
 type Timer = Record[TimerValue]
+type UserTask = Record[UserTaskValue]
 type Variable = Record[VariableValue]
 
 // NamedValueType is an interface for all record types which have known
@@ -253,12 +286,36 @@ type NamedValueType interface {
 	ValueType() ValueType
 }
 
+// DecisionEvaluation record's 'value' field.
+type DecisionEvaluationValue struct {
+	DecisionKey              int64                                      `json:"decisionKey"`
+	DecisionID               string                                     `json:"decisionId"`
+	DecisionName             string                                     `json:"decisionName"`
+	DecisionVersion          int64                                      `json:"decisionVersion"`
+	DecisionRequirementsID   string                                     `json:"decisionRequirementsId"`
+	DecisionRequirementsKey  int64                                      `json:"decisionRequirementsKey"`
+	DecisionOutput           string                                     `json:"decisionOutput"`
+	BpmnProcessID            string                                     `json:"bpmnProcessId"`
+	ProcessDefinitionKey     int64                                      `json:"processDefinitionKey"`
+	ProcessInstanceKey       int64                                      `json:"processInstanceKey"`
+	ElementID                string                                     `json:"elementId"`
+	ElementInstanceKey       int64                                      `json:"elementInstanceKey"`
+	EvaluatedDecisions       []DecisionEvaluationValueEvaluatedDecision `json:"evaluatedDecisions"`
+	EvaluationFailureMessage string                                     `json:"evaluationFailureMessage"`
+	FailedDecisionID         string                                     `json:"failedDecisionId"`
+}
+
+func (DecisionEvaluationValue) ValueType() ValueType {
+	return ValueTypeDecisionEvaluation
+}
+
 // Deployment record's 'value' field.
 type DeploymentValue struct {
 	Resources                    []DeploymentValueResource                     `json:"resources"`
 	ProcessesMetadata            []DeploymentValueProcessesMetadata            `json:"processesMetadata"`
 	DecisionRequirementsMetadata []DeploymentValueDecisionRequirementsMetadata `json:"decisionRequirementsMetadata"`
 	DecisionsMetadata            []DeploymentValueDecisionsMetadata            `json:"decisionsMetadata"`
+	FormMetadata                 []DeploymentValueFormMetadata                 `json:"formMetadata"`
 }
 
 func (DeploymentValue) ValueType() ValueType {
@@ -286,6 +343,35 @@ func (ErrorValue) ValueType() ValueType {
 	return ValueTypeError
 }
 
+// Escalation record's 'value' field.
+type EscalationValue struct {
+	ProcessInstanceKey int64  `json:"processInstanceKey"`
+	EscalationCode     string `json:"escalationCode"`
+	ThrowElementID     string `json:"throwElementId"`
+	CatchElementID     string `json:"catchElementId"`
+	TenantID           string `json:"tenantId"`
+}
+
+func (EscalationValue) ValueType() ValueType {
+	return ValueTypeEscalation
+}
+
+// Form record's 'value' field.
+type FormValue struct {
+	FormID       string `json:"formId"`
+	Version      int64  `json:"version"`
+	FormKey      int64  `json:"formKey"`
+	ResourceName string `json:"resourceName"`
+	Checksum     []byte `json:"checksum"`
+	Resource     []byte `json:"resource"`
+	Duplicate    bool   `json:"duplicate"`
+	TenantID     string `json:"tenantId"`
+}
+
+func (FormValue) ValueType() ValueType {
+	return ValueTypeForm
+}
+
 // Incident record's 'value' field.
 type IncidentValue struct {
 	ErrorType            string `json:"errorType"`
@@ -329,13 +415,13 @@ func (JobValue) ValueType() ValueType {
 
 // JobBatch record's 'value' field.
 type JobBatchValue struct {
-	Truncated         bool    `json:"truncated"`
-	MaxJobsToActivate int64   `json:"maxJobsToActivate"`
-	Jobs              []Job   `json:"jobs"`
-	Type              string  `json:"type"`
-	Timeout           int64   `json:"timeout"`
-	Worker            string  `json:"worker"`
-	JobKeys           []int64 `json:"jobKeys"`
+	Truncated         bool       `json:"truncated"`
+	MaxJobsToActivate int64      `json:"maxJobsToActivate"`
+	Jobs              []JobValue `json:"jobs"`
+	Type              string     `json:"type"`
+	Timeout           int64      `json:"timeout"`
+	Worker            string     `json:"worker"`
+	JobKeys           []int64    `json:"jobKeys"`
 }
 
 func (JobBatchValue) ValueType() ValueType {
@@ -434,6 +520,17 @@ func (ProcessInstanceValue) ValueType() ValueType {
 	return ValueTypeProcessInstance
 }
 
+// ProcessInstanceMigration record's 'value' field.
+type ProcessInstanceMigrationValue struct {
+	ProcessInstanceKey         int64                                             `json:"processInstanceKey"`
+	TargetProcessDefinitionKey int64                                             `json:"targetProcessDefinitionKey"`
+	MappingInstructions        []ProcessInstanceMigrationValueMappingInstruction `json:"mappingInstructions"`
+}
+
+func (ProcessInstanceMigrationValue) ValueType() ValueType {
+	return ValueTypeProcessInstanceMigration
+}
+
 // ProcessInstanceResult record's 'value' field.
 type ProcessInstanceResultValue struct {
 	BpmnProcessID        string         `json:"bpmnProcessId"`
@@ -464,6 +561,41 @@ func (ProcessMessageSubscriptionValue) ValueType() ValueType {
 	return ValueTypeProcessMessageSubscription
 }
 
+// ResourceDeletion record's 'value' field.
+type ResourceDeletionValue struct {
+	ResourceKey int64  `json:"resourceKey"`
+	TenantID    string `json:"tenantId"`
+}
+
+func (ResourceDeletionValue) ValueType() ValueType {
+	return ValueTypeResourceDeletion
+}
+
+// Signal record's 'value' field.
+type SignalValue struct {
+	SignalName string         `json:"signalName"`
+	Variables  map[string]any `json:"variables"`
+	TenantID   string         `json:"tenantId"`
+}
+
+func (SignalValue) ValueType() ValueType {
+	return ValueTypeSignal
+}
+
+// SignalSubscription record's 'value' field.
+type SignalSubscriptionValue struct {
+	ProcessDefinitionKey  int64  `json:"processDefinitionKey"`
+	BpmnProcessID         string `json:"bpmnProcessId"`
+	CatchEventID          string `json:"catchEventId"`
+	CatchEventInstanceKey int64  `json:"catchEventInstanceKey"`
+	SignalName            string `json:"signalName"`
+	TenantID              string `json:"tenantId"`
+}
+
+func (SignalSubscriptionValue) ValueType() ValueType {
+	return ValueTypeSignalSubscription
+}
+
 // Timer record's 'value' field.
 type TimerValue struct {
 	TargetElementID      string `json:"targetElementId"`
@@ -478,6 +610,34 @@ func (TimerValue) ValueType() ValueType {
 	return ValueTypeTimer
 }
 
+// UserTask record's 'value' field.
+type UserTaskValue struct {
+	UserTaskKey         int64    `json:"userTaskKey"`
+	Assignee            string   `json:"assignee"`
+	CandidateGroupsList []string `json:"candidateGroupsList"`
+	CandidateUsersList  []string `json:"candidateUsersList"`
+	// ISO 8601 date times, empty if not set.
+	DueDate                  string            `json:"dueDate"`
+	FollowUpDate             string            `json:"followUpDate"`
+	FormKey                  int64             `json:"formKey"`
+	ExternalFormReference    string            `json:"externalFormReference"`
+	ChangedAttributes        []string          `json:"changedAttributes"`
+	Variables                map[string]any    `json:"variables"`
+	CustomHeaders            map[string]string `json:"customHeaders"`
+	Action                   string            `json:"action"`
+	ElementID                string            `json:"elementId"`
+	ElementInstanceKey       int64             `json:"elementInstanceKey"`
+	BpmnProcessID            string            `json:"bpmnProcessId"`
+	ProcessDefinitionVersion int64             `json:"processDefinitionVersion"`
+	ProcessDefinitionKey     int64             `json:"processDefinitionKey"`
+	ProcessInstanceKey       int64             `json:"processInstanceKey"`
+	TenantID                 string            `json:"tenantId"`
+}
+
+func (UserTaskValue) ValueType() ValueType {
+	return ValueTypeUserTask
+}
+
 // Variable record's 'value' field.
 type VariableValue struct {
 	ProcessInstanceKey   int64  `json:"processInstanceKey"`
@@ -492,6 +652,37 @@ func (VariableValue) ValueType() ValueType {
 	return ValueTypeVariable
 }
 
+// Detail types for DecisionEvaluationValue.
+
+type DecisionEvaluationValueEvaluatedDecision struct {
+	DecisionID      string                                  `json:"decisionId"`
+	DecisionName    string                                  `json:"decisionName"`
+	DecisionKey     int64                                   `json:"decisionKey"`
+	DecisionVersion int64                                   `json:"decisionVersion"`
+	DecisionType    string                                  `json:"decisionType"`
+	DecisionOutput  string                                  `json:"decisionOutput"`
+	EvaluatedInputs []DecisionEvaluationValueEvaluatedInput `json:"evaluatedInputs"`
+	MatchedRules    []DecisionEvaluationValueMatchedRule    `json:"matchedRules"`
+}
+
+type DecisionEvaluationValueEvaluatedInput struct {
+	InputID    string `json:"inputId"`
+	InputName  string `json:"inputName"`
+	InputValue string `json:"inputValue"`
+}
+
+type DecisionEvaluationValueMatchedRule struct {
+	RuleID           string                                   `json:"ruleId"`
+	RuleIndex        int64                                    `json:"ruleIndex"`
+	EvaluatedOutputs []DecisionEvaluationValueEvaluatedOutput `json:"evaluatedOutputs"`
+}
+
+type DecisionEvaluationValueEvaluatedOutput struct {
+	OutputID    string `json:"outputId"`
+	OutputName  string `json:"outputName"`
+	OutputValue string `json:"outputValue"`
+}
+
 // Detail types for DeploymentValue.
 
 type DeploymentValueResource struct {
@@ -528,3 +719,19 @@ type DeploymentValueDecisionsMetadata struct {
 	DecisionRequirementsKey int64  `json:"decisionRequirementsKey"`
 	Duplicate               bool   `json:"duplicate"`
 }
+
+type DeploymentValueFormMetadata struct {
+	FormID       string `json:"formId"`
+	Version      int64  `json:"version"`
+	FormKey      int64  `json:"formKey"`
+	ResourceName string `json:"resourceName"`
+	Checksum     []byte `json:"checksum"`
+	Duplicate    bool   `json:"duplicate"`
+}
+
+// Detail types for ProcessInstanceMigrationValue.
+
+type ProcessInstanceMigrationValueMappingInstruction struct {
+	SourceElementID string `json:"sourceElementId"`
+	TargetElementID string `json:"targetElementId"`
+}
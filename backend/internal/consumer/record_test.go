@@ -99,7 +99,7 @@ var expectedJobBatchRecord = JobBatch{
 	Value: JobBatchValue{
 		Truncated:         false,
 		MaxJobsToActivate: 1664,
-		Jobs:              []Job{},
+		Jobs:              []JobValue{},
 		Type:              "someType",
 		Timeout:           30000,
 		Worker:            "aWorker",
@@ -441,6 +441,114 @@ var expectedVariableRecord = Variable{
 	BrokerVersion:        "8.2.15",
 }
 
+const userTaskRecordJSON = `{
+		"partitionId": 1,
+		"value": {
+				"userTaskKey": 2251799813685423,
+				"assignee": "demo",
+				"candidateGroupsList": ["accounting"],
+				"candidateUsersList": [],
+				"dueDate": "2024-05-01T12:00:00Z",
+				"followUpDate": "",
+				"formKey": 2251799813685249,
+				"externalFormReference": "",
+				"changedAttributes": ["assignee"],
+				"variables": {},
+				"customHeaders": {},
+				"action": "assign",
+				"elementId": "ApproveInvoice",
+				"elementInstanceKey": 2251799813685421,
+				"bpmnProcessId": "invoice",
+				"processDefinitionVersion": 3,
+				"processDefinitionKey": 2251799813685250,
+				"processInstanceKey": 2251799813685416,
+				"tenantId": "<default>"
+		},
+		"rejectionType": "NULL_VAL",
+		"rejectionReason": "",
+		"sourceRecordPosition": 60,
+		"key": 2251799813685423,
+		"timestamp": 1714392000000,
+		"position": 62,
+		"valueType": "USER_TASK",
+		"recordType": "EVENT",
+		"intent": "ASSIGNED",
+		"brokerVersion": "8.5.0"
+}`
+
+var expectedUserTaskRecord = UserTask{
+	PartitionID: 1,
+	Value: UserTaskValue{
+		UserTaskKey:              2251799813685423,
+		Assignee:                 "demo",
+		CandidateGroupsList:      []string{"accounting"},
+		CandidateUsersList:       []string{},
+		DueDate:                  "2024-05-01T12:00:00Z",
+		FollowUpDate:             "",
+		FormKey:                  2251799813685249,
+		ExternalFormReference:    "",
+		ChangedAttributes:        []string{"assignee"},
+		Variables:                map[string]any{},
+		CustomHeaders:            map[string]string{},
+		Action:                   "assign",
+		ElementID:                "ApproveInvoice",
+		ElementInstanceKey:       2251799813685421,
+		BpmnProcessID:            "invoice",
+		ProcessDefinitionVersion: 3,
+		ProcessDefinitionKey:     2251799813685250,
+		ProcessInstanceKey:       2251799813685416,
+		TenantID:                 "<default>",
+	},
+	RejectionType:        RejectionTypeNullVal,
+	RejectionReason:      "",
+	SourceRecordPosition: 60,
+	Key:                  2251799813685423,
+	Timestamp:            1714392000000,
+	Position:             62,
+	ValueType:            ValueTypeUserTask,
+	RecordType:           RecordTypeEvent,
+	Intent:               IntentAssigned,
+	BrokerVersion:        "8.5.0",
+}
+
+const signalRecordJSON = `{
+		"partitionId": 1,
+		"value": {
+				"signalName": "cancelOrders",
+				"variables": {"reason": "recall"},
+				"tenantId": "<default>"
+		},
+		"rejectionType": "NULL_VAL",
+		"rejectionReason": "",
+		"sourceRecordPosition": 70,
+		"key": 2251799813685500,
+		"timestamp": 1714392000000,
+		"position": 71,
+		"valueType": "SIGNAL",
+		"recordType": "EVENT",
+		"intent": "BROADCASTED",
+		"brokerVersion": "8.5.0"
+}`
+
+var expectedSignalRecord = Signal{
+	PartitionID: 1,
+	Value: SignalValue{
+		SignalName: "cancelOrders",
+		Variables:  map[string]any{"reason": "recall"},
+		TenantID:   "<default>",
+	},
+	RejectionType:        RejectionTypeNullVal,
+	RejectionReason:      "",
+	SourceRecordPosition: 70,
+	Key:                  2251799813685500,
+	Timestamp:            1714392000000,
+	Position:             71,
+	ValueType:            ValueTypeSignal,
+	RecordType:           RecordTypeEvent,
+	Intent:               IntentBroadcasted,
+	BrokerVersion:        "8.5.0",
+}
+
 func TestUntypedRecord(t *testing.T) {
 	// Delay value unmarshaling until we know the value type.
 	var untypedJobRecord UntypedRecord
@@ -536,6 +644,22 @@ func TestVariableRecord(t *testing.T) {
 	assert.Equal(t, expectedVariableRecord, variableRecord)
 }
 
+func TestUserTaskRecord(t *testing.T) {
+	var userTaskRecord UserTask
+	err := json.Unmarshal([]byte(userTaskRecordJSON), &userTaskRecord)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUserTaskRecord, userTaskRecord)
+}
+
+func TestSignalRecord(t *testing.T) {
+	var signalRecord Signal
+	err := json.Unmarshal([]byte(signalRecordJSON), &signalRecord)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSignalRecord, signalRecord)
+}
+
 func TestRecordTypeString(t *testing.T) {
 	assert.Equal(t, "EVENT", RecordTypeEvent.String())
 	assert.Equal(t, "COMMAND", RecordTypeCommand.String())
@@ -550,3 +674,20 @@ func TestValueTypeString(t *testing.T) {
 	assert.Equal(t, "JOB", ValueTypeJob.String())
 	assert.Equal(t, "MESSAGE", ValueTypeMessage.String())
 }
+
+func TestDetectEncoding(t *testing.T) {
+	assert.Equal(t, RecordEncodingJSON, detectEncoding([]byte(jobRecordJSON)))
+	assert.Equal(t, RecordEncodingJSON, detectEncoding([]byte("\n {}")))
+	assert.Equal(t, RecordEncodingProtobuf, detectEncoding(jobRecordProtobuf(t)))
+
+	fromJSON, err := parseRecord([]byte(jobRecordJSON))
+	assert.NoError(t, err)
+	fromProtobuf, err := parseRecord(jobRecordProtobuf(t))
+	assert.NoError(t, err)
+	assert.Equal(t, fromJSON.Key, fromProtobuf.Key)
+	assert.Equal(t, fromJSON.ValueType, fromProtobuf.ValueType)
+
+	// A configured encoding isn't second-guessed
+	_, err = parseRecordAs(RecordEncodingJSON, jobRecordProtobuf(t))
+	assert.Error(t, err)
+}
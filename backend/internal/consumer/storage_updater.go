@@ -1,6 +1,7 @@
 package consumer
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,7 +14,10 @@ import (
 
 // Intermediary object that handles communication between consumers and storage.
 type storageUpdater struct {
-	storer storage.Storer
+	storer     storage.Storer
+	progress   *progressTracker
+	topics     Topics
+	reconciler *reconciler
 
 	msgChannels  map[string]listenOnlyMsgChannel
 	closeChannel listenOnlySignalChannel
@@ -21,7 +25,7 @@ type storageUpdater struct {
 	wg *sync.WaitGroup
 }
 
-func newDatabaseUpdater(storer storage.Storer, msgChannels map[string]msgChannelType, closeChannel signalChannelType, wg *sync.WaitGroup) *storageUpdater {
+func newDatabaseUpdater(storer storage.Storer, progress *progressTracker, topics Topics, msgChannels map[string]msgChannelType, closeChannel signalChannelType, wg *sync.WaitGroup) *storageUpdater {
 	// Turn the channels into listen-only channels
 	listenOnlyMsgChannels := map[string]listenOnlyMsgChannel{}
 	for topic, channel := range msgChannels {
@@ -29,7 +33,10 @@ func newDatabaseUpdater(storer storage.Storer, msgChannels map[string]msgChannel
 	}
 
 	result := &storageUpdater{
-		storer: storer,
+		storer:     storer,
+		progress:   progress,
+		topics:     topics,
+		reconciler: newReconciler(),
 
 		msgChannels:  listenOnlyMsgChannels,
 		closeChannel: closeChannel,
@@ -47,83 +54,378 @@ func newDatabaseUpdater(storer storage.Storer, msgChannels map[string]msgChannel
 		}()
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result.reconcileLoop()
+	}()
+
 	return result
 }
 
-// Handle actual database updates from consumers
+const (
+	// Maximum number of records applied in one transaction.
+	BatchMaxSize = 500
+	// How long to wait for more records after the first record of a batch
+	// before applying the batch.
+	BatchMaxDelay = 50 * time.Millisecond
+)
+
+// Handle actual database updates from consumers. Records are applied in
+// batches, each in a single transaction.
 func (u *storageUpdater) storageUpdaterLoop(topic string) {
 	closeChannel := u.closeChannel
 	msgChannel := u.msgChannels[topic]
-readLoop:
 	for {
+		var first message
 		select {
 		case <-closeChannel:
 			// Close this one too when we get a closeChannel message
-			break readLoop
+			return
+		case first = <-msgChannel:
+		}
+
+		batch, closed := u.collectBatch(first, msgChannel)
+		u.applyBatch(batch)
+		if closed {
+			return
+		}
+	}
+}
+
+// Collect the messages following `first` into a batch until the batch is
+// full, `BatchMaxDelay` has passed or the updater is closed. Returns whether
+// the updater was closed.
+func (u *storageUpdater) collectBatch(first message, msgChannel listenOnlyMsgChannel) ([]message, bool) {
+	batch := []message{first}
+
+	timer := time.NewTimer(BatchMaxDelay)
+	defer timer.Stop()
+
+	for len(batch) < BatchMaxSize {
+		select {
+		case <-u.closeChannel:
+			return batch, true
+		case <-timer.C:
+			return batch, false
 		case msg := <-msgChannel:
-			var untypedRecord UntypedRecord
-			err := json.Unmarshal(msg, &untypedRecord)
+			batch = append(batch, msg)
+		}
+	}
+
+	return batch, false
+}
+
+// Apply a batch of messages and commit their offsets in one transaction.
+//
+// Each record is still applied in a nested transaction of its own, so a
+// failing record is stored as a dead letter without affecting the rest of
+// the batch. If the batch fails as a whole, e.g. because committing the
+// transaction fails, its messages are applied one by one instead.
+//
+// Records parked in the batch are only kept in memory once the batch has
+// been committed. Otherwise the records would be parked twice when falling
+// back to applying them one by one, and applied twice when released.
+func (u *storageUpdater) applyBatch(batch []message) {
+	var created []int64
+	var staged *reconciler
+	err := u.storer.Transaction(func(storer storage.Storer) error {
+		created = nil
+		staged = newReconciler()
+		batchUpdater := u.withStorer(storer)
+		batchUpdater.reconciler = staged
+
+		for _, msg := range batch {
+			record, err := batchUpdater.parseMessage(msg)
+			if err == nil && record != nil {
+				var createdRow bool
+				createdRow, err = batchUpdater.applyOrPark(msg, record, time.Now())
+				if createdRow {
+					created = append(created, rowKey(record))
+				}
+			}
 			if err != nil {
-				log.Printf("Failed to unmarshal: %v", err)
-				continue readLoop
+				log.Printf("Handling failed: %v", err)
+				batchUpdater.storeDeadLetter(msg, err)
 			}
 
-			err = u.handlingDispatch(topic, &untypedRecord)
+			// Commit the offset even if handling failed: the
+			// record is in the dead letter store for retrying, and
+			// it would fail in the same way if it was consumed
+			// again after a restart. Parked records are in the
+			// pending queue by now, stored in this same transaction
+			err = storer.OffsetCommitted(msg.topic, msg.partition, msg.offset)
 			if err != nil {
-				log.Printf("Handling failed: %v", err)
-				continue readLoop
+				return err
 			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("Applying a batch of %d records failed, applying them one by one: %v",
+			len(batch), err)
+		for _, msg := range batch {
+			u.applyAndCommit(msg)
+		}
+		return
+	}
+
+	for _, parked := range staged.takeAll() {
+		u.reconciler.park(rowKey(parked.record), parked)
+	}
+
+	// Records waiting for the rows created in the batch can only be
+	// applied once the rows have been committed
+	for _, key := range created {
+		u.release(key)
+	}
+
+	for _, msg := range batch {
+		u.progress.applied(msg.topic, msg.partition, msg.offset)
+	}
+}
+
+// Apply a single message on its own and commit its offset.
+func (u *storageUpdater) applyAndCommit(msg message) {
+	err := u.applyMessage(msg)
+	if err != nil {
+		log.Printf("Handling failed: %v", err)
+		u.storeDeadLetter(msg, err)
+	}
+
+	// Committed even if handling failed, as in a batch
+	err = u.storer.OffsetCommitted(msg.topic, msg.partition, msg.offset)
+	if err != nil {
+		log.Printf("Failed to commit offset: %v", err)
+	}
+	u.progress.applied(msg.topic, msg.partition, msg.offset)
+}
+
+// Returns a copy of the updater applying records through `storer`, e.g.
+// within a transaction.
+func (u *storageUpdater) withStorer(storer storage.Storer) *storageUpdater {
+	updater := *u
+	updater.storer = storer
+	return &updater
+}
+
+// Store a message that failed to be applied so that it isn't lost.
+func (u *storageUpdater) storeDeadLetter(msg message, handlingErr error) {
+	err := u.storer.DeadLetterStored(
+		msg.topic,
+		msg.partition,
+		msg.offset,
+		msg.value,
+		handlingErr.Error(),
+		time.Now(),
+	)
+	if err != nil {
+		log.Printf("Failed to store dead letter: %v", err)
+	}
+}
+
+// Parse a raw record, detecting its encoding.
+func parseRecord(value []byte) (*UntypedRecord, error) {
+	return parseRecordAs(RecordEncodingAuto, value)
+}
+
+// Parse a raw record of the given encoding.
+func parseRecordAs(encoding RecordEncoding, value []byte) (*UntypedRecord, error) {
+	if encoding == RecordEncodingAuto {
+		encoding = detectEncoding(value)
+	}
+
+	if encoding == RecordEncodingProtobuf {
+		untypedRecord, err := parseProtobufRecord(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode protobuf: %w", err)
+		}
+		return untypedRecord, nil
+	}
+
+	var untypedRecord UntypedRecord
+	err := json.Unmarshal(value, &untypedRecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return &untypedRecord, nil
+}
+
+// Parse a raw message and dispatch it to a handler. Messages updating rows
+// that don't exist yet are parked until the rows are created.
+func (u *storageUpdater) applyMessage(msg message) error {
+	untypedRecord, err := u.parseMessage(msg)
+	if err != nil || untypedRecord == nil {
+		return err
+	}
+
+	return u.apply(msg, untypedRecord, time.Now())
+}
+
+// Parse a raw message. Returns nil if the record should be skipped.
+func (u *storageUpdater) parseMessage(msg message) (*UntypedRecord, error) {
+	untypedRecord, err := parseRecordAs(msg.encoding, msg.value)
+	if err != nil {
+		return nil, err
 	}
+
+	// Skip records of value types the topic hasn't been configured for
+	if !u.topics.accepts(msg.topic, untypedRecord.ValueType) {
+		log.Printf("Ignoring %v record in topic %s",
+			untypedRecord.ValueType, msg.topic)
+		return nil, nil
+	}
+
+	return untypedRecord, nil
 }
 
-func (u *storageUpdater) handlingDispatch(topic string, untypedRecord *UntypedRecord) error {
+func (u *storageUpdater) handlingDispatch(untypedRecord *UntypedRecord) error {
 	var err error
 
 	if untypedRecord.ValueType == "" {
 		return fmt.Errorf("zero-value value type in record")
 	}
-	// Dispatch by topic, so that all of them can use this shared dispatch
-	// This way the checking for the correct value type also makes slightly
-	// more sense to hoist out of the dispatch, perhaps..?
-	switch topic {
-	case "zeebe-deployment":
+	// Rejected commands didn't change anything, so they're only stored as
+	// rejections instead of being dispatched by value type
+	if untypedRecord.RecordType == RecordTypeCommandRejection {
+		err = u.handleRejection(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle rejection: %w", err)
+		}
+		return nil
+	}
+	// Dispatch by value type so that any topic layout works, including
+	// several value types sharing a topic
+	switch untypedRecord.ValueType { // nolint:exhaustive
+	case ValueTypeDeployment:
 		err = u.handleDeployment(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle deployment: %w", err)
 		}
-	case "zeebe-process":
+	case ValueTypeProcess:
 		err = u.handleProcess(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle process: %w", err)
 		}
-	case "zeebe-process-instance":
+	case ValueTypeProcessInstance:
 		err = u.handleProcessInstance(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle process instance: %w", err)
 		}
-	case "zeebe-variable":
+	case ValueTypeVariable:
 		err = u.handleVariable(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle variable: %w", err)
 		}
-	case "zeebe-incident":
+	case ValueTypeIncident:
 		err = u.handleIncident(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle incident: %w", err)
 		}
-	case "zeebe-job":
+	case ValueTypeJob:
 		err = u.handleJob(untypedRecord)
 		if err != nil {
 			return fmt.Errorf("failed to handle job: %w", err)
 		}
+	case ValueTypeJobBatch:
+		err = u.handleJobBatch(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle job batch: %w", err)
+		}
+	case ValueTypeMessage:
+		err = u.handleMessage(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle message: %w", err)
+		}
+	case ValueTypeMessageSubscription:
+		err = u.handleMessageSubscription(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle message subscription: %w", err)
+		}
+	case ValueTypeProcessMessageSubscription:
+		err = u.handleProcessMessageSubscription(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle process message subscription: %w", err)
+		}
+	case ValueTypeMessageStartEventSubscription:
+		err = u.handleMessageStartEventSubscription(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle message start event subscription: %w", err)
+		}
+	case ValueTypeTimer:
+		err = u.handleTimer(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle timer: %w", err)
+		}
+	case ValueTypeDecisionEvaluation:
+		err = u.handleDecisionEvaluation(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle decision evaluation: %w", err)
+		}
+	case ValueTypeUserTask:
+		err = u.handleUserTask(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle user task: %w", err)
+		}
+	case ValueTypeSignal:
+		err = u.handleSignal(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle signal: %w", err)
+		}
+	case ValueTypeSignalSubscription:
+		err = u.handleSignalSubscription(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle signal subscription: %w", err)
+		}
+	case ValueTypeError:
+		err = u.handleError(untypedRecord)
+		if err != nil {
+			return fmt.Errorf("failed to handle error: %w", err)
+		}
 	default:
-		log.Printf("Unhandled topic: %s (valuetype %v, intent: %v)",
-			topic, untypedRecord.ValueType, untypedRecord.Intent)
+		log.Printf("Unhandled value type: %v (intent: %v)",
+			untypedRecord.ValueType, untypedRecord.Intent)
 	}
 	return nil
 }
 
+// Fields shared by the values of most value types, linking records to process
+// instances and their elements.
+type linkedValue struct {
+	ProcessInstanceKey int64  `json:"processInstanceKey"`
+	ElementID          string `json:"elementId"`
+}
+
+func (u *storageUpdater) handleRejection(untypedRecord *UntypedRecord) error {
+	// Values of some types don't have these fields, in which case the
+	// rejection isn't linked to anything
+	var value linkedValue
+	if len(untypedRecord.Value) != 0 {
+		err := json.Unmarshal(untypedRecord.Value, &value)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal value: %w", err)
+		}
+	}
+
+	log.Printf("Command rejected: %v %v (%v: %s)",
+		untypedRecord.ValueType, untypedRecord.Intent,
+		untypedRecord.RejectionType, untypedRecord.RejectionReason)
+	return u.storer.CommandRejected(
+		untypedRecord.PartitionID,
+		untypedRecord.Position,
+		untypedRecord.Key,
+		value.ProcessInstanceKey,
+		value.ElementID,
+		string(untypedRecord.ValueType),
+		string(untypedRecord.Intent),
+		string(untypedRecord.RejectionType),
+		untypedRecord.RejectionReason,
+		time.UnixMilli(untypedRecord.Timestamp),
+	)
+}
+
 func (u *storageUpdater) handleDeployment(untypedRecord *UntypedRecord) error {
 	storer := u.storer
 
@@ -144,7 +446,29 @@ func (u *storageUpdater) handleDeployment(untypedRecord *UntypedRecord) error {
 
 		// Make storage for errors
 		var errs []error
+
+		deploymentResources, definitions := deploymentContents(record.Value)
+		err = storer.DeploymentCreated(
+			record.Position,
+			record.Key,
+			deploymentResources,
+			definitions,
+			time.UnixMilli(record.Timestamp),
+		)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		// Definitions deployed again unchanged are marked as duplicates,
+		// and keep the deployment time and resource of their first
+		// deployment
 		for _, process := range processes {
+			if process.Duplicate {
+				log.Printf("Process %d (%s) is already deployed",
+					process.ProcessDefinitionKey, process.BpmnProcessID)
+				continue
+			}
+
 			bpmnResource, ok := resourceMap[process.ResourceName]
 			if !ok {
 				err := fmt.Errorf("resource not in map: %s",
@@ -160,6 +484,7 @@ func (u *storageUpdater) handleDeployment(untypedRecord *UntypedRecord) error {
 			log.Printf("Deploying %s", bpmnProcessID)
 
 			err := storer.ProcessDeployed(
+				record.Position,
 				processDefinitionKey,
 				bpmnProcessID,
 				version,
@@ -174,6 +499,61 @@ func (u *storageUpdater) handleDeployment(untypedRecord *UntypedRecord) error {
 			}
 		}
 
+		for _, drg := range record.Value.DecisionRequirementsMetadata {
+			if drg.Duplicate {
+				continue
+			}
+
+			dmnResource, ok := resourceMap[drg.ResourceName]
+			if !ok {
+				err := fmt.Errorf("resource not in map: %s",
+					drg.ResourceName)
+				errs = append(errs, err)
+				continue
+			}
+
+			err := storer.DecisionRequirementsDeployed(
+				record.Position,
+				drg.DecisionRequirementsKey,
+				drg.DecisionRequirementsID,
+				drg.DecisionRequirementsName,
+				drg.DecisionRequirementsVersion,
+				drg.Namespace,
+				drg.ResourceName,
+				time.UnixMilli(record.Timestamp),
+				dmnResource,
+			)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				log.Printf("Deployed decision requirements %d (%s)",
+					drg.DecisionRequirementsKey, drg.DecisionRequirementsID)
+			}
+		}
+
+		for _, decision := range record.Value.DecisionsMetadata {
+			if decision.Duplicate {
+				continue
+			}
+
+			err := storer.DecisionDeployed(
+				record.Position,
+				decision.DecisionKey,
+				decision.DecisionID,
+				decision.DecisionName,
+				decision.Version,
+				decision.DecisionRequirementsKey,
+				decision.DecisionRequirementsID,
+				time.UnixMilli(record.Timestamp),
+			)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				log.Printf("Deployed decision %d (%s)",
+					decision.DecisionKey, decision.DecisionID)
+			}
+		}
+
 		if len(errs) != 0 {
 			err := fmt.Errorf("failed some deploys: %w", errors.Join(errs...))
 			return err
@@ -191,6 +571,81 @@ func (u *storageUpdater) handleDeployment(untypedRecord *UntypedRecord) error {
 	return nil
 }
 
+// Collect the resources of a deployment and the definitions in them. The type
+// of a resource is known from the definitions found in it.
+func deploymentContents(value DeploymentValue) ([]storage.DeploymentResource, []storage.DeployedDefinition) {
+	resourceTypes := map[string]string{}
+	var definitions []storage.DeployedDefinition
+
+	for _, process := range value.ProcessesMetadata {
+		resourceTypes[process.ResourceName] = "BPMN"
+		definitions = append(definitions, storage.DeployedDefinition{
+			DefinitionKey: process.ProcessDefinitionKey,
+			Kind:          "PROCESS",
+			DefinitionID:  process.BpmnProcessID,
+			Version:       process.Version,
+			ResourceName:  process.ResourceName,
+			Duplicate:     process.Duplicate,
+		})
+	}
+	for _, drg := range value.DecisionRequirementsMetadata {
+		resourceTypes[drg.ResourceName] = "DMN"
+		definitions = append(definitions, storage.DeployedDefinition{
+			DefinitionKey: drg.DecisionRequirementsKey,
+			Kind:          "DECISION_REQUIREMENTS",
+			DefinitionID:  drg.DecisionRequirementsID,
+			Version:       drg.DecisionRequirementsVersion,
+			ResourceName:  drg.ResourceName,
+			Duplicate:     drg.Duplicate,
+		})
+	}
+
+	// Decisions don't have a resource name of their own but share the
+	// one of their decision requirements
+	drgResourceNames := map[int64]string{}
+	for _, drg := range value.DecisionRequirementsMetadata {
+		drgResourceNames[drg.DecisionRequirementsKey] = drg.ResourceName
+	}
+	for _, decision := range value.DecisionsMetadata {
+		definitions = append(definitions, storage.DeployedDefinition{
+			DefinitionKey: decision.DecisionKey,
+			Kind:          "DECISION",
+			DefinitionID:  decision.DecisionID,
+			Version:       decision.Version,
+			ResourceName:  drgResourceNames[decision.DecisionRequirementsKey],
+			Duplicate:     decision.Duplicate,
+		})
+	}
+
+	for _, form := range value.FormMetadata {
+		resourceTypes[form.ResourceName] = "FORM"
+		definitions = append(definitions, storage.DeployedDefinition{
+			DefinitionKey: form.FormKey,
+			Kind:          "FORM",
+			DefinitionID:  form.FormID,
+			Version:       form.Version,
+			ResourceName:  form.ResourceName,
+			Duplicate:     form.Duplicate,
+		})
+	}
+
+	resources := make([]storage.DeploymentResource, 0, len(value.Resources))
+	for _, resource := range value.Resources {
+		resourceType, ok := resourceTypes[resource.ResourceName]
+		if !ok {
+			resourceType = "OTHER"
+		}
+
+		resources = append(resources, storage.DeploymentResource{
+			ResourceName: resource.ResourceName,
+			ResourceType: resourceType,
+			Size:         int64(len(resource.Resource)),
+		})
+	}
+
+	return resources, definitions
+}
+
 func (u *storageUpdater) handleProcess(untypedRecord *UntypedRecord) error {
 	record, err := WithTypedValue[ProcessValue](*untypedRecord)
 	if err != nil {
@@ -250,6 +705,12 @@ func (u *storageUpdater) handleProcessInstance(untypedRecord *UntypedRecord) err
 		)
 	}
 
+	// Track the lifecycle of every element, including the process itself
+	err = u.handleElementInstance(record)
+	if err != nil {
+		return fmt.Errorf("failed to handle element instance: %w", err)
+	}
+
 	// Once we handle further element types it may be desirable to dispatch
 	// them further based on either intent or element type, depending on
 	// which one seems more reasonable.
@@ -262,9 +723,12 @@ func (u *storageUpdater) handleProcessInstance(untypedRecord *UntypedRecord) err
 		if bpmnElementType == BpmnElementTypeProcess {
 			log.Printf("Process instance activated: %d", processInstanceKey)
 			return storer.ProcessInstanceActivated(
+				record.Position,
 				processInstanceKey,
 				processDefinitionKey,
 				version,
+				record.Value.ParentProcessInstanceKey,
+				record.Value.ParentElementInstanceKey,
 				timestamp,
 			)
 		}
@@ -276,6 +740,7 @@ func (u *storageUpdater) handleProcessInstance(untypedRecord *UntypedRecord) err
 		if bpmnElementType == BpmnElementTypeProcess {
 			log.Printf("Process instance completed: %d", processInstanceKey)
 			return storer.ProcessInstanceCompleted(
+				record.Position,
 				processInstanceKey,
 				timestamp,
 			)
@@ -288,6 +753,7 @@ func (u *storageUpdater) handleProcessInstance(untypedRecord *UntypedRecord) err
 		if bpmnElementType == BpmnElementTypeProcess {
 			log.Printf("Process instance terminated: %d", processInstanceKey)
 			return storer.ProcessInstanceTerminated(
+				record.Position,
 				processInstanceKey,
 				timestamp,
 			)
@@ -302,6 +768,44 @@ func (u *storageUpdater) handleProcessInstance(untypedRecord *UntypedRecord) err
 	return nil
 }
 
+func (u *storageUpdater) handleElementInstance(record ProcessInstance) error {
+	storer := u.storer
+
+	key := record.Key
+	state := string(record.Intent)
+	timestamp := time.UnixMilli(record.Timestamp)
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentElementActivating:
+		return storer.ElementInstanceActivating(
+			record.Position,
+			key,
+			record.Value.ProcessInstanceKey,
+			record.Value.ElementID,
+			string(record.Value.BpmnElementType),
+			record.Value.FlowScopeKey,
+			timestamp,
+		)
+	case IntentElementActivated, IntentElementCompleting, IntentElementTerminating:
+		return storer.ElementInstanceUpdated(
+			record.Position,
+			key,
+			state,
+		)
+	case IntentElementCompleted, IntentElementTerminated:
+		return storer.ElementInstanceEnded(
+			record.Position,
+			key,
+			state,
+			timestamp,
+		)
+	}
+
+	// Other intents are commands or sequence flows being taken, which
+	// don't change any element instance
+	return nil
+}
+
 func (u *storageUpdater) handleVariable(untypedRecord *UntypedRecord) error {
 	storer := u.storer
 
@@ -311,35 +815,54 @@ func (u *storageUpdater) handleVariable(untypedRecord *UntypedRecord) error {
 	}
 
 	processInstanceKey := record.Value.ProcessInstanceKey
+	scopeKey := record.Value.ScopeKey
 	name := record.Value.Name
 	value := record.Value.Value
+	timestamp := time.UnixMilli(record.Timestamp)
 	switch record.Intent { // nolint:exhaustive
 	case IntentCreated:
-		log.Printf("Variable created: %s = %s (instance %d)",
-			name, value, processInstanceKey)
-		return storer.VariableCreated(
+		log.Printf("Variable created: %s = %s (instance %d, scope %d)",
+			name, value, processInstanceKey, scopeKey)
+		err = storer.VariableCreated(
+			record.Position,
 			processInstanceKey,
+			scopeKey,
 			name,
 			value,
-			time.UnixMilli(record.Timestamp),
+			timestamp,
 		)
 	case IntentUpdated:
-		log.Printf("Variable updated: %s = %s (instance %d)",
-			name, value, processInstanceKey)
-		return storer.VariableUpdated(
+		log.Printf("Variable updated: %s = %s (instance %d, scope %d)",
+			name, value, processInstanceKey, scopeKey)
+		err = storer.VariableUpdated(
+			record.Position,
 			processInstanceKey,
+			scopeKey,
 			name,
 			value,
-			time.UnixMilli(record.Timestamp),
+			timestamp,
 		)
 	default:
 		log.Printf("Unhandled intent for %v: %s",
 			record.ValueType, record.Intent)
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	// If we get here we did nothing or missed all err returns so handling
-	// succeeded
-	return nil
+	// Only add to the history once the variable itself has been stored,
+	// so a parked update isn't in the history twice
+	return storer.VariableChanged(
+		record.PartitionID,
+		record.Position,
+		processInstanceKey,
+		scopeKey,
+		name,
+		string(record.Intent),
+		value,
+		timestamp,
+	)
 }
 
 func (u *storageUpdater) handleIncident(untypedRecord *UntypedRecord) error {
@@ -362,6 +885,7 @@ func (u *storageUpdater) handleIncident(untypedRecord *UntypedRecord) error {
 		log.Printf("Incident created: %s (instance %d)",
 			errorType, processInstanceKey)
 		return storer.IncidentCreated(
+			record.Position,
 			key,
 			processInstanceKey,
 			elementID,
@@ -373,6 +897,7 @@ func (u *storageUpdater) handleIncident(untypedRecord *UntypedRecord) error {
 		log.Printf("Incident resolved: %s (instance %d)",
 			errorType, processInstanceKey)
 		return storer.IncidentResolved(
+			record.Position,
 			key,
 			time,
 		)
@@ -411,31 +936,584 @@ func (u *storageUpdater) handleJob(untypedRecord *UntypedRecord) error {
 	jobType := record.Value.Type
 	retries := record.Value.Retries
 	worker := record.Value.Worker
+	errorMessage := record.Value.ErrorMessage
+	errorCode := record.Value.ErrorCode
+	retryBackoff := record.Value.RetryBackoff
 	state := string(record.Intent)
-	time := time.UnixMilli(record.Timestamp)
+	timestamp := time.UnixMilli(record.Timestamp)
 
 	if record.Intent == IntentCreated {
+		customHeaders, err := json.Marshal(record.Value.CustomHeaders)
+		if err != nil {
+			return fmt.Errorf("failed to marshal custom headers: %w", err)
+		}
+
 		log.Printf("Job created: %s (instance %d, element %s)",
 			jobType, processInstanceKey, elementID)
-		return storer.JobCreated(
+		err = storer.JobCreated(
+			record.Position,
 			key,
 			elementID,
+			record.Value.ElementInstanceKey,
 			processInstanceKey,
 			jobType,
 			retries,
 			worker,
-			time,
+			string(customHeaders),
+			timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Jobs without a deadline have it set to -1
+		var deadline sql.NullTime
+		if record.Value.Deadline > 0 {
+			deadline = sql.NullTime{
+				Time:  time.UnixMilli(record.Value.Deadline),
+				Valid: true,
+			}
+		}
+
+		// Other intents should only come in for jobs that already
+		// exist so they're Update type tasks
+		log.Printf("Job state changed: %s, %s (instance %d, element %s)",
+			state, jobType, processInstanceKey, elementID)
+		err = storer.JobUpdated(
+			record.Position,
+			key,
+			retries,
+			worker,
+			state,
+			errorMessage,
+			errorCode,
+			deadline,
+			retryBackoff,
+			timestamp,
 		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commands are followed by the events they lead to, so only the
+	// events make up the history
+	if record.RecordType != RecordTypeEvent {
+		return nil
 	}
-	// Other intents should only come in for jobs that already
-	// exist so they're Update type tasks
-	log.Printf("Job state changed: %s, %s (instance %d, element %s)",
-		state, jobType, processInstanceKey, elementID)
-	return storer.JobUpdated(
+
+	return storer.JobEventOccurred(
+		record.PartitionID,
+		record.Position,
 		key,
+		processInstanceKey,
+		state,
 		retries,
 		worker,
-		state,
+		errorMessage,
+		errorCode,
+		retryBackoff,
+		timestamp,
+	)
+}
+
+func (u *storageUpdater) handleJobBatch(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[JobBatchValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	// A batch without a worker can't be attributed to any of them
+	worker := record.Value.Worker
+	if worker == "" {
+		log.Printf("Skipping job batch without a worker (position %d)",
+			record.Position)
+		return nil
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentActivate, IntentActivated:
+		log.Printf("Job batch %s: %d jobs of type %s for %s",
+			record.Intent, len(record.Value.JobKeys), record.Value.Type, worker)
+		return u.storer.JobBatchOccurred(
+			record.PartitionID,
+			record.Position,
+			worker,
+			record.Value.Type,
+			string(record.Intent),
+			record.Value.Timeout,
+			record.Value.MaxJobsToActivate,
+			int64(len(record.Value.JobKeys)),
+			record.Value.Truncated,
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleDecisionEvaluation(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[DecisionEvaluationValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentEvaluated, IntentFailed:
+		evaluatedDecisions := make([]storage.EvaluatedDecision, 0,
+			len(record.Value.EvaluatedDecisions))
+		for _, decision := range record.Value.EvaluatedDecisions {
+			// Inputs and matched rules are only shown as they are, so
+			// they're kept as JSON instead of tables of their own
+			inputs, err := json.Marshal(decision.EvaluatedInputs)
+			if err != nil {
+				return fmt.Errorf("failed to marshal inputs: %w", err)
+			}
+			matchedRules, err := json.Marshal(decision.MatchedRules)
+			if err != nil {
+				return fmt.Errorf("failed to marshal matched rules: %w", err)
+			}
+
+			evaluatedDecisions = append(evaluatedDecisions, storage.EvaluatedDecision{
+				DecisionKey:     decision.DecisionKey,
+				DecisionID:      decision.DecisionID,
+				DecisionName:    decision.DecisionName,
+				DecisionVersion: decision.DecisionVersion,
+				DecisionType:    decision.DecisionType,
+				Output:          decision.DecisionOutput,
+				Inputs:          string(inputs),
+				MatchedRules:    string(matchedRules),
+			})
+		}
+
+		log.Printf("Decision %s %s for instance %d",
+			record.Value.DecisionID, record.Intent, record.Value.ProcessInstanceKey)
+		return u.storer.DecisionEvaluated(
+			record.Position,
+			record.Key,
+			record.Value.DecisionKey,
+			record.Value.DecisionID,
+			record.Value.DecisionName,
+			record.Value.DecisionVersion,
+			record.Value.DecisionRequirementsKey,
+			record.Value.DecisionOutput,
+			record.Value.ProcessInstanceKey,
+			record.Value.ProcessDefinitionKey,
+			record.Value.ElementInstanceKey,
+			record.Value.ElementID,
+			string(record.Intent),
+			record.Value.EvaluationFailureMessage,
+			record.Value.FailedDecisionID,
+			evaluatedDecisions,
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleMessage(untypedRecord *UntypedRecord) error {
+	storer := u.storer
+
+	record, err := WithTypedValue[MessageValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	key := record.Key
+	name := record.Value.Name
+	correlationKey := record.Value.CorrelationKey
+	time := time.UnixMilli(record.Timestamp)
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentPublished:
+		variables, err := json.Marshal(record.Value.Variables)
+		if err != nil {
+			return fmt.Errorf("failed to marshal variables: %w", err)
+		}
+
+		log.Printf("Message published: %s (correlation key %s)",
+			name, correlationKey)
+		return storer.MessagePublished(
+			record.Position,
+			key,
+			name,
+			correlationKey,
+			record.Value.MessageID,
+			record.Value.TimeToLive,
+			string(variables),
+			time,
+		)
+	case IntentExpired:
+		log.Printf("Message expired: %s (correlation key %s)",
+			name, correlationKey)
+		return storer.MessageExpired(
+			record.Position,
+			key,
+			time,
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+// Returns whether a subscription record is an event changing the state of the
+// subscription, as opposed to a command.
+func isSubscriptionEvent(intent Intent) bool {
+	switch intent { // nolint:exhaustive
+	case IntentCreating, IntentCreated, IntentCorrelating, IntentCorrelated,
+		IntentRejected, IntentDeleting, IntentDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Message subscriptions are the message partition's side of a subscription.
+// It's where messages get correlated, so correlating marks the message
+// correlated as well.
+func (u *storageUpdater) handleMessageSubscription(untypedRecord *UntypedRecord) error {
+	storer := u.storer
+
+	record, err := WithTypedValue[MessageSubscriptionValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	if !isSubscriptionEvent(record.Intent) {
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+		return nil
+	}
+
+	messageKey := record.Value.MessageKey
+	time := time.UnixMilli(record.Timestamp)
+
+	log.Printf("Message subscription %s: %s (instance %d)",
+		record.Intent, record.Value.MessageName, record.Value.ProcessInstanceKey)
+	err = storer.MessageSubscriptionUpdated(
+		record.Position,
+		record.Value.ProcessInstanceKey,
+		record.Value.ElementInstanceKey,
+		"",
+		record.Value.MessageName,
+		record.Value.CorrelationKey,
+		messageKey,
+		record.Value.Interrupting,
+		string(record.Intent),
 		time,
 	)
+	if err != nil {
+		return err
+	}
+
+	if record.Intent == IntentCorrelating && messageKey != 0 {
+		return storer.MessageCorrelated(
+			record.Position,
+			messageKey,
+			time,
+		)
+	}
+
+	return nil
+}
+
+// Process message subscriptions are the process instance partition's side of
+// a subscription, which also knows the element waiting for the message.
+func (u *storageUpdater) handleProcessMessageSubscription(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[ProcessMessageSubscriptionValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	if !isSubscriptionEvent(record.Intent) {
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+		return nil
+	}
+
+	log.Printf("Process message subscription %s: %s (instance %d, element %s)",
+		record.Intent, record.Value.MessageName,
+		record.Value.ProcessInstanceKey, record.Value.ElementID)
+	return u.storer.MessageSubscriptionUpdated(
+		record.Position,
+		record.Value.ProcessInstanceKey,
+		record.Value.ElementInstanceKey,
+		record.Value.ElementID,
+		record.Value.MessageName,
+		record.Value.CorrelationKey,
+		record.Value.MessageKey,
+		record.Value.Interrupting,
+		string(record.Intent),
+		time.UnixMilli(record.Timestamp),
+	)
+}
+
+// Message start event subscriptions belong to processes rather than
+// instances, so only their correlations are stored, on the message.
+func (u *storageUpdater) handleMessageStartEventSubscription(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[MessageStartEventSubscriptionValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentCorrelated:
+		log.Printf("Message %s started process %s (instance %d)",
+			record.Value.MessageName, record.Value.BpmnProcessID,
+			record.Value.ProcessInstanceKey)
+		return u.storer.MessageCorrelated(
+			record.Position,
+			record.Value.MessageKey,
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleTimer(untypedRecord *UntypedRecord) error {
+	storer := u.storer
+
+	record, err := WithTypedValue[TimerValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	key := record.Key
+	processInstanceKey := record.Value.ProcessInstanceKey
+	targetElementID := record.Value.TargetElementID
+	timestamp := time.UnixMilli(record.Timestamp)
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentCreated:
+		log.Printf("Timer created: %s (instance %d)",
+			targetElementID, processInstanceKey)
+		return storer.TimerCreated(
+			record.Position,
+			key,
+			processInstanceKey,
+			record.Value.ProcessDefinitionKey,
+			record.Value.ElementInstanceKey,
+			targetElementID,
+			time.UnixMilli(record.Value.DueDate),
+			record.Value.Repetitions,
+			timestamp,
+		)
+	case IntentTriggered, IntentCanceled:
+		log.Printf("Timer %s: %s (instance %d)",
+			record.Intent, targetElementID, processInstanceKey)
+		return storer.TimerUpdated(
+			record.Position,
+			key,
+			string(record.Intent),
+			timestamp,
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleUserTask(untypedRecord *UntypedRecord) error {
+	storer := u.storer
+
+	record, err := WithTypedValue[UserTaskValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	key := record.Key
+	elementID := record.Value.ElementID
+	processInstanceKey := record.Value.ProcessInstanceKey
+	timestamp := time.UnixMilli(record.Timestamp)
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentCreated, IntentAssigned, IntentUpdated:
+		candidateGroups, err := json.Marshal(nonNil(record.Value.CandidateGroupsList))
+		if err != nil {
+			return fmt.Errorf("failed to marshal candidate groups: %w", err)
+		}
+		candidateUsers, err := json.Marshal(nonNil(record.Value.CandidateUsersList))
+		if err != nil {
+			return fmt.Errorf("failed to marshal candidate users: %w", err)
+		}
+		dueDate := parseUserTaskDate(record.Value.DueDate)
+		followUpDate := parseUserTaskDate(record.Value.FollowUpDate)
+
+		if record.Intent == IntentCreated {
+			log.Printf("User task created: %s (instance %d)",
+				elementID, processInstanceKey)
+			return storer.UserTaskCreated(
+				record.Position,
+				key,
+				processInstanceKey,
+				record.Value.ProcessDefinitionKey,
+				record.Value.ElementInstanceKey,
+				elementID,
+				record.Value.Assignee,
+				string(candidateGroups),
+				string(candidateUsers),
+				dueDate,
+				followUpDate,
+				record.Value.FormKey,
+				timestamp,
+			)
+		}
+
+		log.Printf("User task %s: %s (instance %d)",
+			record.Intent, elementID, processInstanceKey)
+		return storer.UserTaskUpdated(
+			record.Position,
+			key,
+			record.Value.Assignee,
+			string(candidateGroups),
+			string(candidateUsers),
+			dueDate,
+			followUpDate,
+			timestamp,
+		)
+	case IntentCompleted, IntentCanceled:
+		log.Printf("User task %s: %s (instance %d)",
+			record.Intent, elementID, processInstanceKey)
+		return storer.UserTaskEnded(
+			record.Position,
+			key,
+			string(record.Intent),
+			timestamp,
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+// Returns an empty slice instead of nil, so that it's marshaled as an empty
+// JSON array.
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// Parse the due or follow-up date of a user task, which is an ISO 8601 date
+// time or empty if it isn't set.
+func parseUserTaskDate(value string) sql.NullTime {
+	if value == "" {
+		return sql.NullTime{}
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Printf("Ignoring invalid user task date %q: %v", value, err)
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  date,
+		Valid: true,
+	}
+}
+
+func (u *storageUpdater) handleSignal(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[SignalValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentBroadcasted:
+		variables, err := json.Marshal(record.Value.Variables)
+		if err != nil {
+			return fmt.Errorf("failed to marshal variables: %w", err)
+		}
+
+		log.Printf("Signal broadcasted: %s", record.Value.SignalName)
+		return u.storer.SignalBroadcasted(
+			record.Position,
+			record.Key,
+			record.Value.SignalName,
+			string(variables),
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleSignalSubscription(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[SignalSubscriptionValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentCreated, IntentDeleted:
+		log.Printf("Signal subscription %s: %s (%s)",
+			record.Intent, record.Value.SignalName, record.Value.CatchEventID)
+		return u.storer.SignalSubscriptionUpdated(
+			record.Position,
+			record.Key,
+			record.Value.SignalName,
+			record.Value.ProcessDefinitionKey,
+			record.Value.BpmnProcessID,
+			record.Value.CatchEventID,
+			record.Value.CatchEventInstanceKey,
+			string(record.Intent),
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
+}
+
+func (u *storageUpdater) handleError(untypedRecord *UntypedRecord) error {
+	record, err := WithTypedValue[ErrorValue](*untypedRecord)
+	if err != nil {
+		return fmt.Errorf("failed to cast: %w", err)
+	}
+
+	switch record.Intent { // nolint:exhaustive
+	case IntentCreated:
+		log.Printf("Broker error: %s (instance %d, position %d)",
+			record.Value.ExceptionMessage, record.Value.ProcessInstanceKey,
+			record.Value.ErrorEventPosition)
+		return u.storer.BrokerErrorOccurred(
+			record.PartitionID,
+			record.Position,
+			record.Value.ErrorEventPosition,
+			record.Value.ProcessInstanceKey,
+			record.Value.ExceptionMessage,
+			record.Value.Stacktrace,
+			time.UnixMilli(record.Timestamp),
+		)
+	default:
+		log.Printf("Unhandled intent for %v: %s",
+			record.ValueType, record.Intent)
+	}
+
+	return nil
 }
@@ -1,13 +1,25 @@
 package consumer
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 type fixedErrStorer struct {
@@ -27,42 +39,81 @@ func (s *fixedErrStorer) reset() {
 	s.err = nil
 }
 
-func (s *fixedErrStorer) ProcessDeployed(int64, string, int64, time.Time, []byte) error {
+func (s *fixedErrStorer) Transaction(fn func(storage.Storer) error) error {
+	return fn(s)
+}
+
+func (s *fixedErrStorer) ProcessDeployed(int64, int64, string, int64, time.Time, []byte) error {
 	s.touched["ProcessDeployed"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) ProcessInstanceActivated(int64, int64, int64, time.Time) error {
+func (s *fixedErrStorer) DeploymentCreated(int64, int64, []storage.DeploymentResource, []storage.DeployedDefinition, time.Time) error {
+	s.touched["DeploymentCreated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) DecisionRequirementsDeployed(int64, int64, string, string, int64, string, string, time.Time, []byte) error {
+	s.touched["DecisionRequirementsDeployed"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) DecisionDeployed(int64, int64, string, string, int64, int64, string, time.Time) error {
+	s.touched["DecisionDeployed"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) DecisionEvaluated(int64, int64, int64, string, string, int64, int64, string, int64, int64, int64, string, string, string, string, []storage.EvaluatedDecision, time.Time) error {
+	s.touched["DecisionEvaluated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) ProcessInstanceActivated(int64, int64, int64, int64, int64, int64, time.Time) error {
 	s.touched["ProcessInstanceActivated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) ProcessInstanceCompleted(int64, time.Time) error {
+func (s *fixedErrStorer) ProcessInstanceCompleted(int64, int64, time.Time) error {
 	s.touched["ProcessInstanceCompleted"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) ProcessInstanceTerminated(int64, time.Time) error {
+func (s *fixedErrStorer) ProcessInstanceTerminated(int64, int64, time.Time) error {
 	s.touched["ProcessInstanceTerminated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) VariableCreated(int64, string, string, time.Time) error {
+func (s *fixedErrStorer) ElementInstanceActivating(int64, int64, int64, string, string, int64, time.Time) error {
+	s.touched["ElementInstanceActivating"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) ElementInstanceUpdated(int64, int64, string) error {
+	s.touched["ElementInstanceUpdated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) ElementInstanceEnded(int64, int64, string, time.Time) error {
+	s.touched["ElementInstanceEnded"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) VariableCreated(int64, int64, int64, string, string, time.Time) error {
 	s.touched["VariableCreated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) VariableUpdated(int64, string, string, time.Time) error {
+func (s *fixedErrStorer) VariableUpdated(int64, int64, int64, string, string, time.Time) error {
 	s.touched["VariableUpdated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) IncidentCreated(int64, int64, string, string, string, time.Time) error {
+func (s *fixedErrStorer) IncidentCreated(int64, int64, int64, string, string, string, time.Time) error {
 	s.touched["IncidentCreated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) IncidentResolved(int64, time.Time) error {
+func (s *fixedErrStorer) IncidentResolved(int64, int64, time.Time) error {
 	s.touched["IncidentResolved"] = true
 	return s.err
 }
@@ -72,19 +123,153 @@ func (s *fixedErrStorer) AuditLogEventOccurred(int64, int64, string, string, str
 	return s.err
 }
 
-func (s *fixedErrStorer) JobCreated(int64, string, int64, string, int64, string, time.Time) error {
+func (s *fixedErrStorer) JobCreated(int64, int64, string, int64, int64, string, int64, string, string, time.Time) error {
 	s.touched["JobCreated"] = true
 	return s.err
 }
 
-func (s *fixedErrStorer) JobUpdated(int64, int64, string, string, time.Time) error {
+func (s *fixedErrStorer) JobUpdated(int64, int64, int64, string, string, string, string, sql.NullTime, int64, time.Time) error {
 	s.touched["JobUpdated"] = true
 	return s.err
 }
 
+func (s *fixedErrStorer) JobEventOccurred(int64, int64, int64, int64, string, int64, string, string, string, int64, time.Time) error {
+	s.touched["JobEventOccurred"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) CommandRejected(int64, int64, int64, int64, string, string, string, string, string, time.Time) error {
+	s.touched["CommandRejected"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) MessagePublished(int64, int64, string, string, string, int64, string, time.Time) error {
+	s.touched["MessagePublished"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) MessageCorrelated(int64, int64, time.Time) error {
+	s.touched["MessageCorrelated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) MessageExpired(int64, int64, time.Time) error {
+	s.touched["MessageExpired"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) MessageSubscriptionUpdated(int64, int64, int64, string, string, string, int64, bool, string, time.Time) error {
+	s.touched["MessageSubscriptionUpdated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) TimerCreated(int64, int64, int64, int64, int64, string, time.Time, int64, time.Time) error {
+	s.touched["TimerCreated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) TimerUpdated(int64, int64, string, time.Time) error {
+	s.touched["TimerUpdated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) VariableChanged(int64, int64, int64, int64, string, string, string, time.Time) error {
+	s.touched["VariableChanged"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) JobBatchOccurred(int64, int64, string, string, string, int64, int64, int64, bool, time.Time) error {
+	s.touched["JobBatchOccurred"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) UserTaskCreated(int64, int64, int64, int64, int64, string, string, string, string, sql.NullTime, sql.NullTime, int64, time.Time) error {
+	s.touched["UserTaskCreated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) UserTaskUpdated(int64, int64, string, string, string, sql.NullTime, sql.NullTime, time.Time) error {
+	s.touched["UserTaskUpdated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) UserTaskEnded(int64, int64, string, time.Time) error {
+	s.touched["UserTaskEnded"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) SignalBroadcasted(int64, int64, string, string, time.Time) error {
+	s.touched["SignalBroadcasted"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) SignalSubscriptionUpdated(int64, int64, string, int64, string, string, int64, string, time.Time) error {
+	s.touched["SignalSubscriptionUpdated"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) BrokerErrorOccurred(int64, int64, int64, int64, string, string, time.Time) error {
+	s.touched["BrokerErrorOccurred"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) OffsetCommitted(string, int32, int64) error {
+	s.touched["OffsetCommitted"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) LastCommittedOffset(string, int32) (int64, bool, error) {
+	s.touched["LastCommittedOffset"] = true
+	return 0, false, s.err
+}
+
+func (s *fixedErrStorer) DeadLetterStored(string, int32, int64, []byte, string, time.Time) error {
+	s.touched["DeadLetterStored"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) DeadLetterDiscarded(int64) error {
+	s.touched["DeadLetterDiscarded"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) DeadLetter(int64) (storage.DeadLetter, error) {
+	s.touched["DeadLetter"] = true
+	return storage.DeadLetter{}, s.err
+}
+
+func (s *fixedErrStorer) DeadLettersToRetry(int64) ([]storage.DeadLetter, error) {
+	s.touched["DeadLettersToRetry"] = true
+	return nil, s.err
+}
+
+func (s *fixedErrStorer) PendingRecordStored(string, int32, int64, []byte, int64, time.Time) error {
+	s.touched["PendingRecordStored"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) PendingRecords(int64) ([]storage.PendingRecord, error) {
+	s.touched["PendingRecords"] = true
+	return nil, s.err
+}
+
+func (s *fixedErrStorer) PendingRecordRemoved(int64) error {
+	s.touched["PendingRecordRemoved"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) PendingRecordResolved(string, int32, int64) error {
+	s.touched["PendingRecordResolved"] = true
+	return s.err
+}
+
+func (s *fixedErrStorer) PendingRecord(int64) (storage.PendingRecord, error) {
+	s.touched["PendingRecord"] = true
+	return storage.PendingRecord{}, s.err
+}
+
 type testRecord struct {
 	name    string
-	topic   string
 	record  *UntypedRecord
 	touched []string
 	err     error
@@ -92,14 +277,12 @@ type testRecord struct {
 
 func newDeploymentTestRecord(
 	name string,
-	topic string,
 	intent Intent,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
@@ -138,16 +321,72 @@ func newDeploymentTestRecord(
 	}
 }
 
+func newDmnDeploymentTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"resources": [
+					{
+						"resource": "Cg==",
+						"resourceName": "test.dmn"
+					}
+				],
+				"processesMetadata": [],
+				"decisionRequirementsMetadata": [
+					{
+						"decisionRequirementsId": "test-drg",
+						"decisionRequirementsName": "Test DRG",
+						"decisionRequirementsVersion": 1,
+						"decisionRequirementsKey": 2,
+						"namespace": "http://camunda.org/schema/1.0/dmn",
+						"resourceName": "test.dmn",
+						"checksum": "Cg==",
+						"duplicate": false
+					}
+				],
+				"decisionsMetadata": [
+					{
+						"decisionId": "test-decision",
+						"version": 1,
+						"decisionKey": 3,
+						"decisionName": "Test decision",
+						"decisionRequirementsId": "test-drg",
+						"decisionRequirementsKey": 2,
+						"duplicate": false
+					}
+				]
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeDeployment,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
 func newProcessTestRecord(
 	name string,
-	topic string,
 	intent Intent,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
@@ -176,14 +415,12 @@ func newProcessTestRecord(
 
 func newProcessInstanceTestRecord(
 	name string,
-	topic string,
 	intent Intent,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
@@ -204,7 +441,375 @@ func newProcessInstanceTestRecord(
 			Key:                  5,
 			Timestamp:            time.Now().UnixMilli(),
 			Position:             6,
-			ValueType:            ValueTypeProcessInstance,
+			ValueType:            ValueTypeProcessInstance,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newVariableTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"processInstanceKey": 1,
+				"processDefinitionKey": 2,
+				"bpmnProcessId": "test-id",
+				"scopeKey": 3,
+				"name": "testName",
+				"value": "testValue"
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeVariable,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newIncidentTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"errorType": "EXTRACT_VALUE_ERROR",
+				"errorMessage": "Some error",
+				"bpmnProcessID": "test-id",
+				"processInstanceKey": 1,
+				"elementId": "Gateway_abcdefg",
+				"elementInstanceKey": 10,
+				"jobKey": -1,
+				"processDefinitionKey": 2,
+				"variableScopeKey": 3
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeIncident,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newJobTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"deadline": 1234567890,
+				"processInstanceKey": 1,
+				"retries": 2,
+				"retryBackoff": 10,
+				"recurringTime": 10,
+				"processDefinitionVersion": 1,
+				"processDefinitionKey": 2,
+				"elementInstanceKey": 10,
+				"elementId": "example-job",
+				"errorMessage": "",
+				"customHeaders": {},
+				"bpmnProcessID": "test-id",
+				"variables": {},
+				"type": "example-job",
+				"errorCode": "",
+				"worker": "worker"
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeJob,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newMessageTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"deadline": 1234567890,
+				"messageId": "message-id",
+				"variables": {"foo": "bar"},
+				"correlationKey": "correlation-key",
+				"name": "message-name",
+				"timeToLive": 3600000
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeMessage,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newMessageSubscriptionTestRecord(
+	name string,
+	valueType ValueType,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"processInstanceKey": 1,
+				"processDefinitionKey": 2,
+				"elementInstanceKey": 10,
+				"elementId": "message-event",
+				"startEventId": "message-start",
+				"messageKey": 5,
+				"messageName": "message-name",
+				"interrupting": true,
+				"bpmnProcessId": "test-id",
+				"variables": {},
+				"correlationKey": "correlation-key"
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  7,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             8,
+			ValueType:            valueType,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newTimerTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"targetElementId": "timer-event",
+				"processInstanceKey": 1,
+				"processDefinitionKey": 2,
+				"elementInstanceKey": 10,
+				"dueDate": 1234567890,
+				"repetitions": -1
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeTimer,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newErrorTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"exceptionMessage": "Expected to process record, but failed",
+				"stacktrace": "java.lang.IllegalStateException: ...",
+				"errorEventPosition": 3,
+				"processInstanceKey": 1
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 3,
+			Key:                  -1,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeError,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newDecisionEvaluationTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"decisionKey": 3,
+				"decisionId": "test-decision",
+				"decisionName": "Test decision",
+				"decisionVersion": 1,
+				"decisionRequirementsId": "test-drg",
+				"decisionRequirementsKey": 2,
+				"decisionOutput": "\"approved\"",
+				"bpmnProcessId": "test-id",
+				"processDefinitionKey": 1,
+				"processInstanceKey": 2,
+				"elementId": "test-element",
+				"elementInstanceKey": 4,
+				"evaluatedDecisions": [
+					{
+						"decisionId": "test-decision",
+						"decisionName": "Test decision",
+						"decisionKey": 3,
+						"decisionVersion": 1,
+						"decisionType": "DECISION_TABLE",
+						"decisionOutput": "\"approved\"",
+						"evaluatedInputs": [
+							{
+								"inputId": "input-1",
+								"inputName": "amount",
+								"inputValue": "100"
+							}
+						],
+						"matchedRules": [
+							{
+								"ruleId": "rule-1",
+								"ruleIndex": 1,
+								"evaluatedOutputs": [
+									{
+										"outputId": "output-1",
+										"outputName": "result",
+										"outputValue": "\"approved\""
+									}
+								]
+							}
+						]
+					}
+				],
+				"evaluationFailureMessage": "",
+				"failedDecisionId": ""
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeDecisionEvaluation,
+			Intent:               intent,
+			RecordType:           RecordTypeEvent,
+			BrokerVersion:        "1.2.3",
+		},
+		touched,
+		err,
+	}
+}
+
+func newUserTaskTestRecord(
+	name string,
+	intent Intent,
+	touched []string,
+	err error,
+) *testRecord {
+	return &testRecord{
+		name,
+		&UntypedRecord{
+			PartitionID: 1,
+			Value: json.RawMessage(`{
+				"userTaskKey": 5,
+				"assignee": "demo",
+				"candidateGroupsList": ["accounting"],
+				"candidateUsersList": null,
+				"dueDate": "2024-05-01T12:00:00Z",
+				"followUpDate": "",
+				"formKey": 3,
+				"elementId": "test-user-task",
+				"elementInstanceKey": 4,
+				"bpmnProcessId": "test-id",
+				"processDefinitionVersion": 1,
+				"processDefinitionKey": 1,
+				"processInstanceKey": 2
+			}`),
+			RejectionType:        RejectionTypeNullVal,
+			RejectionReason:      "",
+			SourceRecordPosition: 4,
+			Key:                  5,
+			Timestamp:            time.Now().UnixMilli(),
+			Position:             6,
+			ValueType:            ValueTypeUserTask,
 			Intent:               intent,
 			RecordType:           RecordTypeEvent,
 			BrokerVersion:        "1.2.3",
@@ -214,25 +819,19 @@ func newProcessInstanceTestRecord(
 	}
 }
 
-func newVariableTestRecord(
+func newSignalTestRecord(
 	name string,
-	topic string,
 	intent Intent,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
-				"processInstanceKey": 1,
-				"processDefinitionKey": 2,
-				"bpmnProcessId": "test-id",
-				"scopeKey": 3,
-				"name": "testName",
-				"value": "testValue"
+				"signalName": "test-signal",
+				"variables": {"test": 1}
 			}`),
 			RejectionType:        RejectionTypeNullVal,
 			RejectionReason:      "",
@@ -240,7 +839,7 @@ func newVariableTestRecord(
 			Key:                  5,
 			Timestamp:            time.Now().UnixMilli(),
 			Position:             6,
-			ValueType:            ValueTypeVariable,
+			ValueType:            ValueTypeSignal,
 			Intent:               intent,
 			RecordType:           RecordTypeEvent,
 			BrokerVersion:        "1.2.3",
@@ -250,28 +849,22 @@ func newVariableTestRecord(
 	}
 }
 
-func newIncidentTestRecord(
+func newSignalSubscriptionTestRecord(
 	name string,
-	topic string,
 	intent Intent,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
-				"errorType": "EXTRACT_VALUE_ERROR",
-				"errorMessage": "Some error",
-				"bpmnProcessID": "test-id",
-				"processInstanceKey": 1,
-				"elementId": "Gateway_abcdefg",
-				"elementInstanceKey": 10,
-				"jobKey": -1,
-				"processDefinitionKey": 2,
-				"variableScopeKey": 3
+				"processDefinitionKey": 1,
+				"bpmnProcessId": "test-id",
+				"catchEventId": "test-catch-event",
+				"catchEventInstanceKey": -1,
+				"signalName": "test-signal"
 			}`),
 			RejectionType:        RejectionTypeNullVal,
 			RejectionReason:      "",
@@ -279,7 +872,7 @@ func newIncidentTestRecord(
 			Key:                  5,
 			Timestamp:            time.Now().UnixMilli(),
 			Position:             6,
-			ValueType:            ValueTypeIncident,
+			ValueType:            ValueTypeSignalSubscription,
 			Intent:               intent,
 			RecordType:           RecordTypeEvent,
 			BrokerVersion:        "1.2.3",
@@ -289,43 +882,33 @@ func newIncidentTestRecord(
 	}
 }
 
-func newJobTestRecord(
+func newJobBatchTestRecord(
 	name string,
-	topic string,
 	intent Intent,
+	worker string,
 	touched []string,
 	err error,
 ) *testRecord {
 	return &testRecord{
 		name,
-		topic,
 		&UntypedRecord{
 			PartitionID: 1,
 			Value: json.RawMessage(`{
-				"deadline": 1234567890,
-				"processInstanceKey": 1,
-				"retries": 2,
-				"retryBackoff": 10,
-				"recurringTime": 10,
-				"processDefinitionVersion": 1,
-				"processDefinitionKey": 2,
-				"elementInstanceKey": 10,
-				"elementId": "example-job",
-				"errorMessage": "",
-				"customHeaders": {},
-				"bpmnProcessID": "test-id",
-				"variables": {},
+				"truncated": false,
+				"maxJobsToActivate": 32,
+				"jobs": [],
 				"type": "example-job",
-				"errorCode": "",
-				"worker": "worker"
+				"timeout": 30000,
+				"worker": "` + worker + `",
+				"jobKeys": [5, 6]
 			}`),
 			RejectionType:        RejectionTypeNullVal,
 			RejectionReason:      "",
 			SourceRecordPosition: 4,
-			Key:                  5,
+			Key:                  -1,
 			Timestamp:            time.Now().UnixMilli(),
 			Position:             6,
-			ValueType:            ValueTypeJob,
+			ValueType:            ValueTypeJobBatch,
 			Intent:               intent,
 			RecordType:           RecordTypeEvent,
 			BrokerVersion:        "1.2.3",
@@ -335,26 +918,50 @@ func newJobTestRecord(
 	}
 }
 
+// Turn a test record into a rejection of the command.
+func newRejectionTestRecord(r *testRecord) *testRecord {
+	r.record.RecordType = RecordTypeCommandRejection
+	r.record.RejectionType = RejectionTypeNotFound
+	r.record.RejectionReason = "Expected to find the entity, but it doesn't exist"
+	return r
+}
+
+// Turn a test record into a command.
+func newCommandTestRecord(r *testRecord) *testRecord {
+	r.record.RecordType = RecordTypeCommand
+	return r
+}
+
 var errTest = errors.New("errTest")
 var testData = []*testRecord{
 	newDeploymentTestRecord(
 		"DeploymentCreated",
-		"zeebe-deployment",
 		IntentCreated,
-		[]string{"ProcessDeployed"},
+		[]string{"DeploymentCreated", "ProcessDeployed"},
 		nil,
 	),
 	newDeploymentTestRecord(
 		"DeploymentCreatedError",
-		"zeebe-deployment",
 		IntentCreated,
-		[]string{"ProcessDeployed"},
+		[]string{"DeploymentCreated", "ProcessDeployed"},
+		errTest,
+	),
+
+	newDmnDeploymentTestRecord(
+		"DmnDeploymentCreated",
+		IntentCreated,
+		[]string{"DeploymentCreated", "DecisionRequirementsDeployed", "DecisionDeployed"},
+		nil,
+	),
+	newDmnDeploymentTestRecord(
+		"DmnDeploymentCreatedError",
+		IntentCreated,
+		[]string{"DeploymentCreated", "DecisionRequirementsDeployed", "DecisionDeployed"},
 		errTest,
 	),
 
 	newProcessTestRecord(
 		"ProcessCreated",
-		"zeebe-process",
 		IntentCreated,
 		nil,
 		nil,
@@ -362,92 +969,93 @@ var testData = []*testRecord{
 
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementActivating",
-		"zeebe-process-instance",
 		IntentElementActivating,
-		[]string{"AuditLogEventOccurred"},
+		[]string{"ElementInstanceActivating", "AuditLogEventOccurred"},
 		nil,
 	),
+	newProcessInstanceTestRecord(
+		"ProcessInstanceElementActivatingError",
+		IntentElementActivating,
+		[]string{"ElementInstanceActivating", "AuditLogEventOccurred"},
+		errTest,
+	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementActivated",
-		"zeebe-process-instance",
 		IntentElementActivated,
-		[]string{"ProcessInstanceActivated", "AuditLogEventOccurred"},
+		[]string{"ElementInstanceUpdated", "ProcessInstanceActivated", "AuditLogEventOccurred"},
 		nil,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementActivatedError",
-		"zeebe-process-instance",
 		IntentElementActivated,
-		[]string{"ProcessInstanceActivated", "AuditLogEventOccurred"},
+		// The instance isn't activated if its element instance can't be
+		// updated
+		[]string{"ElementInstanceUpdated", "AuditLogEventOccurred"},
 		errTest,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementCompleting",
-		"zeebe-process-instance",
 		IntentElementCompleting,
-		[]string{"AuditLogEventOccurred"},
+		[]string{"ElementInstanceUpdated", "AuditLogEventOccurred"},
 		nil,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementCompleted",
-		"zeebe-process-instance",
 		IntentElementCompleted,
-		[]string{"ProcessInstanceCompleted", "AuditLogEventOccurred"},
+		[]string{"ElementInstanceEnded", "ProcessInstanceCompleted", "AuditLogEventOccurred"},
 		nil,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementCompletedError",
-		"zeebe-process-instance",
 		IntentElementCompleted,
-		[]string{"ProcessInstanceCompleted", "AuditLogEventOccurred"},
+		[]string{"ElementInstanceEnded", "AuditLogEventOccurred"},
 		errTest,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementTerminating",
-		"zeebe-process-instance",
 		IntentElementTerminating,
-		[]string{"AuditLogEventOccurred"},
+		[]string{"ElementInstanceUpdated", "AuditLogEventOccurred"},
 		nil,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementTerminated",
-		"zeebe-process-instance",
 		IntentElementTerminated,
-		[]string{"ProcessInstanceTerminated", "AuditLogEventOccurred"},
+		[]string{"ElementInstanceEnded", "ProcessInstanceTerminated", "AuditLogEventOccurred"},
 		nil,
 	),
 	newProcessInstanceTestRecord(
 		"ProcessInstanceElementTerminatedError",
-		"zeebe-process-instance",
 		IntentElementTerminated,
-		[]string{"ProcessInstanceTerminated", "AuditLogEventOccurred"},
+		[]string{"ElementInstanceEnded", "AuditLogEventOccurred"},
 		errTest,
 	),
+	newProcessInstanceTestRecord(
+		"ProcessInstanceSequenceFlowTaken",
+		IntentSequenceFlowTaken,
+		[]string{"AuditLogEventOccurred"},
+		nil,
+	),
 
 	newVariableTestRecord(
 		"VariableCreated",
-		"zeebe-variable",
 		IntentCreated,
-		[]string{"VariableCreated"},
+		[]string{"VariableCreated", "VariableChanged"},
 		nil,
 	),
 	newVariableTestRecord(
 		"VariableCreatedError",
-		"zeebe-variable",
 		IntentCreated,
 		[]string{"VariableCreated"},
 		errTest,
 	),
 	newVariableTestRecord(
 		"VariableUpdated",
-		"zeebe-variable",
 		IntentUpdated,
-		[]string{"VariableUpdated"},
+		[]string{"VariableUpdated", "VariableChanged"},
 		nil,
 	),
 	newVariableTestRecord(
 		"VariableUpdatedError",
-		"zeebe-variable",
 		IntentUpdated,
 		[]string{"VariableUpdated"},
 		errTest,
@@ -455,28 +1063,24 @@ var testData = []*testRecord{
 
 	newIncidentTestRecord(
 		"IncidentCreated",
-		"zeebe-incident",
 		IntentCreated,
 		[]string{"IncidentCreated"},
 		nil,
 	),
 	newIncidentTestRecord(
 		"IncidentCreatedError",
-		"zeebe-incident",
 		IntentCreated,
 		[]string{"IncidentCreated"},
 		errTest,
 	),
 	newIncidentTestRecord(
 		"IncidentResolved",
-		"zeebe-incident",
 		IntentResolved,
 		[]string{"IncidentResolved"},
 		nil,
 	),
 	newIncidentTestRecord(
 		"IncidentResolvedError",
-		"zeebe-incident",
 		IntentResolved,
 		[]string{"IncidentResolved"},
 		errTest,
@@ -484,32 +1088,297 @@ var testData = []*testRecord{
 
 	newJobTestRecord(
 		"JobCreated",
-		"zeebe-job",
 		IntentCreated,
-		[]string{"JobCreated"},
+		[]string{"JobCreated", "JobEventOccurred"},
 		nil,
 	),
 	newJobTestRecord(
 		"JobCreatedError",
-		"zeebe-job",
 		IntentCreated,
 		[]string{"JobCreated"},
 		errTest,
 	),
 	newJobTestRecord(
 		"JobUpdated",
-		"zeebe-job",
 		IntentCompleted,
-		[]string{"JobUpdated"},
+		[]string{"JobUpdated", "JobEventOccurred"},
 		nil,
 	),
 	newJobTestRecord(
 		"JobUpdatedError",
-		"zeebe-job",
 		IntentCompleted,
 		[]string{"JobUpdated"},
 		errTest,
 	),
+	newCommandTestRecord(newJobTestRecord(
+		"JobCompleteCommand",
+		IntentComplete,
+		[]string{"JobUpdated"},
+		nil,
+	)),
+
+	newCommandTestRecord(newJobBatchTestRecord(
+		"JobBatchActivate",
+		IntentActivate,
+		"worker",
+		[]string{"JobBatchOccurred"},
+		nil,
+	)),
+	newJobBatchTestRecord(
+		"JobBatchActivated",
+		IntentActivated,
+		"worker",
+		[]string{"JobBatchOccurred"},
+		nil,
+	),
+	newJobBatchTestRecord(
+		"JobBatchActivatedError",
+		IntentActivated,
+		"worker",
+		[]string{"JobBatchOccurred"},
+		errTest,
+	),
+	newJobBatchTestRecord(
+		"JobBatchWithoutWorker",
+		IntentActivated,
+		"",
+		[]string{},
+		nil,
+	),
+
+	newMessageTestRecord(
+		"MessagePublished",
+		IntentPublished,
+		[]string{"MessagePublished"},
+		nil,
+	),
+	newMessageTestRecord(
+		"MessageExpired",
+		IntentExpired,
+		[]string{"MessageExpired"},
+		nil,
+	),
+	newMessageTestRecord(
+		"MessageExpiredError",
+		IntentExpired,
+		[]string{"MessageExpired"},
+		errTest,
+	),
+	newMessageTestRecord(
+		"MessagePublishCommand",
+		IntentPublish,
+		[]string{},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageSubscriptionCreated",
+		ValueTypeMessageSubscription,
+		IntentCreated,
+		[]string{"MessageSubscriptionUpdated"},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageSubscriptionCorrelating",
+		ValueTypeMessageSubscription,
+		IntentCorrelating,
+		[]string{"MessageSubscriptionUpdated", "MessageCorrelated"},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageSubscriptionCorrelatingError",
+		ValueTypeMessageSubscription,
+		IntentCorrelating,
+		// Failing to update the subscription fails the whole record
+		[]string{"MessageSubscriptionUpdated"},
+		errTest,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageSubscriptionCorrelateCommand",
+		ValueTypeMessageSubscription,
+		IntentCorrelate,
+		[]string{},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"ProcessMessageSubscriptionCorrelated",
+		ValueTypeProcessMessageSubscription,
+		IntentCorrelated,
+		[]string{"MessageSubscriptionUpdated"},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"ProcessMessageSubscriptionDeletedError",
+		ValueTypeProcessMessageSubscription,
+		IntentDeleted,
+		[]string{"MessageSubscriptionUpdated"},
+		errTest,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageStartEventSubscriptionCorrelated",
+		ValueTypeMessageStartEventSubscription,
+		IntentCorrelated,
+		[]string{"MessageCorrelated"},
+		nil,
+	),
+	newMessageSubscriptionTestRecord(
+		"MessageStartEventSubscriptionCreated",
+		ValueTypeMessageStartEventSubscription,
+		IntentCreated,
+		[]string{},
+		nil,
+	),
+
+	newTimerTestRecord(
+		"TimerCreated",
+		IntentCreated,
+		[]string{"TimerCreated"},
+		nil,
+	),
+	newTimerTestRecord(
+		"TimerCreatedError",
+		IntentCreated,
+		[]string{"TimerCreated"},
+		errTest,
+	),
+	newTimerTestRecord(
+		"TimerTriggered",
+		IntentTriggered,
+		[]string{"TimerUpdated"},
+		nil,
+	),
+	newTimerTestRecord(
+		"TimerCanceledError",
+		IntentCanceled,
+		[]string{"TimerUpdated"},
+		errTest,
+	),
+	newTimerTestRecord(
+		"TimerTriggerCommand",
+		IntentTrigger,
+		[]string{},
+		nil,
+	),
+
+	newDecisionEvaluationTestRecord(
+		"DecisionEvaluated",
+		IntentEvaluated,
+		[]string{"DecisionEvaluated"},
+		nil,
+	),
+	newDecisionEvaluationTestRecord(
+		"DecisionEvaluationFailed",
+		IntentFailed,
+		[]string{"DecisionEvaluated"},
+		nil,
+	),
+	newDecisionEvaluationTestRecord(
+		"DecisionEvaluatedError",
+		IntentEvaluated,
+		[]string{"DecisionEvaluated"},
+		errTest,
+	),
+
+	newUserTaskTestRecord(
+		"UserTaskCreated",
+		IntentCreated,
+		[]string{"UserTaskCreated"},
+		nil,
+	),
+	newUserTaskTestRecord(
+		"UserTaskCreatedError",
+		IntentCreated,
+		[]string{"UserTaskCreated"},
+		errTest,
+	),
+	newUserTaskTestRecord(
+		"UserTaskAssigned",
+		IntentAssigned,
+		[]string{"UserTaskUpdated"},
+		nil,
+	),
+	newUserTaskTestRecord(
+		"UserTaskUpdated",
+		IntentUpdated,
+		[]string{"UserTaskUpdated"},
+		nil,
+	),
+	newUserTaskTestRecord(
+		"UserTaskCompleted",
+		IntentCompleted,
+		[]string{"UserTaskEnded"},
+		nil,
+	),
+	newUserTaskTestRecord(
+		"UserTaskCanceledError",
+		IntentCanceled,
+		[]string{"UserTaskEnded"},
+		errTest,
+	),
+	newUserTaskTestRecord(
+		"UserTaskAssigning",
+		IntentAssigning,
+		[]string{},
+		nil,
+	),
+
+	newSignalTestRecord(
+		"SignalBroadcasted",
+		IntentBroadcasted,
+		[]string{"SignalBroadcasted"},
+		nil,
+	),
+	newSignalTestRecord(
+		"SignalBroadcastedError",
+		IntentBroadcasted,
+		[]string{"SignalBroadcasted"},
+		errTest,
+	),
+
+	newSignalSubscriptionTestRecord(
+		"SignalSubscriptionCreated",
+		IntentCreated,
+		[]string{"SignalSubscriptionUpdated"},
+		nil,
+	),
+	newSignalSubscriptionTestRecord(
+		"SignalSubscriptionDeletedError",
+		IntentDeleted,
+		[]string{"SignalSubscriptionUpdated"},
+		errTest,
+	),
+
+	newErrorTestRecord(
+		"ErrorCreated",
+		IntentCreated,
+		[]string{"BrokerErrorOccurred"},
+		nil,
+	),
+	newErrorTestRecord(
+		"ErrorCreatedError",
+		IntentCreated,
+		[]string{"BrokerErrorOccurred"},
+		errTest,
+	),
+
+	// Rejections are stored as such regardless of value type
+	newRejectionTestRecord(newJobTestRecord(
+		"JobCompleteRejected",
+		IntentComplete,
+		[]string{"CommandRejected"},
+		nil,
+	)),
+	newRejectionTestRecord(newJobTestRecord(
+		"JobCompleteRejectedError",
+		IntentComplete,
+		[]string{"CommandRejected"},
+		errTest,
+	)),
+	newRejectionTestRecord(newProcessInstanceTestRecord(
+		"ProcessInstanceActivateRejected",
+		IntentActivateElement,
+		[]string{"CommandRejected"},
+		nil,
+	)),
 }
 
 // Test creating and closing database updater successfully and processing a record.
@@ -520,7 +1389,7 @@ func TestDatabaseUpdater(t *testing.T) {
 	msgChannels["zeebe-process"] = make(msgChannelType)
 	closeChannel := make(signalChannelType)
 
-	updater := newDatabaseUpdater(storer, msgChannels, closeChannel, &wg)
+	updater := newDatabaseUpdater(storer, newProgressTracker(), DefaultTopics("zeebe"), msgChannels, closeChannel, &wg)
 	defer func() {
 		// close closeChannel to make reads from it succeed (and return
 		// nil)
@@ -540,7 +1409,12 @@ func TestDatabaseUpdater(t *testing.T) {
 	// Put in an empty JSON record - this is invalid and will cause an
 	// error in handling, but errors in handling should not crash or
 	// anything.
-	msgChannels["zeebe-process"] <- []byte(`{}`)
+	msgChannels["zeebe-process"] <- message{
+		topic:     "zeebe-process",
+		partition: 0,
+		offset:    0,
+		value:     []byte(`{}`),
+	}
 }
 
 // Automatically test that handlers hit all their intended storer functions.
@@ -568,7 +1442,7 @@ func TestStoring(t *testing.T) {
 			// Pass it to the handling dispatcher (if the dispatch
 			// does something wrong we'll likely touch the wrong
 			// database updater at the end and fail that way)
-			err := updater.handlingDispatch(r.topic, r.record)
+			err := updater.handlingDispatch(r.record)
 
 			if r.err == nil {
 				assert.NoError(t, err)
@@ -636,10 +1510,177 @@ func TestMissingDeploymentResource(t *testing.T) {
 	}
 
 	// There should be an error now
-	err := updater.handlingDispatch("zeebe-deployment", untypedRecord)
+	err := updater.handlingDispatch(untypedRecord)
 	assert.ErrorContains(t, err, "resource not in map")
 }
 
+func TestRedeployUnchangedProcess(t *testing.T) {
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(t.TempDir(), "redeploy.db")),
+		&gorm.Config{
+			DisableForeignKeyConstraintWhenMigrating: true,
+			Logger:                                   logger.Discard,
+		},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, storage.AutoMigrate(db))
+
+	updater := &storageUpdater{
+		storer:     storage.NewStorer(db),
+		reconciler: newReconciler(),
+	}
+
+	deployedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	deployment := newDeploymentTestRecord("", IntentCreated, nil, nil).record
+	deployment.Timestamp = deployedAt.UnixMilli()
+	assert.NoError(t, updater.handlingDispatch(deployment))
+
+	// Deploying the same process again creates a new deployment whose
+	// process metadata is marked as a duplicate
+	redeployment := newDeploymentTestRecord("", IntentCreated, nil, nil).record
+	redeployment.Value = json.RawMessage(strings.Replace(
+		string(redeployment.Value), `"duplicate": false`, `"duplicate": true`, 1))
+	redeployment.Key = 7
+	redeployment.Position = 8
+	redeployment.Timestamp = deployedAt.Add(time.Hour).UnixMilli()
+	assert.NoError(t, updater.handlingDispatch(redeployment))
+
+	process, err := storage.NewFetcher(db).GetProcess(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, deployedAt, process.DeploymentTime.UTC())
+	assert.Equal(t, int64(6), process.Position)
+}
+
+// Test that a subscription correlating a message it has been consumed before
+// waits for the message, and marks it correlated once it's published.
+func TestSubscriptionCorrelatedBeforeMessage(t *testing.T) {
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(t.TempDir(), "correlate.db")),
+		&gorm.Config{
+			DisableForeignKeyConstraintWhenMigrating: true,
+			Logger:                                   logger.Discard,
+		},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, storage.AutoMigrate(db))
+
+	updater := &storageUpdater{
+		storer:     storage.NewStorer(db),
+		reconciler: newReconciler(),
+	}
+	apply := func(topic string, record *UntypedRecord) {
+		value, err := json.Marshal(record)
+		assert.NoError(t, err)
+		err = updater.apply(message{topic: topic, value: value}, record, time.Now())
+		assert.NoError(t, err)
+	}
+
+	const messageKey = 300
+	subscriptionValue, err := json.Marshal(MessageSubscriptionValue{
+		ProcessInstanceKey: 100,
+		ElementInstanceKey: 101,
+		MessageKey:         messageKey,
+		MessageName:        "payment-received",
+		CorrelationKey:     "order-1",
+	})
+	assert.NoError(t, err)
+	correlating := &UntypedRecord{
+		PartitionID: 1,
+		Position:    20,
+		Key:         50,
+		Timestamp:   2000,
+		RecordType:  RecordTypeEvent,
+		ValueType:   ValueTypeMessageSubscription,
+		Intent:      IntentCorrelating,
+		Value:       subscriptionValue,
+	}
+
+	apply("zeebe-message-subscription", correlating)
+	// Parked under the message it's waiting for, not the subscription
+	assert.Len(t, updater.reconciler.parked[messageKey], 1)
+
+	messageValue, err := json.Marshal(MessageValue{
+		Name:           "payment-received",
+		CorrelationKey: "order-1",
+		TimeToLive:     60000,
+	})
+	assert.NoError(t, err)
+	published := &UntypedRecord{
+		PartitionID: 1,
+		Position:    10,
+		Key:         messageKey,
+		Timestamp:   1000,
+		RecordType:  RecordTypeEvent,
+		ValueType:   ValueTypeMessage,
+		Intent:      IntentPublished,
+		Value:       messageValue,
+	}
+
+	apply("zeebe-message", published)
+	assert.Empty(t, updater.reconciler.parked)
+
+	var pending int64
+	assert.NoError(t, db.Model(&storage.PendingRecord{}).Count(&pending).Error)
+	assert.Equal(t, int64(0), pending)
+
+	var stored storage.Message
+	assert.NoError(t, db.First(&stored, messageKey).Error)
+	assert.Equal(t, "CORRELATED", stored.State)
+	assert.Equal(t, int64(20), stored.Position)
+
+	var subscriptions []storage.MessageSubscription
+	assert.NoError(t, db.Find(&subscriptions).Error)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, int64(messageKey), subscriptions[0].MessageKey)
+}
+
+func TestDeploymentContents(t *testing.T) {
+	value := DeploymentValue{
+		Resources: []DeploymentValueResource{
+			{Resource: []byte("<bpmn/>"), ResourceName: "process.bpmn"},
+			{Resource: []byte("<dmn/>"), ResourceName: "decision.dmn"},
+			{Resource: []byte("{}"), ResourceName: "form.form"},
+			{Resource: []byte("text"), ResourceName: "readme.txt"},
+		},
+		ProcessesMetadata: []DeploymentValueProcessesMetadata{
+			{BpmnProcessID: "process", Version: 2, ProcessDefinitionKey: 1, ResourceName: "process.bpmn"},
+		},
+		DecisionRequirementsMetadata: []DeploymentValueDecisionRequirementsMetadata{
+			{DecisionRequirementsID: "drg", DecisionRequirementsVersion: 1, DecisionRequirementsKey: 2, ResourceName: "decision.dmn", Duplicate: true},
+		},
+		DecisionsMetadata: []DeploymentValueDecisionsMetadata{
+			{DecisionID: "decision", Version: 1, DecisionKey: 3, DecisionRequirementsKey: 2, Duplicate: true},
+		},
+		FormMetadata: []DeploymentValueFormMetadata{
+			{FormID: "form", Version: 1, FormKey: 4, ResourceName: "form.form"},
+		},
+	}
+
+	resources, definitions := deploymentContents(value)
+
+	assert.Equal(t, []storage.DeploymentResource{
+		{ResourceName: "process.bpmn", ResourceType: "BPMN", Size: 7},
+		{ResourceName: "decision.dmn", ResourceType: "DMN", Size: 6},
+		{ResourceName: "form.form", ResourceType: "FORM", Size: 2},
+		{ResourceName: "readme.txt", ResourceType: "OTHER", Size: 4},
+	}, resources)
+	assert.Equal(t, []storage.DeployedDefinition{
+		{DefinitionKey: 1, Kind: "PROCESS", DefinitionID: "process", Version: 2, ResourceName: "process.bpmn"},
+		{DefinitionKey: 2, Kind: "DECISION_REQUIREMENTS", DefinitionID: "drg", Version: 1, ResourceName: "decision.dmn", Duplicate: true},
+		{DefinitionKey: 3, Kind: "DECISION", DefinitionID: "decision", Version: 1, ResourceName: "decision.dmn", Duplicate: true},
+		{DefinitionKey: 4, Kind: "FORM", DefinitionID: "form", Version: 1, ResourceName: "form.form"},
+	}, definitions)
+}
+
+func TestParseUserTaskDate(t *testing.T) {
+	assert.Equal(t, sql.NullTime{}, parseUserTaskDate(""))
+	assert.Equal(t, sql.NullTime{}, parseUserTaskDate("tomorrow"))
+	assert.Equal(t, sql.NullTime{
+		Time:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Valid: true,
+	}, parseUserTaskDate("2024-05-01T12:00:00Z"))
+}
+
 func TestDispatchInvalidRecord(t *testing.T) {
 	storer := newFixedErrStorer(nil)
 	updater := &storageUpdater{
@@ -651,17 +1692,23 @@ func TestDispatchInvalidRecord(t *testing.T) {
 		wg: nil,
 	}
 
-	// Pass in a zero-value record; this should fail and not hit topic
+	// Pass in a zero-value record; this should fail and not hit value type
 	// handling at all
-	err := updater.handlingDispatch("invalid-topic", &UntypedRecord{})
+	err := updater.handlingDispatch(&UntypedRecord{})
 	assert.ErrorContains(t, err, "zero-value value type in record")
 }
 
-// Test that a value type in the incorrect topic breaks
+// Test that records of value types a topic isn't configured for are skipped
 func TestValueTypeInWrongTopic(t *testing.T) {
 	storer := newFixedErrStorer(nil)
 	updater := &storageUpdater{
 		storer: storer,
+		topics: Topics{
+			"zeebe":     {ValueTypeProcessInstance, ValueTypeVariable},
+			"zeebe-job": {ValueTypeJob},
+			"zeebe-any": {},
+		},
+		reconciler: newReconciler(),
 
 		msgChannels:  nil,
 		closeChannel: nil,
@@ -669,49 +1716,224 @@ func TestValueTypeInWrongTopic(t *testing.T) {
 		wg: nil,
 	}
 
-	// First create two records we'll use for this
-	// Job for all the non-job topics
 	r := newJobTestRecord(
 		"Job",
-		"zeebe-job",
 		IntentCreated,
 		[]string{"JobCreated"},
 		nil,
 	)
-	// process instnce for the others
-	r2 := newProcessInstanceTestRecord(
-		"JobInstance",
-		"zeebe-process-instance",
-		IntentCreated,
-		[]string{"ProcessInstanceCreated"},
-		nil,
-	)
+	value, err := json.Marshal(r.record)
+	assert.NoError(t, err)
 
-	topics := []string{
-		"zeebe-deployment",
-		"zeebe-process",
-		"zeebe-process-instance",
-		"zeebe-variable",
-		"zeebe-incident",
-		"zeebe-job",
+	tests := []struct {
+		topic   string
+		touched bool
+	}{
+		{topic: "zeebe", touched: false},
+		{topic: "zeebe-job", touched: true},
+		{topic: "zeebe-any", touched: true},
+		{topic: "unconfigured", touched: true},
 	}
 
-	for _, topic := range topics {
-		t.Run(topic, func(t *testing.T) {
+	for _, test := range tests {
+		test := test
+		t.Run(test.topic, func(t *testing.T) {
 			t.Cleanup(func() {
 				// Reset err and the touched map after a test
 				storer.reset()
 			})
 
-			if topic != r.topic {
-				err := updater.handlingDispatch(topic, r.record)
-				assert.ErrorContains(t, err, "failed to cast")
-			} else {
-				// for the one other topic use r2 instead
-				err := updater.handlingDispatch(topic, r2.record)
-				assert.ErrorContains(t, err, "failed to cast")
+			err := updater.applyMessage(message{
+				topic: test.topic,
+				value: value,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.touched, storer.touched["JobCreated"])
+		})
+	}
+}
+
+// Storer tracking transactions, optionally failing to commit the next
+// outermost one.
+type transactionStorer struct {
+	*jobOrderStorer
+	depth      int
+	commits    int
+	failCommit bool
+}
+
+func (s *transactionStorer) Transaction(fn func(storage.Storer) error) error {
+	s.depth++
+	err := fn(s)
+	s.depth--
+	if err != nil || s.depth > 0 {
+		return err
+	}
+
+	if s.failCommit {
+		s.failCommit = false
+		return errTest
+	}
+	s.commits++
+	return nil
+}
+
+// Test that records in a batch are applied and fail independently of each
+// other.
+func TestApplyBatch(t *testing.T) {
+	var batch []message
+	for offset, r := range []*testRecord{
+		// Applied only after the job has been created
+		newJobTestRecord("JobCompleted", IntentCompleted, nil, nil),
+		newJobTestRecord("JobCreated", IntentCreated, nil, nil),
+	} {
+		value, err := json.Marshal(r.record)
+		assert.NoError(t, err)
+		batch = append(batch, message{topic: "zeebe", offset: int64(offset), value: value})
+	}
+	batch = append(batch, message{topic: "zeebe", offset: 2, value: []byte(`{`)})
+
+	tests := []struct {
+		name       string
+		failCommit bool
+		commits    int
+	}{
+		// The batch, then the released record
+		{name: "batch", failCommit: false, commits: 2},
+		// Each record on its own: the created job, and the update
+		// released once even though the failed batch parked it too
+		{name: "failed commit falls back", failCommit: true, commits: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storer := &transactionStorer{
+				jobOrderStorer: newJobOrderStorer(),
+				failCommit:     test.failCommit,
+			}
+			updater := &storageUpdater{
+				storer:     storer,
+				progress:   newProgressTracker(),
+				reconciler: newReconciler(),
+			}
+
+			updater.applyBatch(batch)
+
+			assert.True(t, storer.touched["JobCreated"])
+			assert.True(t, storer.touched["JobUpdated"])
+			assert.True(t, storer.touched["DeadLetterStored"])
+			assert.True(t, storer.touched["OffsetCommitted"])
+			assert.Empty(t, updater.reconciler.parked)
+			assert.Equal(t, test.commits, storer.commits)
+		})
+	}
+}
+
+// Messages with process instances and their variables to benchmark applying
+// records with.
+func benchmarkMessages(count int) []message {
+	msgs := make([]message, 0, count)
+	for i := 0; i < count; i++ {
+		position := int64(i + 1)
+		instanceKey := position - int64(i%3)
+
+		var value string
+		if i%3 != 2 {
+			intent := IntentElementActivating
+			if i%3 == 1 {
+				intent = IntentElementActivated
 			}
+			value = fmt.Sprintf(`{
+				"valueType": "PROCESS_INSTANCE",
+				"intent": "%s",
+				"recordType": "EVENT",
+				"key": %d,
+				"position": %d,
+				"timestamp": 1000,
+				"value": {
+					"bpmnElementType": "PROCESS",
+					"processInstanceKey": %d,
+					"processDefinitionKey": 1,
+					"version": 1
+				}
+			}`, intent, instanceKey, position, instanceKey)
+		} else {
+			value = fmt.Sprintf(`{
+				"valueType": "VARIABLE",
+				"intent": "CREATED",
+				"recordType": "EVENT",
+				"key": %d,
+				"position": %d,
+				"timestamp": 1000,
+				"value": {
+					"processInstanceKey": %d,
+					"name": "benchmark",
+					"value": "1"
+				}
+			}`, position, position, instanceKey)
+		}
+
+		msgs = append(msgs, message{
+			topic:  "zeebe",
+			offset: int64(i),
+			value:  []byte(value),
 		})
+	}
+
+	return msgs
+}
+
+// Compare the throughput of applying records one by one, as before batching,
+// with applying them in batches.
+func BenchmarkApplyRecords(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		apply func(updater *storageUpdater, msgs []message)
+	}{
+		{
+			"one by one",
+			func(updater *storageUpdater, msgs []message) {
+				for _, msg := range msgs {
+					updater.applyAndCommit(msg)
+				}
+			},
+		},
+		{
+			"batched",
+			func(updater *storageUpdater, msgs []message) {
+				for start := 0; start < len(msgs); start += BatchMaxSize {
+					updater.applyBatch(msgs[start:min(start+BatchMaxSize, len(msgs))])
+				}
+			},
+		},
+	}
+
+	// The updater logs every record
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
 
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			db, err := gorm.Open(
+				sqlite.Open(filepath.Join(b.TempDir(), "benchmark.db")),
+				&gorm.Config{
+					DisableForeignKeyConstraintWhenMigrating: true,
+					Logger:                                   logger.Discard,
+				},
+			)
+			assert.NoError(b, err)
+			assert.NoError(b, storage.AutoMigrate(db))
+
+			updater := &storageUpdater{
+				storer:     storage.NewStorer(db),
+				progress:   newProgressTracker(),
+				reconciler: newReconciler(),
+			}
+			msgs := benchmarkMessages(b.N)
+
+			b.ResetTimer()
+			benchmark.apply(updater, msgs)
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "records/s")
+		})
 	}
 }
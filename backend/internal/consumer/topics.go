@@ -0,0 +1,68 @@
+package consumer
+
+import (
+	"slices"
+)
+
+// Topics maps each Kafka topic to the value types of the records consumed
+// from it. A topic without any value types accepts records of every value
+// type.
+type Topics map[string][]ValueType
+
+// Topic name suffixes used by the default Zeebe Kafka exporter
+// configuration. Each topic is named by the prefix followed by the suffix.
+var defaultTopicSuffixes = map[ValueType]string{
+	ValueTypeDecisionEvaluation:            "decision-evaluation",
+	ValueTypeDeployment:                    "deployment",
+	ValueTypeDeploymentDistribution:        "deployment-distribution",
+	ValueTypeError:                         "error",
+	ValueTypeEscalation:                    "escalation",
+	ValueTypeForm:                          "form",
+	ValueTypeIncident:                      "incident",
+	ValueTypeJob:                           "job",
+	ValueTypeJobBatch:                      "job-batch",
+	ValueTypeMessage:                       "message",
+	ValueTypeMessageSubscription:           "message-subscription",
+	ValueTypeMessageStartEventSubscription: "message-subscription-start-event",
+	ValueTypeProcess:                       "process",
+	ValueTypeProcessEvent:                  "process-event",
+	ValueTypeProcessInstance:               "process-instance",
+	ValueTypeProcessInstanceMigration:      "process-instance-migration",
+	ValueTypeProcessInstanceResult:         "process-instance-result",
+	ValueTypeProcessMessageSubscription:    "process-message-subscription",
+	ValueTypeResourceDeletion:              "resource-deletion",
+	ValueTypeSignal:                        "signal",
+	ValueTypeSignalSubscription:            "signal-subscription",
+	ValueTypeTimer:                         "timer",
+	ValueTypeUserTask:                      "user-task",
+	ValueTypeVariable:                      "variable",
+}
+
+// DefaultTopics returns the topic layout of the default Zeebe Kafka exporter
+// configuration with the given topic name prefix, e.g. "zeebe".
+func DefaultTopics(prefix string) Topics {
+	topics := Topics{}
+	for valueType, suffix := range defaultTopicSuffixes {
+		topics[prefix+"-"+suffix] = []ValueType{valueType}
+	}
+
+	return topics
+}
+
+// Returns the names of the topics in alphabetical order.
+func (t Topics) names() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Returns whether records of the given value type are consumed from a topic.
+// Topics that aren't part of the layout accept every value type.
+func (t Topics) accepts(topic string, valueType ValueType) bool {
+	valueTypes := t[topic]
+	return len(valueTypes) == 0 || slices.Contains(valueTypes, valueType)
+}
@@ -0,0 +1,16 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultTopics(t *testing.T) {
+	topics := DefaultTopics("custom")
+
+	assert.Len(t, topics, len(defaultTopicSuffixes))
+	assert.Equal(t, []ValueType{ValueTypeProcessInstance}, topics["custom-process-instance"])
+	assert.Equal(t, []ValueType{ValueTypeMessageStartEventSubscription}, topics["custom-message-subscription-start-event"])
+	assert.Equal(t, "custom-decision-evaluation", topics.names()[0])
+}
@@ -19,9 +19,13 @@ const (
 	KeepAlivePingInterval = 5
 )
 
-func newAPIHandler(fetcher *storage.Fetcher) *qlhandler.Server {
+func newAPIHandler(fetcher *storage.Fetcher, deadLetters graph.DeadLetterQueue, pendingRecords graph.PendingRecordQueue) *qlhandler.Server {
 	// Setup GraphQL schema options.
-	rootResolver := &graph.Resolver{Fetcher: fetcher}
+	rootResolver := &graph.Resolver{
+		Fetcher:        fetcher,
+		DeadLetters:    deadLetters,
+		PendingRecords: pendingRecords,
+	}
 	config := graph.Config{Resolvers: rootResolver}
 	schema := graph.NewExecutableSchema(config)
 
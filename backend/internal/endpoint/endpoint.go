@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/ducanhpham0312/zeevision/backend/graph"
 	"github.com/ducanhpham0312/zeevision/backend/internal/environment"
 	"github.com/ducanhpham0312/zeevision/backend/internal/storage"
 	"github.com/gin-gonic/gin"
@@ -50,6 +51,9 @@ type Config struct {
 	Production bool
 	// This defines the allowed origins for CORS.
 	AllowedOrigins []string
+	// The bearer token required for pushing records to the ingest
+	// endpoint. The endpoint is disabled if empty.
+	IngestToken string
 }
 
 // Endpoint represents a server that handles incoming requests.
@@ -59,7 +63,7 @@ type Endpoint struct {
 }
 
 // Create a new endpoint from environment variables.
-func NewFromEnv(fetcher *storage.Fetcher) (*Endpoint, error) {
+func NewFromEnv(fetcher *storage.Fetcher, deadLetters graph.DeadLetterQueue, pendingRecords graph.PendingRecordQueue, ingester RecordIngester) (*Endpoint, error) {
 	// Create configuration from environment variables.
 	conf := Config{
 		AppPort:          environment.AppPort(),
@@ -68,13 +72,14 @@ func NewFromEnv(fetcher *storage.Fetcher) (*Endpoint, error) {
 		DoHostPlayground: environment.DoHostPlayground(),
 		Production:       environment.IsProduction(),
 		AllowedOrigins:   environment.APIAllowedOrigins(),
+		IngestToken:      environment.IngestToken(),
 	}
 
-	return New(conf, fetcher)
+	return New(conf, fetcher, deadLetters, pendingRecords, ingester)
 }
 
 // Create a new endpoint.
-func New(conf Config, fetcher *storage.Fetcher) (*Endpoint, error) {
+func New(conf Config, fetcher *storage.Fetcher, deadLetters graph.DeadLetterQueue, pendingRecords graph.PendingRecordQueue, ingester RecordIngester) (*Endpoint, error) {
 	var appServer *http.Server
 	if conf.DoHostApp {
 		var err error
@@ -84,7 +89,7 @@ func New(conf Config, fetcher *storage.Fetcher) (*Endpoint, error) {
 		}
 	}
 
-	apiServer, err := NewAPIServer(conf, fetcher)
+	apiServer, err := NewAPIServer(conf, fetcher, deadLetters, pendingRecords, ingester)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +118,7 @@ func NewAppServer(conf Config) (*http.Server, error) {
 }
 
 // Create a new API server.
-func NewAPIServer(conf Config, fetcher *storage.Fetcher) (*http.Server, error) {
+func NewAPIServer(conf Config, fetcher *storage.Fetcher, deadLetters graph.DeadLetterQueue, pendingRecords graph.PendingRecordQueue, ingester RecordIngester) (*http.Server, error) {
 	router := chi.NewRouter()
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
@@ -125,7 +130,12 @@ func NewAPIServer(conf Config, fetcher *storage.Fetcher) (*http.Server, error) {
 		Debug:            !conf.Production,
 	}).Handler)
 
-	router.Handle(APIPath, newAPIHandler(fetcher))
+	router.Handle(APIPath, newAPIHandler(fetcher, deadLetters, pendingRecords))
+
+	// Accept pushed records only if a token has been configured.
+	if conf.IngestToken != "" {
+		router.Handle(IngestPath, newIngestHandler(ingester, conf.IngestToken))
+	}
 
 	// Host GraphQL playground if it has been configured.
 	if conf.DoHostPlayground {
@@ -0,0 +1,129 @@
+package endpoint
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ducanhpham0312/zeevision/backend/internal/consumer"
+)
+
+const (
+	// Path where records are pushed to.
+	IngestPath = "/ingest"
+
+	// Maximum size of a request body pushed to the ingest endpoint.
+	IngestMaxBodyBytes = 32 << 20
+)
+
+// RecordIngester applies raw Zeebe records pushed to the ingest endpoint.
+type RecordIngester interface {
+	// Apply a batch of records in order. Returns only once the records
+	// have been applied or stored as dead letters, along with the number
+	// of dead letters.
+	Ingest(values [][]byte) (int, error)
+}
+
+// Response body of a successful ingest request.
+type ingestResponse struct {
+	// Number of records applied.
+	Applied int `json:"applied"`
+	// Number of records that failed to apply and were stored as dead
+	// letters instead.
+	DeadLetters int `json:"deadLetters"`
+}
+
+// Create a handler which accepts a single record or an array of records in
+// the request body and applies them. Requests must carry the token in an
+// "Authorization: Bearer" header.
+//
+// The response is sent only after the records have been applied, so the
+// pushing side can retry the whole request on failure. Records that fail to
+// apply are stored as dead letters and don't fail the request.
+func newIngestHandler(ingester RecordIngester, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !hasBearerToken(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, IngestMaxBodyBytes))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		values, err := splitRecords(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deadLetters, err := ingester.Ingest(values)
+		if errors.Is(err, consumer.ErrInvalidRecord) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Ingesting records failed: %v", err)
+			http.Error(w, "failed to apply records", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(ingestResponse{
+			Applied:     len(values) - deadLetters,
+			DeadLetters: deadLetters,
+		})
+		if err != nil {
+			log.Printf("Failed to write ingest response: %v", err)
+		}
+	})
+}
+
+// Returns whether the request is authorized with the expected bearer token.
+func hasBearerToken(r *http.Request, token string) bool {
+	given, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// Split a request body holding either a single record or an array of records
+// into the raw records.
+func splitRecords(body []byte) ([][]byte, error) {
+	body = bytes.TrimSpace(body)
+	if !bytes.HasPrefix(body, []byte("[")) {
+		if !json.Valid(body) {
+			return nil, errors.New("invalid JSON in body")
+		}
+		return [][]byte{body}, nil
+	}
+
+	var records []json.RawMessage
+	err := json.Unmarshal(body, &records)
+	if err != nil {
+		return nil, errors.New("invalid JSON in body")
+	}
+
+	values := make([][]byte, 0, len(records))
+	for _, record := range records {
+		values = append(values, record)
+	}
+
+	return values, nil
+}
@@ -16,6 +16,15 @@ import (
 const (
 	// Environment variable used to configure the Kafka address.
 	EnvVarKafkaAddr = "ZEEVISION_KAFKA_ADDR"
+	// Environment variable used to configure the prefix of the Kafka topic
+	// names when using the default exporter topic layout.
+	EnvVarKafkaTopicPrefix = "ZEEVISION_KAFKA_TOPIC_PREFIX"
+	// Environment variable used to configure the Kafka topics to consume
+	// and the value types of the records on each of them, e.g.
+	// "zeebe=PROCESS_INSTANCE|JOB,zeebe-variable=VARIABLE". A topic without
+	// value types accepts records of all value types. Overrides the topic
+	// prefix.
+	EnvVarKafkaTopics = "ZEEVISION_KAFKA_TOPICS"
 	// Environment variable used to configure the port to use for the
 	// application deployment.
 	EnvVarAppPort = "ZEEVISION_APP_PORT"
@@ -42,11 +51,25 @@ const (
 	EnvVarDatabaseHost = "ZEEVISION_DATABASE_HOST"
 	// Environment variable used to configure the port is used to connect to the database.
 	EnvVarDatabasePort = "ZEEVISION_DATABASE_PORT"
+	// Environment variable used to configure rebuilding the database from
+	// the oldest records in Kafka on startup. "tail" keeps consuming new
+	// records afterwards, "exit" stops the application once done.
+	EnvVarBackfill = "ZEEVISION_BACKFILL"
+	// Environment variable used to configure the bearer token required for
+	// pushing records to the ingest endpoint. The endpoint is disabled if
+	// no token is set.
+	EnvVarIngestToken = "ZEEVISION_INGEST_TOKEN" //nolint:gosec
+	// Environment variable used to configure the encoding of the records
+	// consumed from Kafka: "json" or "protobuf". The encoding of each
+	// record is detected if not set.
+	EnvVarRecordEncoding = "ZEEVISION_RECORD_ENCODING"
 )
 
 const (
 	// Default Kafka address to use.
 	DefaultKafkaAddr = "kafka:9093"
+	// Default prefix for Kafka topic names.
+	DefaultKafkaTopicPrefix = "zeebe"
 	// Default port to use for the application.
 	DefaultAppPort = 8080
 	// Default port to use for the API.
@@ -63,11 +86,18 @@ const (
 	DefaultHostDatabase = "postgres"
 	// Default port to use for the database.
 	DefaultDatabasePort = 5432
+	// Default value for backfill mode. No backfill is done.
+	DefaultBackfill = ""
+	// Default value for record encoding. Detected for each record.
+	DefaultRecordEncoding = ""
 )
 
 var (
 	// Default value for allowed origins. None are allowed.
 	DefaultAPIAllowedOrigins = []string{}
+	// Default value for Kafka topics. None are set, so the topics are
+	// named using the topic prefix.
+	DefaultKafkaTopics = map[string][]string{}
 )
 
 var cache map[string]any
@@ -78,6 +108,8 @@ func init() {
 	cache = make(map[string]any)
 
 	setOrFallback(EnvVarKafkaAddr, DefaultKafkaAddr)
+	setOrFallback(EnvVarKafkaTopicPrefix, DefaultKafkaTopicPrefix)
+	setOrFallbackMap(EnvVarKafkaTopics, DefaultKafkaTopics, parseTopics)
 
 	setOrFallbackMap(EnvVarAppPort, DefaultAppPort, parsePort)
 	setOrFallbackMap(EnvVarAPIPort, DefaultAPIPort, parsePort)
@@ -95,6 +127,12 @@ func init() {
 	setOrFallback(EnvVarDatabaseHost, DefaultHostDatabase)
 	setOrFallback(EnvVarDatabaseUser, "")
 	setOrFallback(EnvVarDatabasePassword, "")
+
+	setOrFallbackMap(EnvVarBackfill, DefaultBackfill, parseBackfill)
+
+	setOrFallback(EnvVarIngestToken, "")
+
+	setOrFallbackMap(EnvVarRecordEncoding, DefaultRecordEncoding, parseRecordEncoding)
 }
 
 // Return the full address for Kafka where consumer can connect.
@@ -102,6 +140,17 @@ func KafkaAddress() string {
 	return cache[EnvVarKafkaAddr].(string)
 }
 
+// Return the prefix used for Kafka topic names.
+func KafkaTopicPrefix() string {
+	return cache[EnvVarKafkaTopicPrefix].(string)
+}
+
+// Return the Kafka topics mapped to the value types of the records on them.
+// Empty if the topics haven't been configured explicitly.
+func KafkaTopics() map[string][]string {
+	return cache[EnvVarKafkaTopics].(map[string][]string)
+}
+
 // Return the port the application is hosted at.
 func AppPort() uint16 {
 	return cache[EnvVarAppPort].(uint16)
@@ -159,6 +208,22 @@ func DatabaseHost() string {
 	return cache[EnvVarDatabaseHost].(string)
 }
 
+// Return the backfill mode: "" for no backfill, "tail" or "exit".
+func Backfill() string {
+	return cache[EnvVarBackfill].(string)
+}
+
+// Return the bearer token required by the ingest endpoint. Empty if the
+// endpoint is disabled.
+func IngestToken() string {
+	return cache[EnvVarIngestToken].(string)
+}
+
+// Return the record encoding: "" for detecting it, "json" or "protobuf".
+func RecordEncoding() string {
+	return cache[EnvVarRecordEncoding].(string)
+}
+
 // Helper to save environment variable value if it has been set.
 func setOrFallback(envVar string, fallback string) {
 	setOrFallbackMap(envVar, fallback, func(s string) (string, bool) {
@@ -192,3 +257,48 @@ func parsePort(value string) (uint16, bool) {
 func isOne(value string) (bool, bool) {
 	return value == "1", true
 }
+
+// Helper to only accept known backfill modes.
+func parseBackfill(value string) (string, bool) {
+	switch value {
+	case "tail", "exit":
+		return value, true
+	default:
+		return "", false
+	}
+}
+
+// Helper to only accept known record encodings.
+func parseRecordEncoding(value string) (string, bool) {
+	switch value {
+	case "json", "protobuf":
+		return value, true
+	default:
+		return "", false
+	}
+}
+
+// Helper to parse a comma separated list of topics, each optionally followed
+// by "=" and a "|" separated list of value types.
+func parseTopics(value string) (map[string][]string, bool) {
+	topics := map[string][]string{}
+	for _, entry := range strings.Split(value, ",") {
+		topic, valueTypes, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if topic == "" {
+			return nil, false
+		}
+
+		topics[topic] = []string{}
+		if !found {
+			continue
+		}
+		for _, valueType := range strings.Split(valueTypes, "|") {
+			if valueType == "" {
+				return nil, false
+			}
+			topics[topic] = append(topics[topic], valueType)
+		}
+	}
+
+	return topics, true
+}
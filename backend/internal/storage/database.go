@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -61,9 +62,121 @@ func ConnectDb(dsnConfig DsnConfig, maxRetries int, retryDelay time.Duration) (*
 
 // Migrate all tables in database automatically.
 func AutoMigrate(db *gorm.DB) error {
+	if err := migrateVariableScopes(db); err != nil {
+		return fmt.Errorf("failed to migrate variables: %w", err)
+	}
+
+	for _, model := range partitionKeyedTables {
+		if err := migratePartitionKey(db, model); err != nil {
+			return fmt.Errorf("failed to migrate partition keys: %w", err)
+		}
+	}
+
 	if err := db.AutoMigrate(TableMigrations...); err != nil {
 		return fmt.Errorf("failed to migrate tables: %w", err)
 	}
 
 	return nil
 }
+
+// Variables used to be identified by their process instance and name instead
+// of their scope and name. AutoMigrate doesn't change the primary key of an
+// existing table, so the table is rebuilt with the new key, keeping the
+// existing variables as global ones.
+func migrateVariableScopes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Variable{}) || migrator.HasColumn(&Variable{}, "ScopeKey") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		const scopedTable = "variables_scoped"
+
+		err := tx.Table(scopedTable).Migrator().CreateTable(&Variable{})
+		if err != nil {
+			return err
+		}
+
+		// Only columns the table has had from the start are copied
+		err = tx.Exec(`INSERT INTO variables_scoped (scope_key, name, process_instance_key, value, time)
+			SELECT process_instance_key, name, process_instance_key, value, time FROM variables`).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Migrator().DropTable(&Variable{})
+		if err != nil {
+			return err
+		}
+
+		return tx.Migrator().RenameTable(scopedTable, &Variable{})
+	})
+}
+
+// Tables of records keyed by their partition and position.
+var partitionKeyedTables = []any{
+	&Rejection{},
+	&BrokerError{},
+	&VariableChange{},
+	&JobEvent{},
+	&JobBatch{},
+}
+
+// Tables of records used to be keyed by the position of the record alone,
+// which is only unique within a partition. As with variables, the table is
+// rebuilt with the new key. The partition of the existing rows isn't known,
+// so they're kept in partition 0, which Zeebe doesn't use.
+func migratePartitionKey(db *gorm.DB, model any) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(model) || migrator.HasColumn(model, "PartitionID") {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	table := stmt.Schema.Table
+	unkeyedTable := table + "_unkeyed"
+
+	columnTypes, err := migrator.ColumnTypes(model)
+	if err != nil {
+		return err
+	}
+	columns := make([]string, 0, len(columnTypes))
+	for _, columnType := range columnTypes {
+		columns = append(columns, stmt.Quote(columnType.Name()))
+	}
+	copied := strings.Join(columns, ", ")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+
+		// Index names aren't per table, so the indexes of the old table
+		// are dropped for the new one to have them
+		for name := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(model, name) {
+				continue
+			}
+			if err := migrator.DropIndex(model, name); err != nil {
+				return err
+			}
+		}
+
+		if err := migrator.RenameTable(model, unkeyedTable); err != nil {
+			return err
+		}
+
+		if err := migrator.CreateTable(model); err != nil {
+			return err
+		}
+
+		err := tx.Exec(fmt.Sprintf("INSERT INTO %s (partition_id, %s) SELECT 0, %s FROM %s",
+			stmt.Quote(table), copied, copied, stmt.Quote(unkeyedTable))).Error
+		if err != nil {
+			return err
+		}
+
+		return migrator.DropTable(unkeyedTable)
+	})
+}
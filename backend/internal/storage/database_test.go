@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +23,101 @@ func TestNewDsn(t *testing.T) {
 	assert.Equal(t, result, DSN)
 }
 
+func TestMigrateVariableScopes(t *testing.T) {
+	testDb := newTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// Variables table as it was before variables had scopes
+	err := db.Exec(`CREATE TABLE variables (
+		process_instance_key bigint,
+		name text,
+		value text NOT NULL,
+		time datetime NOT NULL,
+		PRIMARY KEY (process_instance_key, name)
+	)`).Error
+	assert.NoError(t, err)
+	err = db.Exec(`INSERT INTO variables VALUES (11, 'a', '1', '2023-10-08 12:00:00')`).Error
+	assert.NoError(t, err)
+
+	err = AutoMigrate(db)
+	assert.NoError(t, err)
+
+	var variables []Variable
+	assert.NoError(t, db.Find(&variables).Error)
+	assert.Len(t, variables, 1)
+	assert.Equal(t, int64(11), variables[0].ScopeKey)
+	assert.Equal(t, int64(11), variables[0].ProcessInstanceKey)
+	assert.Equal(t, "a", variables[0].Name)
+	assert.Equal(t, "1", variables[0].Value)
+
+	// Variables of the same name in other scopes of the instance are kept
+	// apart
+	err = db.Create(&Variable{
+		ScopeKey:           12,
+		Name:               "a",
+		ProcessInstanceKey: 11,
+		Value:              "2",
+		Time:               time.Now(),
+	}).Error
+	assert.NoError(t, err)
+
+	// Migrating again leaves the table as it is
+	err = AutoMigrate(db)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Find(&variables).Error)
+	assert.Len(t, variables, 2)
+}
+
+func TestMigratePartitionKeys(t *testing.T) {
+	testDb := newTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// Rejections table as it was when rejections were keyed by position
+	err := db.Exec(`CREATE TABLE rejections (
+		position bigint PRIMARY KEY,
+		key bigint NOT NULL,
+		process_instance_key bigint NOT NULL,
+		element_id text NOT NULL,
+		value_type text NOT NULL,
+		intent text NOT NULL,
+		rejection_type text NOT NULL,
+		rejection_reason text NOT NULL,
+		time datetime NOT NULL
+	)`).Error
+	assert.NoError(t, err)
+	err = db.Exec(`CREATE INDEX idx_rejections_process_instance_key ON rejections (process_instance_key)`).Error
+	assert.NoError(t, err)
+	err = db.Exec(`INSERT INTO rejections VALUES (200, 1, 11, 'task', 'JOB', 'COMPLETE', 'NOT_FOUND', 'no job', '2023-10-08 12:00:00')`).Error
+	assert.NoError(t, err)
+
+	err = AutoMigrate(db)
+	assert.NoError(t, err)
+
+	var rejections []Rejection
+	assert.NoError(t, db.Find(&rejections).Error)
+	assert.Len(t, rejections, 1)
+	assert.Equal(t, int64(0), rejections[0].PartitionID)
+	assert.Equal(t, int64(200), rejections[0].Position)
+	assert.Equal(t, int64(11), rejections[0].ProcessInstanceKey)
+	assert.Equal(t, "no job", rejections[0].RejectionReason)
+
+	// The same position in another partition is a rejection of its own
+	err = NewStorer(db).CommandRejected(1, 200, 2, 12, "task", "JOB", "COMPLETE", "NOT_FOUND", "no job", time.Now())
+	assert.NoError(t, err)
+
+	// Migrating again leaves the table as it is
+	err = AutoMigrate(db)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Find(&rejections).Error)
+	assert.Len(t, rejections, 2)
+}
+
 func TestFillDatabase(t *testing.T) {
 	testDb := newTestDB(t)
 	defer func() {
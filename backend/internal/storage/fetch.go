@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -137,6 +138,36 @@ func (f *Fetcher) GetInstancesForProcess(ctx context.Context, pagination *Pagina
 	}).GetInstances(ctx, pagination)
 }
 
+// Gets all instances started by call activities of an instance.
+func (f *Fetcher) GetChildInstances(ctx context.Context, pagination *Pagination, parentInstanceKey int64) (Paginated[Instance], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Instance{ParentProcessInstanceKey: parentInstanceKey})
+	}).GetInstances(ctx, pagination)
+}
+
+// Gets the root of the call activity tree an instance is part of by following
+// parent links. If an ancestor hasn't been stored, the topmost stored one is
+// returned instead.
+func (f *Fetcher) GetRootInstance(ctx context.Context, instanceKey int64) (Instance, error) {
+	instance, err := f.GetInstance(ctx, instanceKey)
+	if err != nil {
+		return instance, err
+	}
+
+	for instance.ParentProcessInstanceKey > 0 {
+		parent, err := f.GetInstance(ctx, instance.ParentProcessInstanceKey)
+		if IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return instance, err
+		}
+		instance = parent
+	}
+
+	return instance, nil
+}
+
 // Gets a process by its key.
 func (f *Fetcher) GetProcess(ctx context.Context, processDefKey int64) (Process, error) {
 	var process Process
@@ -155,6 +186,137 @@ func (f *Fetcher) GetProcesses(ctx context.Context, pagination *Pagination) (Pag
 	})
 }
 
+// Gets a deployment by its key.
+func (f *Fetcher) GetDeployment(ctx context.Context, deploymentKey int64) (Deployment, error) {
+	var deployment Deployment
+	err := f.contextDB(ctx).
+		Where(&Deployment{DeploymentKey: deploymentKey}).
+		First(&deployment).
+		Error
+
+	return deployment, err
+}
+
+// Gets all deployments.
+func (f *Fetcher) GetDeployments(ctx context.Context, pagination *Pagination) (Paginated[Deployment], error) {
+	return paginatedFetch[Deployment](ctx, f, pagination, func(db *gorm.DB, deployments *[]Deployment) *gorm.DB {
+		return db.Order("time DESC").Find(deployments)
+	})
+}
+
+// Gets the resources of a deployment by their name.
+func (f *Fetcher) GetDeploymentResources(ctx context.Context, deploymentKey int64) ([]DeploymentResource, error) {
+	var resources []DeploymentResource
+	err := f.contextDB(ctx).
+		Where(&DeploymentResource{DeploymentKey: deploymentKey}).
+		Order("resource_name ASC").
+		Find(&resources).
+		Error
+
+	return resources, err
+}
+
+// Gets the definitions in the resources of a deployment by their resource
+// name.
+func (f *Fetcher) GetDeployedDefinitions(ctx context.Context, deploymentKey int64) ([]DeployedDefinition, error) {
+	var definitions []DeployedDefinition
+	err := f.contextDB(ctx).
+		Where(&DeployedDefinition{DeploymentKey: deploymentKey}).
+		Order("resource_name ASC, definition_key ASC").
+		Find(&definitions).
+		Error
+
+	return definitions, err
+}
+
+// Gets a DMN resource by its decision requirements key.
+func (f *Fetcher) GetDmnResource(ctx context.Context, decisionRequirementsKey int64) (DmnResource, error) {
+	var dmnResource DmnResource
+	err := f.contextDB(ctx).
+		Where(&DmnResource{DecisionRequirementsKey: decisionRequirementsKey}).
+		First(&dmnResource).
+		Error
+
+	return dmnResource, err
+}
+
+// Gets decision requirements by their key.
+func (f *Fetcher) GetDecisionRequirements(ctx context.Context, decisionRequirementsKey int64) (DecisionRequirements, error) {
+	var decisionRequirements DecisionRequirements
+	err := f.contextDB(ctx).
+		Where(&DecisionRequirements{DecisionRequirementsKey: decisionRequirementsKey}).
+		First(&decisionRequirements).
+		Error
+
+	return decisionRequirements, err
+}
+
+// Gets all decision requirements.
+func (f *Fetcher) GetAllDecisionRequirements(ctx context.Context, pagination *Pagination) (Paginated[DecisionRequirements], error) {
+	return paginatedFetch[DecisionRequirements](ctx, f, pagination, func(db *gorm.DB, decisionRequirements *[]DecisionRequirements) *gorm.DB {
+		return db.Order("deployment_time DESC").Find(decisionRequirements)
+	})
+}
+
+// Gets a decision by its key.
+func (f *Fetcher) GetDecision(ctx context.Context, decisionKey int64) (Decision, error) {
+	var decision Decision
+	err := f.contextDB(ctx).
+		Where(&Decision{DecisionKey: decisionKey}).
+		First(&decision).
+		Error
+
+	return decision, err
+}
+
+// Gets all decisions.
+func (f *Fetcher) GetDecisions(ctx context.Context, pagination *Pagination) (Paginated[Decision], error) {
+	return paginatedFetch[Decision](ctx, f, pagination, func(db *gorm.DB, decisions *[]Decision) *gorm.DB {
+		return db.Order("deployment_time DESC").Find(decisions)
+	})
+}
+
+// Gets all decisions of decision requirements.
+func (f *Fetcher) GetDecisionsForRequirements(ctx context.Context, pagination *Pagination, decisionRequirementsKey int64) (Paginated[Decision], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Decision{DecisionRequirementsKey: decisionRequirementsKey})
+	}).GetDecisions(ctx, pagination)
+}
+
+// Gets all decision evaluations.
+func (f *Fetcher) GetDecisionEvaluations(ctx context.Context, pagination *Pagination) (Paginated[DecisionEvaluation], error) {
+	return paginatedFetch[DecisionEvaluation](ctx, f, pagination, func(db *gorm.DB, evaluations *[]DecisionEvaluation) *gorm.DB {
+		return db.Order("time DESC").Find(evaluations)
+	})
+}
+
+// Gets all decision evaluations for an instance.
+func (f *Fetcher) GetDecisionEvaluationsForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[DecisionEvaluation], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&DecisionEvaluation{ProcessInstanceKey: instanceKey})
+	}).GetDecisionEvaluations(ctx, pagination)
+}
+
+// Gets all evaluations of a decision.
+func (f *Fetcher) GetDecisionEvaluationsForDecision(ctx context.Context, pagination *Pagination, decisionKey int64) (Paginated[DecisionEvaluation], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&DecisionEvaluation{DecisionKey: decisionKey})
+	}).GetDecisionEvaluations(ctx, pagination)
+}
+
+// Gets the decisions evaluated as part of a decision evaluation in the order
+// they were evaluated in.
+func (f *Fetcher) GetEvaluatedDecisions(ctx context.Context, evaluationKey int64) ([]EvaluatedDecision, error) {
+	var evaluatedDecisions []EvaluatedDecision
+	err := f.contextDB(ctx).
+		Where(&EvaluatedDecision{EvaluationKey: evaluationKey}).
+		Order("evaluation_index ASC").
+		Find(&evaluatedDecisions).
+		Error
+
+	return evaluatedDecisions, err
+}
+
 // Gets all jobs.
 func (f *Fetcher) GetJobs(ctx context.Context, pagination *Pagination) (Paginated[Job], error) {
 	return paginatedFetch[Job](ctx, f, pagination, func(db *gorm.DB, jobs *[]Job) *gorm.DB {
@@ -169,6 +331,86 @@ func (f *Fetcher) GetJobsForInstance(ctx context.Context, pagination *Pagination
 	}).GetJobs(ctx, pagination)
 }
 
+// Gets the lifecycle of a job in the order it happened in.
+func (f *Fetcher) GetJobHistory(ctx context.Context, pagination *Pagination, jobKey int64) (Paginated[JobEvent], error) {
+	return paginatedFetch[JobEvent](ctx, f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&JobEvent{JobKey: jobKey})
+	}), pagination, func(db *gorm.DB, events *[]JobEvent) *gorm.DB {
+		return db.Order("position ASC").Find(events)
+	})
+}
+
+// Worker is a job worker as seen from its job batches and the jobs it
+// handled. Workers aren't stored as such, they're derived from the
+// 'job_batches' and 'job_events' tables.
+type Worker struct {
+	Name string
+	// When the worker last asked for jobs.
+	LastSeen time.Time
+	// Number of times the worker asked for jobs.
+	Polls int64
+	// Number of times the worker was handed jobs, and how many.
+	Activations   int64
+	ActivatedJobs int64
+	CompletedJobs int64
+	FailedJobs    int64
+}
+
+// Gets all workers, the most recently seen first.
+func (f *Fetcher) GetWorkers(ctx context.Context, pagination *Pagination) (Paginated[Worker], error) {
+	var paginated Paginated[Worker]
+
+	// Jobs are counted with subqueries so that the batches aren't
+	// multiplied by joining them with the job events
+	workers := f.contextDB(ctx).
+		Model(&JobBatch{}).
+		Select(`worker AS name,
+			(SELECT partition_id FROM job_batches AS latest WHERE latest.worker = job_batches.worker ORDER BY time DESC, position DESC, partition_id DESC LIMIT 1) AS last_partition_id,
+			(SELECT position FROM job_batches AS latest WHERE latest.worker = job_batches.worker ORDER BY time DESC, position DESC, partition_id DESC LIMIT 1) AS last_position,
+			SUM(CASE WHEN intent = 'ACTIVATE' THEN 1 ELSE 0 END) AS polls,
+			SUM(CASE WHEN intent = 'ACTIVATED' THEN 1 ELSE 0 END) AS activations,
+			SUM(CASE WHEN intent = 'ACTIVATED' THEN job_count ELSE 0 END) AS activated_jobs,
+			(SELECT COUNT(*) FROM job_events WHERE job_events.worker = job_batches.worker AND job_events.intent = 'COMPLETED') AS completed_jobs,
+			(SELECT COUNT(*) FROM job_events WHERE job_events.worker = job_batches.worker AND job_events.intent = 'FAILED') AS failed_jobs`).
+		Group("worker")
+
+	err := f.contextDB(ctx).
+		Table("(?) AS workers", workers).
+		Count(&paginated.TotalCount).
+		Error
+	if err != nil || paginated.TotalCount == 0 {
+		return paginated, err
+	}
+
+	// The time is read from the latest batch itself rather than
+	// aggregated, since not every database keeps the type of aggregated
+	// timestamps
+	err = f.paginated(pagination).contextDB(ctx).
+		Table("(?) AS workers", workers).
+		Select("workers.*, last_batch.time AS last_seen").
+		Joins("JOIN job_batches AS last_batch ON last_batch.partition_id = workers.last_partition_id AND last_batch.position = workers.last_position").
+		Order("last_seen DESC").
+		Order("name ASC").
+		Find(&paginated.Items).
+		Error
+
+	return paginated, err
+}
+
+// Gets the job types a worker has asked for in alphabetical order.
+func (f *Fetcher) GetWorkerJobTypes(ctx context.Context, worker string) ([]string, error) {
+	jobTypes := []string{}
+	err := f.contextDB(ctx).
+		Model(&JobBatch{}).
+		Where(&JobBatch{Worker: worker}).
+		Distinct("type").
+		Order("type ASC").
+		Pluck("type", &jobTypes).
+		Error
+
+	return jobTypes, err
+}
+
 // Gets all incidents.
 func (f *Fetcher) GetIncidents(ctx context.Context, pagination *Pagination) (Paginated[Incident], error) {
 	return paginatedFetch[Incident](ctx, f, pagination, func(db *gorm.DB, incidents *[]Incident) *gorm.DB {
@@ -183,7 +425,7 @@ func (f *Fetcher) GetIncidentsForInstance(ctx context.Context, pagination *Pagin
 	}).GetIncidents(ctx, pagination)
 }
 
-// Gets all variables for an instance.
+// Gets all variables for an instance, whatever their scope.
 func (f *Fetcher) GetVariablesForInstance(ctx context.Context, pagination *Pagination, filter *Filter, instanceKey int64) (Paginated[Variable], error) {
 	return paginatedFetch[Variable](ctx, f.scopes(
 		func(db *gorm.DB) *gorm.DB {
@@ -195,6 +437,62 @@ func (f *Fetcher) GetVariablesForInstance(ctx context.Context, pagination *Pagin
 	})
 }
 
+// Gets the variables in the scope of an element instance. The scope of global
+// variables is the process instance.
+func (f *Fetcher) GetVariablesForScope(ctx context.Context, pagination *Pagination, filter *Filter, scopeKey int64) (Paginated[Variable], error) {
+	return paginatedFetch[Variable](ctx, f.scopes(
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where(&Variable{ScopeKey: scopeKey})
+		},
+		filter.FilterFunctor("name"),
+	), pagination, func(db *gorm.DB, variables *[]Variable) *gorm.DB {
+		return db.Order("time DESC").Find(variables)
+	})
+}
+
+// Gets every value a variable has had, the latest first.
+func (f *Fetcher) GetVariableHistory(ctx context.Context, pagination *Pagination, scopeKey int64, name string) (Paginated[VariableChange], error) {
+	return paginatedFetch[VariableChange](ctx, f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&VariableChange{ScopeKey: scopeKey, Name: name})
+	}), pagination, func(db *gorm.DB, changes *[]VariableChange) *gorm.DB {
+		return db.Order("position DESC").Find(changes)
+	})
+}
+
+// Gets every variable change of an instance in the order they happened in,
+// optionally filtered by variable name.
+func (f *Fetcher) GetVariableChangesForInstance(ctx context.Context, pagination *Pagination, filter *Filter, instanceKey int64) (Paginated[VariableChange], error) {
+	return paginatedFetch[VariableChange](ctx, f.scopes(
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where(&VariableChange{ProcessInstanceKey: instanceKey})
+		},
+		filter.FilterFunctor("name"),
+	), pagination, func(db *gorm.DB, changes *[]VariableChange) *gorm.DB {
+		return db.Order("position ASC").Find(changes)
+	})
+}
+
+// Gets an element instance by its key.
+func (f *Fetcher) GetElementInstance(ctx context.Context, key int64) (ElementInstance, error) {
+	var elementInstance ElementInstance
+	err := f.contextDB(ctx).
+		Where(&ElementInstance{Key: key}).
+		First(&elementInstance).
+		Error
+
+	return elementInstance, err
+}
+
+// Gets all element instances for an instance in the order they were
+// activated in.
+func (f *Fetcher) GetElementInstancesForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[ElementInstance], error) {
+	return paginatedFetch[ElementInstance](ctx, f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&ElementInstance{ProcessInstanceKey: instanceKey})
+	}), pagination, func(db *gorm.DB, elementInstances *[]ElementInstance) *gorm.DB {
+		return db.Order("start_time ASC").Order("key ASC").Find(elementInstances)
+	})
+}
+
 // Gets all audit logs for an instance.
 func (f *Fetcher) GetAuditLogsForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[AuditLog], error) {
 	return paginatedFetch[AuditLog](ctx, f.scopes(func(db *gorm.DB) *gorm.DB {
@@ -204,6 +502,180 @@ func (f *Fetcher) GetAuditLogsForInstance(ctx context.Context, pagination *Pagin
 	})
 }
 
+// Gets all rejected commands, optionally filtered by rejection type.
+func (f *Fetcher) GetRejections(ctx context.Context, pagination *Pagination, filter *Filter) (Paginated[Rejection], error) {
+	return paginatedFetch[Rejection](ctx, f.scopes(
+		filter.FilterFunctor("rejection_type"),
+	), pagination, func(db *gorm.DB, rejections *[]Rejection) *gorm.DB {
+		return db.Order("time DESC").Find(rejections)
+	})
+}
+
+// Gets all rejected commands for an instance, optionally filtered by
+// rejection type.
+func (f *Fetcher) GetRejectionsForInstance(ctx context.Context, pagination *Pagination, filter *Filter, instanceKey int64) (Paginated[Rejection], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Rejection{ProcessInstanceKey: instanceKey})
+	}).GetRejections(ctx, pagination, filter)
+}
+
+// Gets a message by its key.
+func (f *Fetcher) GetMessage(ctx context.Context, messageKey int64) (Message, error) {
+	var message Message
+	err := f.contextDB(ctx).
+		Where(&Message{Key: messageKey}).
+		First(&message).
+		Error
+
+	return message, err
+}
+
+// Gets all messages, optionally filtered by name.
+func (f *Fetcher) GetMessages(ctx context.Context, pagination *Pagination, filter *Filter) (Paginated[Message], error) {
+	return paginatedFetch[Message](ctx, f.scopes(
+		filter.FilterFunctor("name"),
+	), pagination, func(db *gorm.DB, messages *[]Message) *gorm.DB {
+		return db.Order("publish_time DESC").Find(messages)
+	})
+}
+
+// Gets all messages correlated to the subscriptions of an instance.
+func (f *Fetcher) GetMessagesForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[Message], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		correlated := db.Session(&gorm.Session{NewDB: true}).
+			Model(&MessageSubscription{}).
+			Select("message_key").
+			Where(&MessageSubscription{ProcessInstanceKey: instanceKey})
+		return db.Where("key IN (?)", correlated)
+	}).GetMessages(ctx, pagination, nil)
+}
+
+// Gets all message subscriptions.
+func (f *Fetcher) GetMessageSubscriptions(ctx context.Context, pagination *Pagination) (Paginated[MessageSubscription], error) {
+	return paginatedFetch[MessageSubscription](ctx, f, pagination, func(db *gorm.DB, subscriptions *[]MessageSubscription) *gorm.DB {
+		return db.Order("time DESC").Find(subscriptions)
+	})
+}
+
+// Gets all message subscriptions for an instance.
+func (f *Fetcher) GetMessageSubscriptionsForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[MessageSubscription], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&MessageSubscription{ProcessInstanceKey: instanceKey})
+	}).GetMessageSubscriptions(ctx, pagination)
+}
+
+// Gets all message subscriptions a message has been correlated to.
+func (f *Fetcher) GetMessageSubscriptionsForMessage(ctx context.Context, pagination *Pagination, messageKey int64) (Paginated[MessageSubscription], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&MessageSubscription{MessageKey: messageKey})
+	}).GetMessageSubscriptions(ctx, pagination)
+}
+
+// Gets all timers, the earliest due first.
+func (f *Fetcher) GetTimers(ctx context.Context, pagination *Pagination) (Paginated[Timer], error) {
+	return paginatedFetch[Timer](ctx, f, pagination, func(db *gorm.DB, timers *[]Timer) *gorm.DB {
+		return db.Order("due_date ASC").Find(timers)
+	})
+}
+
+// Gets all timers for an instance, the earliest due first.
+func (f *Fetcher) GetTimersForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[Timer], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Timer{ProcessInstanceKey: instanceKey})
+	}).GetTimers(ctx, pagination)
+}
+
+// Gets all timers for a process, including the timers of its instances, the
+// earliest due first.
+func (f *Fetcher) GetTimersForProcess(ctx context.Context, pagination *Pagination, processDefKey int64) (Paginated[Timer], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Timer{ProcessDefinitionKey: processDefKey})
+	}).GetTimers(ctx, pagination)
+}
+
+// Gets a user task by its key.
+func (f *Fetcher) GetUserTask(ctx context.Context, key int64) (UserTask, error) {
+	var userTask UserTask
+	err := f.contextDB(ctx).
+		Where(&UserTask{Key: key}).
+		First(&userTask).
+		Error
+
+	return userTask, err
+}
+
+// Gets all user tasks, optionally filtered by their assignee.
+func (f *Fetcher) GetUserTasks(ctx context.Context, pagination *Pagination, filter *Filter) (Paginated[UserTask], error) {
+	return paginatedFetch[UserTask](ctx, f.scopes(
+		filter.FilterFunctor("assignee"),
+	), pagination, func(db *gorm.DB, userTasks *[]UserTask) *gorm.DB {
+		return db.Order("creation_time DESC").Find(userTasks)
+	})
+}
+
+// Gets all user tasks for an instance.
+func (f *Fetcher) GetUserTasksForInstance(ctx context.Context, pagination *Pagination, filter *Filter, instanceKey int64) (Paginated[UserTask], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&UserTask{ProcessInstanceKey: instanceKey})
+	}).GetUserTasks(ctx, pagination, filter)
+}
+
+// Gets all signals.
+func (f *Fetcher) GetSignals(ctx context.Context, pagination *Pagination) (Paginated[Signal], error) {
+	return paginatedFetch[Signal](ctx, f, pagination, func(db *gorm.DB, signals *[]Signal) *gorm.DB {
+		return db.Order("time DESC").Find(signals)
+	})
+}
+
+// Gets all signal subscriptions.
+func (f *Fetcher) GetSignalSubscriptions(ctx context.Context, pagination *Pagination) (Paginated[SignalSubscription], error) {
+	return paginatedFetch[SignalSubscription](ctx, f, pagination, func(db *gorm.DB, subscriptions *[]SignalSubscription) *gorm.DB {
+		return db.Order("time DESC").Find(subscriptions)
+	})
+}
+
+// Gets all subscriptions to signals with a name.
+func (f *Fetcher) GetSignalSubscriptionsForName(ctx context.Context, pagination *Pagination, signalName string) (Paginated[SignalSubscription], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&SignalSubscription{SignalName: signalName})
+	}).GetSignalSubscriptions(ctx, pagination)
+}
+
+// Gets all signal subscriptions of a process.
+func (f *Fetcher) GetSignalSubscriptionsForProcess(ctx context.Context, pagination *Pagination, processDefKey int64) (Paginated[SignalSubscription], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&SignalSubscription{ProcessDefinitionKey: processDefKey})
+	}).GetSignalSubscriptions(ctx, pagination)
+}
+
+// Gets all errors the broker ran into.
+func (f *Fetcher) GetBrokerErrors(ctx context.Context, pagination *Pagination) (Paginated[BrokerError], error) {
+	return paginatedFetch[BrokerError](ctx, f, pagination, func(db *gorm.DB, brokerErrors *[]BrokerError) *gorm.DB {
+		return db.Order("time DESC").Find(brokerErrors)
+	})
+}
+
+// Gets all errors the broker ran into for an instance.
+func (f *Fetcher) GetBrokerErrorsForInstance(ctx context.Context, pagination *Pagination, instanceKey int64) (Paginated[BrokerError], error) {
+	return f.scopes(func(db *gorm.DB) *gorm.DB {
+		return db.Where(&BrokerError{ProcessInstanceKey: instanceKey})
+	}).GetBrokerErrors(ctx, pagination)
+}
+
+// Gets all dead letters.
+func (f *Fetcher) GetDeadLetters(ctx context.Context, pagination *Pagination) (Paginated[DeadLetter], error) {
+	return paginatedFetch[DeadLetter](ctx, f, pagination, func(db *gorm.DB, deadLetters *[]DeadLetter) *gorm.DB {
+		return db.Order("time DESC").Find(deadLetters)
+	})
+}
+
+// Gets all pending records.
+func (f *Fetcher) GetPendingRecords(ctx context.Context, pagination *Pagination) (Paginated[PendingRecord], error) {
+	return paginatedFetch[PendingRecord](ctx, f, pagination, func(db *gorm.DB, pendingRecords *[]PendingRecord) *gorm.DB {
+		return db.Order("time DESC").Find(pendingRecords)
+	})
+}
+
 // Fetches paginated results from the database.
 //
 // `fetcher` should be a Fetcher with the filtering scope already applied to
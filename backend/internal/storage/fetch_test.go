@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -297,6 +298,71 @@ func TestInstancesForProcessQuery(t *testing.T) {
 	}
 }
 
+func TestInstanceHierarchyQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// 1 calls 2 and 3, 3 calls 4, and 6 was called by an instance that
+	// hasn't been stored
+	instances := []Instance{
+		{ProcessInstanceKey: 1, ParentProcessInstanceKey: -1, ParentElementInstanceKey: -1},
+		{ProcessInstanceKey: 2, ParentProcessInstanceKey: 1, ParentElementInstanceKey: 11},
+		{ProcessInstanceKey: 3, ParentProcessInstanceKey: 1, ParentElementInstanceKey: 12},
+		{ProcessInstanceKey: 4, ParentProcessInstanceKey: 3, ParentElementInstanceKey: 31},
+		{ProcessInstanceKey: 6, ParentProcessInstanceKey: 5, ParentElementInstanceKey: 51},
+	}
+	err := db.Create(instances).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	keys := func(result Paginated[Instance]) []int64 {
+		keys := []int64{}
+		for _, instance := range result.Items {
+			keys = append(keys, instance.ProcessInstanceKey)
+		}
+		return keys
+	}
+
+	t.Run("children", func(t *testing.T) {
+		children, err := fetcher.GetChildInstances(ctx, nil, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), children.TotalCount)
+		assert.ElementsMatch(t, []int64{2, 3}, keys(children))
+
+		children, err = fetcher.GetChildInstances(ctx, nil, 4)
+		assert.NoError(t, err)
+		assert.Empty(t, children.Items)
+	})
+
+	tests := []struct {
+		name        string
+		instanceKey int64
+		rootKey     int64
+	}{
+		{"root of root", 1, 1},
+		{"root of child", 2, 1},
+		{"root of grandchild", 4, 1},
+		{"root with missing parent", 6, 6},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := fetcher.GetRootInstance(ctx, test.instanceKey)
+			assert.NoError(t, err)
+			assert.Equal(t, test.rootKey, root.ProcessInstanceKey)
+		})
+	}
+
+	t.Run("root of missing instance", func(t *testing.T) {
+		_, err := fetcher.GetRootInstance(ctx, 100)
+		assert.True(t, IsNotFound(err))
+	})
+}
+
 func TestProcessesQuery(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -434,6 +500,121 @@ func TestJobsForInstanceQuery(t *testing.T) {
 	}
 }
 
+func TestJobHistoryQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// Job 1 fails once and is completed on the second try
+	events := []JobEvent{
+		{Position: 4, JobKey: 1, Intent: "FAILED", Retries: 1},
+		{Position: 1, JobKey: 1, Intent: "CREATED", Retries: 2},
+		{Position: 2, JobKey: 2, Intent: "CREATED", Retries: 3},
+		{Position: 3, JobKey: 1, Intent: "ACTIVATED", Retries: 2},
+		{Position: 5, JobKey: 1, Intent: "ACTIVATED", Retries: 1},
+		{Position: 6, JobKey: 1, Intent: "COMPLETED", Retries: 1},
+	}
+	err := db.Create(events).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	intents := func(result Paginated[JobEvent]) []string {
+		intents := []string{}
+		for _, event := range result.Items {
+			intents = append(intents, event.Intent)
+		}
+		return intents
+	}
+
+	t.Run("history of job", func(t *testing.T) {
+		result, err := fetcher.GetJobHistory(context.Background(), nil, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.TotalCount)
+		assert.Equal(t, []string{"CREATED", "ACTIVATED", "FAILED", "ACTIVATED", "COMPLETED"}, intents(result))
+	})
+
+	t.Run("paginated history of job", func(t *testing.T) {
+		result, err := fetcher.GetJobHistory(context.Background(), &Pagination{Offset: 3, Limit: 5}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.TotalCount)
+		assert.Equal(t, []string{"ACTIVATED", "COMPLETED"}, intents(result))
+	})
+}
+
+func TestWorkersQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// Worker a polls twice and gets three jobs, of which it completes two
+	// and fails one. Worker b polls once in another partition, without
+	// getting any jobs
+	batches := []JobBatch{
+		{PartitionID: 1, Position: 1, Worker: "a", Type: "x", Intent: "ACTIVATE", Time: time.Unix(1, 0)},
+		{PartitionID: 1, Position: 2, Worker: "a", Type: "x", Intent: "ACTIVATED", JobCount: 2, Time: time.Unix(2, 0)},
+		{PartitionID: 1, Position: 3, Worker: "a", Type: "y", Intent: "ACTIVATE", Time: time.Unix(3, 0)},
+		{PartitionID: 1, Position: 4, Worker: "a", Type: "y", Intent: "ACTIVATED", JobCount: 1, Time: time.Unix(4, 0)},
+		{PartitionID: 2, Position: 4, Worker: "b", Type: "x", Intent: "ACTIVATE", Time: time.Unix(5, 0)},
+	}
+	err := db.Create(batches).Error
+	assert.NoError(t, err)
+
+	events := []JobEvent{
+		{Position: 10, JobKey: 1, Worker: "a", Intent: "ACTIVATED"},
+		{Position: 11, JobKey: 1, Worker: "a", Intent: "COMPLETED"},
+		{Position: 12, JobKey: 2, Worker: "a", Intent: "COMPLETED"},
+		{Position: 13, JobKey: 3, Worker: "a", Intent: "FAILED"},
+		{Position: 14, JobKey: 4, Worker: "c", Intent: "COMPLETED"},
+	}
+	err = db.Create(events).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	t.Run("all workers", func(t *testing.T) {
+		workers, err := fetcher.GetWorkers(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), workers.TotalCount)
+		assert.Len(t, workers.Items, 2)
+
+		b := workers.Items[0]
+		assert.Equal(t, "b", b.Name)
+		assert.Equal(t, time.Unix(5, 0).UTC(), b.LastSeen.UTC())
+		assert.Equal(t, int64(1), b.Polls)
+		assert.Equal(t, int64(0), b.Activations)
+		assert.Equal(t, int64(0), b.CompletedJobs)
+
+		a := workers.Items[1]
+		assert.Equal(t, "a", a.Name)
+		assert.Equal(t, time.Unix(4, 0).UTC(), a.LastSeen.UTC())
+		assert.Equal(t, int64(2), a.Polls)
+		assert.Equal(t, int64(2), a.Activations)
+		assert.Equal(t, int64(3), a.ActivatedJobs)
+		assert.Equal(t, int64(2), a.CompletedJobs)
+		assert.Equal(t, int64(1), a.FailedJobs)
+	})
+
+	t.Run("paginated workers", func(t *testing.T) {
+		workers, err := fetcher.GetWorkers(ctx, &Pagination{Offset: 1, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), workers.TotalCount)
+		assert.Len(t, workers.Items, 1)
+		assert.Equal(t, "a", workers.Items[0].Name)
+	})
+
+	t.Run("job types", func(t *testing.T) {
+		jobTypes, err := fetcher.GetWorkerJobTypes(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"x", "y"}, jobTypes)
+	})
+}
+
 func TestIncidentsQuery(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -569,6 +750,117 @@ func TestVariablesForInstanceQuery(t *testing.T) {
 	}
 }
 
+func TestVariablesForScopeQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// Instance 10 has a global variable and a local one of the same name
+	variables := []Variable{
+		{ScopeKey: 10, Name: "name", ProcessInstanceKey: 10, Value: "global", Time: time.Unix(1, 0)},
+		{ScopeKey: 11, Name: "name", ProcessInstanceKey: 10, Value: "local", Time: time.Unix(2, 0)},
+		{ScopeKey: 11, Name: "other", ProcessInstanceKey: 10, Value: "local", Time: time.Unix(3, 0)},
+	}
+	err := db.Create(variables).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	scopedNames := func(result Paginated[Variable]) [][2]any {
+		scopedNames := [][2]any{}
+		for _, variable := range result.Items {
+			scopedNames = append(scopedNames, [2]any{variable.ScopeKey, variable.Name})
+		}
+		return scopedNames
+	}
+
+	t.Run("all scopes", func(t *testing.T) {
+		result, err := fetcher.GetVariablesForInstance(ctx, nil, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+	})
+
+	t.Run("global scope", func(t *testing.T) {
+		result, err := fetcher.GetVariablesForScope(ctx, nil, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, [][2]any{{int64(10), "name"}}, scopedNames(result))
+	})
+
+	t.Run("local scope", func(t *testing.T) {
+		result, err := fetcher.GetVariablesForScope(ctx, nil, nil, 11)
+		assert.NoError(t, err)
+		assert.Equal(t, [][2]any{{int64(11), "other"}, {int64(11), "name"}}, scopedNames(result))
+	})
+
+	t.Run("filtered local scope", func(t *testing.T) {
+		filter := &Filter{Input: "name", Type: FilterTypeIs}
+		result, err := fetcher.GetVariablesForScope(ctx, nil, filter, 11)
+		assert.NoError(t, err)
+		assert.Equal(t, [][2]any{{int64(11), "name"}}, scopedNames(result))
+	})
+}
+
+func TestVariableHistoryQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	// A global and a local variable of the same name in instance 10, and a
+	// variable of instance 20
+	changes := []VariableChange{
+		{Position: 1, ProcessInstanceKey: 10, ScopeKey: 10, Name: "a", Intent: "CREATED", Value: "1"},
+		{Position: 2, ProcessInstanceKey: 10, ScopeKey: 11, Name: "a", Intent: "CREATED", Value: "2"},
+		{Position: 3, ProcessInstanceKey: 10, ScopeKey: 10, Name: "a", Intent: "UPDATED", Value: "3"},
+		{Position: 4, ProcessInstanceKey: 10, ScopeKey: 10, Name: "b", Intent: "CREATED", Value: "4"},
+		{Position: 5, ProcessInstanceKey: 20, ScopeKey: 20, Name: "a", Intent: "CREATED", Value: "5"},
+	}
+	err := db.Create(changes).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	positions := func(result Paginated[VariableChange]) []int64 {
+		positions := []int64{}
+		for _, change := range result.Items {
+			positions = append(positions, change.Position)
+		}
+		return positions
+	}
+
+	t.Run("history of global variable", func(t *testing.T) {
+		result, err := fetcher.GetVariableHistory(ctx, nil, 10, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{3, 1}, positions(result))
+	})
+
+	t.Run("history of local variable", func(t *testing.T) {
+		result, err := fetcher.GetVariableHistory(ctx, nil, 11, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2}, positions(result))
+	})
+
+	t.Run("changes for instance", func(t *testing.T) {
+		result, err := fetcher.GetVariableChangesForInstance(ctx, nil, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), result.TotalCount)
+		assert.Equal(t, []int64{1, 2, 3, 4}, positions(result))
+	})
+
+	t.Run("filtered changes for instance", func(t *testing.T) {
+		filter := &Filter{Input: "b", Type: FilterTypeIs}
+		result, err := fetcher.GetVariableChangesForInstance(ctx, nil, filter, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{4}, positions(result))
+	})
+}
+
 func TestFilterVariableName(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -664,6 +956,36 @@ func TestFilterVariableName(t *testing.T) {
 	}
 }
 
+func TestElementInstancesForInstanceQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	elementInstances := []ElementInstance{
+		{Key: 10, ProcessInstanceKey: 10, ElementID: "process", StartTime: time.Unix(1, 0)},
+		{Key: 13, ProcessInstanceKey: 10, ElementID: "task", StartTime: time.Unix(3, 0)},
+		{Key: 11, ProcessInstanceKey: 10, ElementID: "start", StartTime: time.Unix(2, 0)},
+		{Key: 12, ProcessInstanceKey: 10, ElementID: "gateway", StartTime: time.Unix(2, 0)},
+		{Key: 20, ProcessInstanceKey: 20, ElementID: "process", StartTime: time.Unix(1, 0)},
+	}
+	err := db.Create(elementInstances).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	result, err := fetcher.GetElementInstancesForInstance(context.Background(), nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.TotalCount)
+
+	elementIDs := []string{}
+	for _, elementInstance := range result.Items {
+		elementIDs = append(elementIDs, elementInstance.ElementID)
+	}
+	assert.Equal(t, []string{"process", "start", "gateway", "task"}, elementIDs)
+}
+
 func TestAuditLogsForInstanceQuery(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -713,6 +1035,547 @@ func TestAuditLogsForInstanceQuery(t *testing.T) {
 	}
 }
 
+func TestDeadLettersQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	expectedDeadLetters := []DeadLetter{
+		{
+			ID:        1,
+			Topic:     "zeebe-job",
+			Partition: 1,
+			Offset:    10,
+			Payload:   []byte(`{}`),
+			Error:     "error-1",
+			Attempts:  1,
+			Time:      time.Unix(1701235496, 0),
+		},
+		{
+			ID:        2,
+			Topic:     "zeebe-variable",
+			Partition: 2,
+			Offset:    20,
+			Payload:   []byte(`{}`),
+			Error:     "error-2",
+			Attempts:  2,
+			Time:      time.Unix(1701235495, 0),
+		},
+	}
+	err := db.Create(expectedDeadLetters).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	deadLetters, err := fetcher.GetDeadLetters(context.Background(), nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(2), deadLetters.TotalCount)
+	assert.Len(t, deadLetters.Items, 2)
+	for i := range deadLetters.Items {
+		assert.Equal(t, expectedDeadLetters[i].ID, deadLetters.Items[i].ID)
+		assert.Equal(t, expectedDeadLetters[i].Error, deadLetters.Items[i].Error)
+	}
+}
+
+func TestPendingRecordsQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	expectedPendingRecords := []PendingRecord{
+		{
+			ID:         1,
+			Topic:      "zeebe-job",
+			Partition:  1,
+			Offset:     10,
+			Payload:    []byte(`{}`),
+			MissingKey: 100,
+			Time:       time.Unix(1701235496, 0),
+		},
+		{
+			ID:         2,
+			Topic:      "zeebe-variable",
+			Partition:  2,
+			Offset:     20,
+			Payload:    []byte(`{}`),
+			MissingKey: 200,
+			Time:       time.Unix(1701235495, 0),
+		},
+	}
+	err := db.Create(expectedPendingRecords).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	pendingRecords, err := fetcher.GetPendingRecords(context.Background(), nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(2), pendingRecords.TotalCount)
+	assert.Len(t, pendingRecords.Items, 2)
+	for i := range pendingRecords.Items {
+		assert.Equal(t, expectedPendingRecords[i].ID, pendingRecords.Items[i].ID)
+		assert.Equal(t, expectedPendingRecords[i].MissingKey, pendingRecords.Items[i].MissingKey)
+	}
+}
+
+func TestRejectionsQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	rejections := []Rejection{
+		{
+			Position:           3,
+			ProcessInstanceKey: 10,
+			RejectionType:      "NOT_FOUND",
+			Time:               time.Unix(3, 0),
+		},
+		{
+			Position:           2,
+			ProcessInstanceKey: 10,
+			RejectionType:      "INVALID_STATE",
+			Time:               time.Unix(2, 0),
+		},
+		{
+			Position:           1,
+			ProcessInstanceKey: 20,
+			RejectionType:      "NOT_FOUND",
+			Time:               time.Unix(1, 0),
+		},
+	}
+	err := db.Create(rejections).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	tests := []struct {
+		name        string
+		instanceKey int64
+		filter      *Filter
+		positions   []int64
+	}{
+		{
+			name:      "all rejections",
+			positions: []int64{3, 2, 1},
+		},
+		{
+			name:      "rejection type is",
+			filter:    &Filter{Input: "NOT_FOUND", Type: FilterTypeIs},
+			positions: []int64{3, 1},
+		},
+		{
+			name:      "rejection type is not",
+			filter:    &Filter{Input: "NOT_FOUND", Type: FilterTypeIsNot},
+			positions: []int64{2},
+		},
+		{
+			name:        "rejections for instance",
+			instanceKey: 10,
+			positions:   []int64{3, 2},
+		},
+		{
+			name:        "filtered rejections for instance",
+			instanceKey: 10,
+			filter:      &Filter{Input: "NOT_FOUND", Type: FilterTypeIs},
+			positions:   []int64{3},
+		},
+		{
+			name:        "non-existent instance",
+			instanceKey: 123,
+			positions:   []int64{},
+		},
+	}
+
+	for _, test := range tests {
+		// Capture range variable.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var result Paginated[Rejection]
+			var err error
+			if test.instanceKey == 0 {
+				result, err = fetcher.GetRejections(context.Background(), nil, test.filter)
+			} else {
+				result, err = fetcher.GetRejectionsForInstance(context.Background(), nil, test.filter, test.instanceKey)
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, int64(len(test.positions)), result.TotalCount)
+			positions := []int64{}
+			for _, rejection := range result.Items {
+				positions = append(positions, rejection.Position)
+			}
+			assert.Equal(t, test.positions, positions)
+		})
+	}
+}
+
+func TestMessagesQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	messages := []Message{
+		{Key: 1, Name: "payment-received", PublishTime: time.Unix(1, 0)},
+		{Key: 2, Name: "order-cancelled", PublishTime: time.Unix(2, 0)},
+		{Key: 3, Name: "payment-refunded", PublishTime: time.Unix(3, 0)},
+	}
+	err := db.Create(messages).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		keys   []int64
+	}{
+		{
+			name: "all messages",
+			keys: []int64{3, 2, 1},
+		},
+		{
+			name:   "name is",
+			filter: &Filter{Input: "order-cancelled", Type: FilterTypeIs},
+			keys:   []int64{2},
+		},
+		{
+			name:   "name contains",
+			filter: &Filter{Input: "payment", Type: FilterTypeContains},
+			keys:   []int64{3, 1},
+		},
+	}
+
+	for _, test := range tests {
+		// Capture range variable.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := fetcher.GetMessages(context.Background(), nil, test.filter)
+			assert.NoError(t, err)
+
+			assert.Equal(t, int64(len(test.keys)), result.TotalCount)
+			keys := []int64{}
+			for _, message := range result.Items {
+				keys = append(keys, message.Key)
+			}
+			assert.Equal(t, test.keys, keys)
+		})
+	}
+}
+
+func TestMessagesForInstanceQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	messages := []Message{
+		{Key: 1, Name: "payment-received", PublishTime: time.Unix(1, 0)},
+		{Key: 2, Name: "order-cancelled", PublishTime: time.Unix(2, 0)},
+		{Key: 3, Name: "payment-refunded", PublishTime: time.Unix(3, 0)},
+	}
+	err := db.Create(messages).Error
+	assert.NoError(t, err)
+
+	subscriptions := []MessageSubscription{
+		{ElementInstanceKey: 11, MessageName: "payment-received", ProcessInstanceKey: 10, MessageKey: 1},
+		{ElementInstanceKey: 12, MessageName: "payment-refunded", ProcessInstanceKey: 10, MessageKey: 3},
+		// Not correlated yet
+		{ElementInstanceKey: 13, MessageName: "order-cancelled", ProcessInstanceKey: 10},
+		{ElementInstanceKey: 21, MessageName: "order-cancelled", ProcessInstanceKey: 20, MessageKey: 2},
+	}
+	err = db.Create(subscriptions).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+
+	result, err := fetcher.GetMessagesForInstance(context.Background(), nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.TotalCount)
+	keys := []int64{}
+	for _, message := range result.Items {
+		keys = append(keys, message.Key)
+	}
+	assert.Equal(t, []int64{3, 1}, keys)
+
+	result, err = fetcher.GetMessagesForInstance(context.Background(), nil, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.TotalCount)
+	assert.Empty(t, result.Items)
+}
+
+func TestMessageSubscriptionsQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	subscriptions := []MessageSubscription{
+		{ElementInstanceKey: 1, MessageName: "a", ProcessInstanceKey: 10, MessageKey: 100, Time: time.Unix(1, 0)},
+		{ElementInstanceKey: 2, MessageName: "a", ProcessInstanceKey: 10, Time: time.Unix(2, 0)},
+		{ElementInstanceKey: 3, MessageName: "b", ProcessInstanceKey: 20, MessageKey: 100, Time: time.Unix(3, 0)},
+	}
+	err := db.Create(subscriptions).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	elementInstanceKeys := func(result Paginated[MessageSubscription]) []int64 {
+		keys := []int64{}
+		for _, subscription := range result.Items {
+			keys = append(keys, subscription.ElementInstanceKey)
+		}
+		return keys
+	}
+
+	t.Run("all subscriptions", func(t *testing.T) {
+		result, err := fetcher.GetMessageSubscriptions(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+		assert.Equal(t, []int64{3, 2, 1}, elementInstanceKeys(result))
+	})
+
+	t.Run("subscriptions for instance", func(t *testing.T) {
+		result, err := fetcher.GetMessageSubscriptionsForInstance(ctx, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{2, 1}, elementInstanceKeys(result))
+	})
+
+	t.Run("subscriptions for message", func(t *testing.T) {
+		result, err := fetcher.GetMessageSubscriptionsForMessage(ctx, nil, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{3, 1}, elementInstanceKeys(result))
+	})
+}
+
+func TestTimersQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	timers := []Timer{
+		{Key: 1, ProcessInstanceKey: 10, ProcessDefinitionKey: 100, DueDate: time.Unix(30, 0)},
+		{Key: 2, ProcessInstanceKey: 10, ProcessDefinitionKey: 100, DueDate: time.Unix(10, 0)},
+		{Key: 3, ProcessInstanceKey: -1, ProcessDefinitionKey: 100, DueDate: time.Unix(20, 0)},
+		{Key: 4, ProcessInstanceKey: 20, ProcessDefinitionKey: 200, DueDate: time.Unix(40, 0)},
+	}
+	err := db.Create(timers).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	timerKeys := func(result Paginated[Timer]) []int64 {
+		keys := []int64{}
+		for _, timer := range result.Items {
+			keys = append(keys, timer.Key)
+		}
+		return keys
+	}
+
+	t.Run("all timers", func(t *testing.T) {
+		result, err := fetcher.GetTimers(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), result.TotalCount)
+		assert.Equal(t, []int64{2, 3, 1, 4}, timerKeys(result))
+	})
+
+	t.Run("timers for instance", func(t *testing.T) {
+		result, err := fetcher.GetTimersForInstance(ctx, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{2, 1}, timerKeys(result))
+	})
+
+	t.Run("timers for process", func(t *testing.T) {
+		result, err := fetcher.GetTimersForProcess(ctx, nil, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+		assert.Equal(t, []int64{2, 3, 1}, timerKeys(result))
+	})
+}
+
+func TestBrokerErrorsQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	brokerErrors := []BrokerError{
+		{Position: 1, ProcessInstanceKey: 10, Time: time.Unix(1, 0)},
+		{Position: 2, ProcessInstanceKey: 20, Time: time.Unix(2, 0)},
+		{Position: 3, ProcessInstanceKey: 10, Time: time.Unix(3, 0)},
+	}
+	err := db.Create(brokerErrors).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	positions := func(result Paginated[BrokerError]) []int64 {
+		positions := []int64{}
+		for _, brokerError := range result.Items {
+			positions = append(positions, brokerError.Position)
+		}
+		return positions
+	}
+
+	t.Run("all errors", func(t *testing.T) {
+		result, err := fetcher.GetBrokerErrors(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+		assert.Equal(t, []int64{3, 2, 1}, positions(result))
+	})
+
+	t.Run("errors for instance", func(t *testing.T) {
+		result, err := fetcher.GetBrokerErrorsForInstance(ctx, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{3, 1}, positions(result))
+	})
+
+	t.Run("paginated errors for instance", func(t *testing.T) {
+		result, err := fetcher.GetBrokerErrorsForInstance(ctx, &Pagination{Offset: 1, Limit: 1}, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{1}, positions(result))
+	})
+}
+
+func TestDecisionsQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	decisions := []Decision{
+		{DecisionKey: 1, DecisionRequirementsKey: 10, DeploymentTime: time.Unix(1, 0)},
+		{DecisionKey: 2, DecisionRequirementsKey: 10, DeploymentTime: time.Unix(1, 0)},
+		{DecisionKey: 3, DecisionRequirementsKey: 20, DeploymentTime: time.Unix(2, 0)},
+	}
+	err := db.Create(decisions).Error
+	assert.NoError(t, err)
+
+	evaluations := []DecisionEvaluation{
+		{Key: 100, DecisionKey: 1, ProcessInstanceKey: 50, Time: time.Unix(3, 0)},
+		{Key: 101, DecisionKey: 3, ProcessInstanceKey: 50, Time: time.Unix(4, 0)},
+		{Key: 102, DecisionKey: 1, ProcessInstanceKey: 60, Time: time.Unix(5, 0)},
+	}
+	err = db.Create(evaluations).Error
+	assert.NoError(t, err)
+
+	evaluatedDecisions := []EvaluatedDecision{
+		{EvaluationKey: 101, Index: 1, DecisionKey: 3},
+		{EvaluationKey: 101, Index: 0, DecisionKey: 2},
+	}
+	err = db.Create(evaluatedDecisions).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	evaluationKeys := func(result Paginated[DecisionEvaluation]) []int64 {
+		keys := []int64{}
+		for _, evaluation := range result.Items {
+			keys = append(keys, evaluation.Key)
+		}
+		return keys
+	}
+
+	t.Run("decisions for requirements", func(t *testing.T) {
+		result, err := fetcher.GetDecisionsForRequirements(ctx, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+	})
+
+	t.Run("evaluations for instance", func(t *testing.T) {
+		result, err := fetcher.GetDecisionEvaluationsForInstance(ctx, nil, 50)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{101, 100}, evaluationKeys(result))
+	})
+
+	t.Run("evaluations for decision", func(t *testing.T) {
+		result, err := fetcher.GetDecisionEvaluationsForDecision(ctx, nil, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{102, 100}, evaluationKeys(result))
+	})
+
+	t.Run("evaluated decisions in order", func(t *testing.T) {
+		result, err := fetcher.GetEvaluatedDecisions(ctx, 101)
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(2), result[0].DecisionKey)
+		assert.Equal(t, int64(3), result[1].DecisionKey)
+	})
+}
+
+func TestUserTasksQuery(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+
+	userTasks := []UserTask{
+		{Key: 1, ProcessInstanceKey: 10, Assignee: "alice", CreationTime: time.Unix(1, 0), Time: time.Unix(1, 0)},
+		{Key: 2, ProcessInstanceKey: 10, Assignee: "bob", CreationTime: time.Unix(2, 0), Time: time.Unix(2, 0)},
+		{Key: 3, ProcessInstanceKey: 20, Assignee: "alice", CreationTime: time.Unix(3, 0), Time: time.Unix(3, 0)},
+	}
+	err := db.Create(userTasks).Error
+	assert.NoError(t, err)
+
+	fetcher := NewFetcher(db)
+	ctx := context.Background()
+
+	userTaskKeys := func(result Paginated[UserTask]) []int64 {
+		keys := []int64{}
+		for _, userTask := range result.Items {
+			keys = append(keys, userTask.Key)
+		}
+		return keys
+	}
+
+	t.Run("all user tasks", func(t *testing.T) {
+		result, err := fetcher.GetUserTasks(ctx, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.TotalCount)
+		assert.Equal(t, []int64{3, 2, 1}, userTaskKeys(result))
+	})
+
+	t.Run("user tasks by assignee", func(t *testing.T) {
+		result, err := fetcher.GetUserTasks(ctx, nil, &Filter{Input: "alice", Type: FilterTypeIs})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{3, 1}, userTaskKeys(result))
+	})
+
+	t.Run("user tasks for instance", func(t *testing.T) {
+		result, err := fetcher.GetUserTasksForInstance(ctx, nil, nil, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.TotalCount)
+		assert.Equal(t, []int64{2, 1}, userTaskKeys(result))
+	})
+}
+
 func TestPaginatedQuery(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
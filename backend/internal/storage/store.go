@@ -3,14 +3,31 @@ package storage
 import (
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Storer applies Zeebe records to storage.
+//
+// Every method takes the position of the record being applied. Rows remember
+// the position of the record that last changed them and records at earlier
+// positions are ignored, so applying the same records again is safe.
 type Storer interface {
+	// Apply changes through the storer passed to `fn` in one transaction,
+	// which is rolled back if `fn` fails. Transactions can be nested, in
+	// which case only the nested transaction is rolled back on failure.
+	//
+	// Audit log events and offset commits are buffered within a
+	// transaction and written in bulk once the outermost transaction
+	// commits, so they can't be read back before that.
+	Transaction(fn func(storer Storer) error) error
+
 	ProcessDeployed(
+		position int64,
 		processDefinitionKey int64,
 		bpmnProcessID string,
 		version int64,
@@ -18,38 +35,141 @@ type Storer interface {
 		bpmnResourceRaw []byte,
 	) error
 
+	// Store a deployment with its resources and the definitions in them.
+	// Deployments are keyed by their record key, so storing one again does
+	// nothing.
+	DeploymentCreated(
+		position int64,
+		deploymentKey int64,
+		resources []DeploymentResource,
+		definitions []DeployedDefinition,
+		time time.Time,
+	) error
+
+	DecisionRequirementsDeployed(
+		position int64,
+		decisionRequirementsKey int64,
+		decisionRequirementsID string,
+		name string,
+		version int64,
+		namespace string,
+		resourceName string,
+		deploymentTime time.Time,
+		dmnResourceRaw []byte,
+	) error
+
+	DecisionDeployed(
+		position int64,
+		decisionKey int64,
+		decisionID string,
+		name string,
+		version int64,
+		decisionRequirementsKey int64,
+		decisionRequirementsID string,
+		deploymentTime time.Time,
+	) error
+
+	// Store an evaluation of a decision together with the decisions
+	// evaluated as part of it. Evaluations are keyed by their record key, so
+	// storing one again does nothing.
+	DecisionEvaluated(
+		position int64,
+		key int64,
+		decisionKey int64,
+		decisionID string,
+		decisionName string,
+		decisionVersion int64,
+		decisionRequirementsKey int64,
+		output string,
+		processInstanceKey int64,
+		processDefinitionKey int64,
+		elementInstanceKey int64,
+		elementID string,
+		state string,
+		failureMessage string,
+		failedDecisionID string,
+		evaluatedDecisions []EvaluatedDecision,
+		time time.Time,
+	) error
+
 	ProcessInstanceActivated(
+		position int64,
 		processInstanceKey int64,
 		processDefinitionKey int64,
 		version int64,
+		parentProcessInstanceKey int64,
+		parentElementInstanceKey int64,
 		startTime time.Time,
 	) error
 
 	ProcessInstanceCompleted(
+		position int64,
 		processInstanceKey int64,
 		endTime time.Time,
 	) error
 
 	ProcessInstanceTerminated(
+		position int64,
+		processInstanceKey int64,
+		endTime time.Time,
+	) error
+
+	ElementInstanceActivating(
+		position int64,
+		key int64,
 		processInstanceKey int64,
+		elementID string,
+		elementType string,
+		flowScopeKey int64,
+		startTime time.Time,
+	) error
+
+	ElementInstanceUpdated(
+		position int64,
+		key int64,
+		state string,
+	) error
+
+	// Update the state of an element instance that completed or was
+	// terminated.
+	ElementInstanceEnded(
+		position int64,
+		key int64,
+		state string,
 		endTime time.Time,
 	) error
 
 	VariableCreated(
+		position int64,
 		processInstanceKey int64,
+		scopeKey int64,
 		name string,
 		value string,
 		time time.Time,
 	) error
 
 	VariableUpdated(
+		position int64,
+		processInstanceKey int64,
+		scopeKey int64,
+		name string,
+		value string,
+		time time.Time,
+	) error
+
+	VariableChanged(
+		partitionID int64,
+		position int64,
 		processInstanceKey int64,
+		scopeKey int64,
 		name string,
+		intent string,
 		value string,
 		time time.Time,
 	) error
 
 	IncidentCreated(
+		position int64,
 		key int64,
 		processInstanceKey int64,
 		elementID string,
@@ -59,6 +179,7 @@ type Storer interface {
 	) error
 
 	IncidentResolved(
+		position int64,
 		key int64,
 		time time.Time,
 	) error
@@ -73,36 +194,400 @@ type Storer interface {
 	) error
 
 	JobCreated(
+		position int64,
 		key int64,
 		elementID string,
+		elementInstanceKey int64,
 		processInstanceKey int64,
 		jobType string,
 		retries int64,
 		worker string,
+		customHeaders string,
 		time time.Time,
 	) error
 
 	JobUpdated(
+		position int64,
 		key int64,
 		retries int64,
 		worker string,
 		state string,
+		errorMessage string,
+		errorCode string,
+		deadline sql.NullTime,
+		retryBackoff int64,
+		time time.Time,
+	) error
+
+	// Add a step in the lifecycle of a job to its history. Events are keyed
+	// by their partition and position, so adding one again does nothing.
+	JobEventOccurred(
+		partitionID int64,
+		position int64,
+		jobKey int64,
+		processInstanceKey int64,
+		intent string,
+		retries int64,
+		worker string,
+		errorMessage string,
+		errorCode string,
+		retryBackoff int64,
+		time time.Time,
+	) error
+
+	// Store a worker asking for or being handed jobs. Batches are keyed by
+	// their partition and position, so storing one again does nothing.
+	JobBatchOccurred(
+		partitionID int64,
+		position int64,
+		worker string,
+		jobType string,
+		intent string,
+		timeout int64,
+		maxJobsToActivate int64,
+		jobCount int64,
+		truncated bool,
+		time time.Time,
+	) error
+
+	// Store a rejected command. The rejection is keyed by its partition and
+	// position, so storing it again does nothing.
+	CommandRejected(
+		partitionID int64,
+		position int64,
+		key int64,
+		processInstanceKey int64,
+		elementID string,
+		valueType string,
+		intent string,
+		rejectionType string,
+		rejectionReason string,
+		time time.Time,
+	) error
+
+	MessagePublished(
+		position int64,
+		key int64,
+		name string,
+		correlationKey string,
+		messageID string,
+		timeToLive int64,
+		variables string,
+		time time.Time,
+	) error
+
+	// Mark a message correlated. Messages that haven't been stored are
+	// ignored, since correlating doesn't depend on them being stored.
+	MessageCorrelated(
+		position int64,
+		key int64,
+		time time.Time,
+	) error
+
+	MessageExpired(
+		position int64,
+		key int64,
+		time time.Time,
+	) error
+
+	// Create or update the subscription of an element instance to messages
+	// named `messageName`. Zero element ID and message key don't overwrite
+	// stored ones, since not every subscription record has them.
+	MessageSubscriptionUpdated(
+		position int64,
+		processInstanceKey int64,
+		elementInstanceKey int64,
+		elementID string,
+		messageName string,
+		correlationKey string,
+		messageKey int64,
+		interrupting bool,
+		state string,
+		time time.Time,
+	) error
+
+	TimerCreated(
+		position int64,
+		key int64,
+		processInstanceKey int64,
+		processDefinitionKey int64,
+		elementInstanceKey int64,
+		targetElementID string,
+		dueDate time.Time,
+		repetitions int64,
+		time time.Time,
+	) error
+
+	TimerUpdated(
+		position int64,
+		key int64,
+		state string,
+		time time.Time,
+	) error
+
+	UserTaskCreated(
+		position int64,
+		key int64,
+		processInstanceKey int64,
+		processDefinitionKey int64,
+		elementInstanceKey int64,
+		elementID string,
+		assignee string,
+		candidateGroups string,
+		candidateUsers string,
+		dueDate sql.NullTime,
+		followUpDate sql.NullTime,
+		formKey int64,
+		time time.Time,
+	) error
+
+	// Update the assignee and attributes of a user task that's still open.
+	UserTaskUpdated(
+		position int64,
+		key int64,
+		assignee string,
+		candidateGroups string,
+		candidateUsers string,
+		dueDate sql.NullTime,
+		followUpDate sql.NullTime,
+		time time.Time,
+	) error
+
+	// Update the state of a user task that completed or was canceled.
+	UserTaskEnded(
+		position int64,
+		key int64,
+		state string,
+		endTime time.Time,
+	) error
+
+	// Store a broadcasted signal. Signals are broadcasted to every
+	// partition with the same key, so storing one again does nothing.
+	SignalBroadcasted(
+		position int64,
+		key int64,
+		name string,
+		variables string,
+		time time.Time,
+	) error
+
+	// Create or update the subscription of a signal catch event.
+	SignalSubscriptionUpdated(
+		position int64,
+		key int64,
+		signalName string,
+		processDefinitionKey int64,
+		bpmnProcessID string,
+		catchEventID string,
+		catchEventInstanceKey int64,
+		state string,
+		time time.Time,
+	) error
+
+	// Store an error the broker ran into. Errors are keyed by their
+	// partition and position, so storing one again does nothing.
+	BrokerErrorOccurred(
+		partitionID int64,
+		position int64,
+		errorEventPosition int64,
+		processInstanceKey int64,
+		exceptionMessage string,
+		stacktrace string,
+		time time.Time,
+	) error
+
+	OffsetCommitted(
+		topic string,
+		partition int32,
+		offset int64,
+	) error
+
+	// Returns the last committed offset for a topic partition and whether
+	// one has been committed at all.
+	LastCommittedOffset(
+		topic string,
+		partition int32,
+	) (int64, bool, error)
+
+	// Store a record that failed to be applied. Storing the same record
+	// again counts as another failed attempt.
+	DeadLetterStored(
+		topic string,
+		partition int32,
+		offset int64,
+		payload []byte,
+		errorMessage string,
+		time time.Time,
+	) error
+
+	DeadLetterDiscarded(
+		id int64,
+	) error
+
+	DeadLetter(
+		id int64,
+	) (DeadLetter, error)
+
+	// Returns the dead letters that have been attempted fewer than
+	// `maxAttempts` times, oldest first.
+	DeadLettersToRetry(
+		maxAttempts int64,
+	) ([]DeadLetter, error)
+
+	// Store a record waiting for the row with `missingKey` to be created.
+	// Storing the same record again does nothing.
+	PendingRecordStored(
+		topic string,
+		partition int32,
+		offset int64,
+		payload []byte,
+		missingKey int64,
 		time time.Time,
 	) error
+
+	// Returns the pending records waiting for the row with `missingKey`,
+	// oldest first.
+	PendingRecords(
+		missingKey int64,
+	) ([]PendingRecord, error)
+
+	PendingRecordRemoved(
+		id int64,
+	) error
+
+	// Remove the pending record read from the given origin, e.g. once it has
+	// been applied from memory. Does nothing if there is no such record.
+	PendingRecordResolved(
+		topic string,
+		partition int32,
+		offset int64,
+	) error
+
+	PendingRecord(
+		id int64,
+	) (PendingRecord, error)
+}
+
+// Returns whether applying a record failed because a row it refers to
+// doesn't exist, e.g. because the record creating the row hasn't been
+// applied yet.
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
 }
 
 // TODO: use context for queries where reasonable
 
+// Maximum number of rows inserted by a single bulk insert statement.
+const BulkInsertSize = 1000
+
 type databaseStorer struct {
 	db *gorm.DB
+	// Writes buffered until the outermost transaction commits, nil outside
+	// transactions.
+	buffered *bufferedWrites
 }
 
 func NewStorer(db *gorm.DB) Storer {
-	return &databaseStorer{db}
+	return &databaseStorer{db: db}
+}
+
+// Identifies a topic partition whose offset is being committed.
+type offsetKey struct {
+	topic     string
+	partition int32
+}
+
+// Writes which don't need to be read back within a transaction, so they can
+// be written in bulk once it commits.
+type bufferedWrites struct {
+	auditLogs []AuditLog
+	// Last offset committed for each topic partition.
+	offsets map[offsetKey]KafkaOffset
+}
+
+func (r *databaseStorer) Transaction(fn func(storer Storer) error) error {
+	if r.buffered != nil {
+		// Nested transactions are savepoints, so audit log events
+		// added in them must be dropped on rollback too. Offsets are
+		// committed whether applying a record succeeds or not, so
+		// they're kept
+		auditLogs := len(r.buffered.auditLogs)
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			return fn(&databaseStorer{db: tx, buffered: r.buffered})
+		})
+		if err != nil {
+			r.buffered.auditLogs = r.buffered.auditLogs[:auditLogs]
+		}
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		buffered := &bufferedWrites{offsets: map[offsetKey]KafkaOffset{}}
+		err := fn(&databaseStorer{db: tx, buffered: buffered})
+		if err != nil {
+			return err
+		}
+
+		return buffered.flush(tx)
+	})
+}
+
+// Write the buffered writes in bulk.
+func (w *bufferedWrites) flush(db *gorm.DB) error {
+	if len(w.auditLogs) != 0 {
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(w.auditLogs, BulkInsertSize).Error
+		if err != nil {
+			return fmt.Errorf("failed to add to audit log: %w", err)
+		}
+	}
+
+	if len(w.offsets) != 0 {
+		offsets := make([]KafkaOffset, 0, len(w.offsets))
+		for _, offset := range w.offsets {
+			offsets = append(offsets, offset)
+		}
+
+		err := db.Clauses(offsetUpsert).
+			CreateInBatches(offsets, BulkInsertSize).Error
+		if err != nil {
+			return fmt.Errorf("failed to commit offsets: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Returns an upsert clause for rows of `table` identified by `keys`. On
+// conflict `columns` are only updated if the row was last changed by a record
+// at an earlier position, which makes applying a record again a no-op and
+// keeps older records from overwriting newer state.
+func newerPositionUpsert(table string, keys []string, columns []string) clause.OnConflict {
+	conflictColumns := make([]clause.Column, 0, len(keys))
+	for _, key := range keys {
+		conflictColumns = append(conflictColumns, clause.Column{Name: key})
+	}
+
+	return clause.OnConflict{
+		Columns:   conflictColumns,
+		DoUpdates: clause.AssignmentColumns(append(columns, "position")),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: table + ".position < excluded.position"},
+		}},
+	}
+}
+
+// Condition for updating only rows last changed by a record at an earlier
+// position than `position`.
+func olderPosition(position int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("position < ?", position)
+	}
 }
 
 // call this for each processesMetadata
 func (r *databaseStorer) ProcessDeployed(
+	position int64,
 	processDefinitionKey int64,
 	bpmnProcessID string,
 	version int64,
@@ -114,12 +599,17 @@ func (r *databaseStorer) ProcessDeployed(
 		BpmnFile:             base64.StdEncoding.EncodeToString(bpmnResourceRaw),
 	}
 
-	err := r.db.Create(&Process{
+	err := r.db.Clauses(newerPositionUpsert(
+		Process{}.TableName(),
+		[]string{"process_definition_key"},
+		[]string{"bpmn_process_id", "version", "deployment_time"},
+	)).Create(&Process{
 		ProcessDefinitionKey: processDefinitionKey,
 		BpmnProcessID:        bpmnProcessID,
 		Version:              version,
 		DeploymentTime:       deploymentTime,
 		BpmnResource:         bpmnResource,
+		Position:             position,
 	}).Error
 	if err != nil {
 		return fmt.Errorf("failed to create process: %w", err)
@@ -128,59 +618,221 @@ func (r *databaseStorer) ProcessDeployed(
 	return nil
 }
 
-func (r *databaseStorer) ProcessInstanceActivated(
-	processInstanceKey int64,
-	processDefinitionKey int64,
-	version int64,
-	startTime time.Time,
+func (r *databaseStorer) DeploymentCreated(
+	position int64,
+	deploymentKey int64,
+	resources []DeploymentResource,
+	definitions []DeployedDefinition,
+	time time.Time,
 ) error {
-	err := r.db.Create(&Instance{
-		ProcessInstanceKey:   processInstanceKey,
-		ProcessDefinitionKey: processDefinitionKey,
-		Version:              version,
-		Status:               "ACTIVE",
-		StartTime:            startTime,
+	for i := range resources {
+		resources[i].DeploymentKey = deploymentKey
+	}
+	for i := range definitions {
+		definitions[i].DeploymentKey = deploymentKey
+	}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Deployment{
+		DeploymentKey: deploymentKey,
+		Time:          time,
+		Resources:     resources,
+		Definitions:   definitions,
+		Position:      position,
 	}).Error
 	if err != nil {
-		return fmt.Errorf("failed to create process instance: %w", err)
+		return fmt.Errorf("failed to store deployment: %w", err)
 	}
 
 	return nil
 }
 
-func (r *databaseStorer) ProcessInstanceCompleted(
-	processInstanceKey int64,
-	endTime time.Time,
+// call this for each decisionRequirementsMetadata
+func (r *databaseStorer) DecisionRequirementsDeployed(
+	position int64,
+	decisionRequirementsKey int64,
+	decisionRequirementsID string,
+	name string,
+	version int64,
+	namespace string,
+	resourceName string,
+	deploymentTime time.Time,
+	dmnResourceRaw []byte,
 ) error {
-	var instance Instance
-	err := r.db.
-		Where(&Instance{ProcessInstanceKey: processInstanceKey}).
-		First(&instance).Error
-	if err != nil {
-		return fmt.Errorf("failed to find process instance: %w", err)
+	dmnResource := DmnResource{
+		DecisionRequirementsKey: decisionRequirementsKey,
+		DmnFile:                 base64.StdEncoding.EncodeToString(dmnResourceRaw),
 	}
 
-	err = r.db.Model(&instance).
-		Select("Status", "EndTime").
-		Updates(Instance{
-			Status: "COMPLETED",
-			EndTime: sql.NullTime{
-				Time:  endTime,
-				Valid: true,
-			},
-		}).Error
+	err := r.db.Clauses(newerPositionUpsert(
+		DecisionRequirements{}.TableName(),
+		[]string{"decision_requirements_key"},
+		[]string{"decision_requirements_id", "name", "version", "namespace", "resource_name", "deployment_time"},
+	)).Create(&DecisionRequirements{
+		DecisionRequirementsKey: decisionRequirementsKey,
+		DecisionRequirementsID:  decisionRequirementsID,
+		Name:                    name,
+		Version:                 version,
+		Namespace:               namespace,
+		ResourceName:            resourceName,
+		DeploymentTime:          deploymentTime,
+		DmnResource:             dmnResource,
+		Position:                position,
+	}).Error
 	if err != nil {
-		return fmt.Errorf("failed to update instance: %w", err)
+		return fmt.Errorf("failed to create decision requirements: %w", err)
 	}
 
 	return nil
 }
 
-func (r *databaseStorer) ProcessInstanceTerminated(
-	processInstanceKey int64,
-	endTime time.Time,
+// call this for each decisionsMetadata
+func (r *databaseStorer) DecisionDeployed(
+	position int64,
+	decisionKey int64,
+	decisionID string,
+	name string,
+	version int64,
+	decisionRequirementsKey int64,
+	decisionRequirementsID string,
+	deploymentTime time.Time,
 ) error {
-	var instance Instance
+	err := r.db.Clauses(newerPositionUpsert(
+		Decision{}.TableName(),
+		[]string{"decision_key"},
+		[]string{"decision_id", "name", "version", "decision_requirements_key", "decision_requirements_id", "deployment_time"},
+	)).Create(&Decision{
+		DecisionKey:             decisionKey,
+		DecisionID:              decisionID,
+		Name:                    name,
+		Version:                 version,
+		DecisionRequirementsKey: decisionRequirementsKey,
+		DecisionRequirementsID:  decisionRequirementsID,
+		DeploymentTime:          deploymentTime,
+		Position:                position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create decision: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) DecisionEvaluated(
+	position int64,
+	key int64,
+	decisionKey int64,
+	decisionID string,
+	decisionName string,
+	decisionVersion int64,
+	decisionRequirementsKey int64,
+	output string,
+	processInstanceKey int64,
+	processDefinitionKey int64,
+	elementInstanceKey int64,
+	elementID string,
+	state string,
+	failureMessage string,
+	failedDecisionID string,
+	evaluatedDecisions []EvaluatedDecision,
+	time time.Time,
+) error {
+	for i := range evaluatedDecisions {
+		evaluatedDecisions[i].EvaluationKey = key
+		evaluatedDecisions[i].Index = int64(i)
+	}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&DecisionEvaluation{
+		Key:                     key,
+		DecisionKey:             decisionKey,
+		DecisionID:              decisionID,
+		DecisionName:            decisionName,
+		DecisionVersion:         decisionVersion,
+		DecisionRequirementsKey: decisionRequirementsKey,
+		Output:                  output,
+		ProcessInstanceKey:      processInstanceKey,
+		ProcessDefinitionKey:    processDefinitionKey,
+		ElementInstanceKey:      elementInstanceKey,
+		ElementID:               elementID,
+		State:                   state,
+		FailureMessage:          failureMessage,
+		FailedDecisionID:        failedDecisionID,
+		Time:                    time,
+		Decisions:               evaluatedDecisions,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store decision evaluation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) ProcessInstanceActivated(
+	position int64,
+	processInstanceKey int64,
+	processDefinitionKey int64,
+	version int64,
+	parentProcessInstanceKey int64,
+	parentElementInstanceKey int64,
+	startTime time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		Instance{}.TableName(),
+		[]string{"process_instance_key"},
+		[]string{"process_definition_key", "version", "status", "start_time", "parent_process_instance_key", "parent_element_instance_key"},
+	)).Create(&Instance{
+		ProcessInstanceKey:       processInstanceKey,
+		ProcessDefinitionKey:     processDefinitionKey,
+		Version:                  version,
+		Status:                   "ACTIVE",
+		StartTime:                startTime,
+		ParentProcessInstanceKey: parentProcessInstanceKey,
+		ParentElementInstanceKey: parentElementInstanceKey,
+		Position:                 position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create process instance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) ProcessInstanceCompleted(
+	position int64,
+	processInstanceKey int64,
+	endTime time.Time,
+) error {
+	var instance Instance
+	err := r.db.
+		Where(&Instance{ProcessInstanceKey: processInstanceKey}).
+		First(&instance).Error
+	if err != nil {
+		return fmt.Errorf("failed to find process instance: %w", err)
+	}
+
+	err = r.db.Model(&instance).
+		Scopes(olderPosition(position)).
+		Select("Status", "EndTime", "Position").
+		Updates(Instance{
+			Status: "COMPLETED",
+			EndTime: sql.NullTime{
+				Time:  endTime,
+				Valid: true,
+			},
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update instance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) ProcessInstanceTerminated(
+	position int64,
+	processInstanceKey int64,
+	endTime time.Time,
+) error {
+	var instance Instance
 	err := r.db.
 		Where(&Instance{ProcessInstanceKey: processInstanceKey}).
 		First(&instance).Error
@@ -189,13 +841,15 @@ func (r *databaseStorer) ProcessInstanceTerminated(
 	}
 
 	err = r.db.Model(&instance).
-		Select("Status", "EndTime").
+		Scopes(olderPosition(position)).
+		Select("Status", "EndTime", "Position").
 		Updates(Instance{
 			Status: "TERMINATED",
 			EndTime: sql.NullTime{
 				Time:  endTime,
 				Valid: true,
 			},
+			Position: position,
 		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to update instance: %w", err)
@@ -204,17 +858,114 @@ func (r *databaseStorer) ProcessInstanceTerminated(
 	return nil
 }
 
+func (r *databaseStorer) ElementInstanceActivating(
+	position int64,
+	key int64,
+	processInstanceKey int64,
+	elementID string,
+	elementType string,
+	flowScopeKey int64,
+	startTime time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		ElementInstance{}.TableName(),
+		[]string{"key"},
+		[]string{"process_instance_key", "element_id", "element_type", "flow_scope_key", "state", "start_time"},
+	)).Create(&ElementInstance{
+		Key:                key,
+		ProcessInstanceKey: processInstanceKey,
+		ElementID:          elementID,
+		ElementType:        elementType,
+		FlowScopeKey:       flowScopeKey,
+		State:              "ELEMENT_ACTIVATING",
+		StartTime:          startTime,
+		Position:           position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create element instance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) ElementInstanceUpdated(
+	position int64,
+	key int64,
+	state string,
+) error {
+	var elementInstance ElementInstance
+	err := r.db.
+		Where(&ElementInstance{Key: key}).
+		First(&elementInstance).Error
+	if err != nil {
+		return fmt.Errorf("failed to find element instance: %w", err)
+	}
+
+	err = r.db.Model(&elementInstance).
+		Scopes(olderPosition(position)).
+		Select("State", "Position").
+		Updates(ElementInstance{
+			State:    state,
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update element instance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) ElementInstanceEnded(
+	position int64,
+	key int64,
+	state string,
+	endTime time.Time,
+) error {
+	var elementInstance ElementInstance
+	err := r.db.
+		Where(&ElementInstance{Key: key}).
+		First(&elementInstance).Error
+	if err != nil {
+		return fmt.Errorf("failed to find element instance: %w", err)
+	}
+
+	err = r.db.Model(&elementInstance).
+		Scopes(olderPosition(position)).
+		Select("State", "EndTime", "Position").
+		Updates(ElementInstance{
+			State: state,
+			EndTime: sql.NullTime{
+				Time:  endTime,
+				Valid: true,
+			},
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update element instance: %w", err)
+	}
+
+	return nil
+}
+
 func (r *databaseStorer) VariableCreated(
+	position int64,
 	processInstanceKey int64,
+	scopeKey int64,
 	name string,
 	value string,
 	time time.Time,
 ) error {
-	err := r.db.Create(&Variable{
-		ProcessInstanceKey: processInstanceKey,
+	err := r.db.Clauses(newerPositionUpsert(
+		Variable{}.TableName(),
+		[]string{"scope_key", "name"},
+		[]string{"process_instance_key", "value", "time"},
+	)).Create(&Variable{
+		ScopeKey:           scopeKey,
 		Name:               name,
+		ProcessInstanceKey: processInstanceKey,
 		Value:              value,
 		Time:               time,
+		Position:           position,
 	}).Error
 	if err != nil {
 		return fmt.Errorf("failed to create variable: %w", err)
@@ -224,7 +975,9 @@ func (r *databaseStorer) VariableCreated(
 }
 
 func (r *databaseStorer) VariableUpdated(
+	position int64,
 	processInstanceKey int64,
+	scopeKey int64,
 	name string,
 	value string,
 	time time.Time,
@@ -232,8 +985,9 @@ func (r *databaseStorer) VariableUpdated(
 	var variable Variable
 	err := r.db.
 		Where(&Variable{
-			ProcessInstanceKey: processInstanceKey,
+			ScopeKey:           scopeKey,
 			Name:               name,
+			ProcessInstanceKey: processInstanceKey,
 		}).
 		First(&variable).Error
 	if err != nil {
@@ -241,10 +995,12 @@ func (r *databaseStorer) VariableUpdated(
 	}
 
 	err = r.db.Model(&variable).
-		Select("Value", "Time").
+		Scopes(olderPosition(position)).
+		Select("Value", "Time", "Position").
 		Updates(&Variable{
-			Value: value,
-			Time:  time,
+			Value:    value,
+			Time:     time,
+			Position: position,
 		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to save variable: %w", err)
@@ -253,8 +1009,37 @@ func (r *databaseStorer) VariableUpdated(
 	return nil
 }
 
+// Add a variable value to the variable's history.
+func (r *databaseStorer) VariableChanged(
+	partitionID int64,
+	position int64,
+	processInstanceKey int64,
+	scopeKey int64,
+	name string,
+	intent string,
+	value string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&VariableChange{
+		PartitionID:        partitionID,
+		Position:           position,
+		ProcessInstanceKey: processInstanceKey,
+		ScopeKey:           scopeKey,
+		Name:               name,
+		Intent:             intent,
+		Value:              value,
+		Time:               time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to add to variable history: %w", err)
+	}
+
+	return nil
+}
+
 // Store a newly created incident in the database.
 func (r *databaseStorer) IncidentCreated(
+	position int64,
 	key int64,
 	processInstanceKey int64,
 	elementID string,
@@ -262,7 +1047,11 @@ func (r *databaseStorer) IncidentCreated(
 	errorMessage string,
 	time time.Time,
 ) error {
-	err := r.db.Create(&Incident{
+	err := r.db.Clauses(newerPositionUpsert(
+		Incident{}.TableName(),
+		[]string{"key"},
+		[]string{"process_instance_key", "element_id", "error_type", "error_message", "state", "time"},
+	)).Create(&Incident{
 		Key:                key,
 		ProcessInstanceKey: processInstanceKey,
 		ElementID:          elementID,
@@ -270,6 +1059,7 @@ func (r *databaseStorer) IncidentCreated(
 		ErrorMessage:       errorMessage,
 		State:              "CREATED",
 		Time:               time,
+		Position:           position,
 	}).Error
 	if err != nil {
 		return fmt.Errorf("failed to create incident: %w", err)
@@ -280,6 +1070,7 @@ func (r *databaseStorer) IncidentCreated(
 
 // Handle incident being resolved.
 func (r *databaseStorer) IncidentResolved(
+	position int64,
 	key int64,
 	time time.Time,
 ) error {
@@ -294,10 +1085,12 @@ func (r *databaseStorer) IncidentResolved(
 	}
 
 	err = r.db.Model(&incident).
-		Select("State", "Time").
+		Scopes(olderPosition(position)).
+		Select("State", "Time", "Position").
 		Updates(&Incident{
-			State: "RESOLVED",
-			Time:  time,
+			State:    "RESOLVED",
+			Time:     time,
+			Position: position,
 		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to save incident: %w", err)
@@ -306,6 +1099,8 @@ func (r *databaseStorer) IncidentResolved(
 	return nil
 }
 
+// Add an event to the audit log. The audit log is keyed by position, so
+// adding the same event again does nothing.
 func (r *databaseStorer) AuditLogEventOccurred(
 	position int64,
 	processInstanceKey int64,
@@ -314,14 +1109,20 @@ func (r *databaseStorer) AuditLogEventOccurred(
 	intent string,
 	timestamp time.Time,
 ) error {
-	err := r.db.Create(&AuditLog{
+	auditLog := AuditLog{
 		Position:           position,
 		ProcessInstanceKey: processInstanceKey,
 		ElementID:          elementID,
 		ElementType:        elementType,
 		Intent:             intent,
 		Time:               timestamp,
-	}).Error
+	}
+	if r.buffered != nil {
+		r.buffered.auditLogs = append(r.buffered.auditLogs, auditLog)
+		return nil
+	}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&auditLog).Error
 	if err != nil {
 		return fmt.Errorf("failed to add to audit log: %w", err)
 	}
@@ -330,23 +1131,33 @@ func (r *databaseStorer) AuditLogEventOccurred(
 }
 
 func (r *databaseStorer) JobCreated(
+	position int64,
 	key int64,
 	elementID string,
+	elementInstanceKey int64,
 	processInstanceKey int64,
 	jobType string,
 	retries int64,
 	worker string,
+	customHeaders string,
 	time time.Time,
 ) error {
-	err := r.db.Create(&Job{
+	err := r.db.Clauses(newerPositionUpsert(
+		Job{}.TableName(),
+		[]string{"key"},
+		[]string{"element_id", "element_instance_key", "process_instance_key", "type", "retries", "worker", "state", "custom_headers", "time"},
+	)).Create(&Job{
 		Key:                key,
 		ElementID:          elementID,
+		ElementInstanceKey: elementInstanceKey,
 		ProcessInstanceKey: processInstanceKey,
 		Type:               jobType,
 		Retries:            retries,
 		Worker:             worker,
 		State:              "CREATED",
+		CustomHeaders:      customHeaders,
 		Time:               time,
+		Position:           position,
 	}).Error
 	if err != nil {
 		return fmt.Errorf("failed to create job: %w", err)
@@ -356,10 +1167,15 @@ func (r *databaseStorer) JobCreated(
 }
 
 func (r *databaseStorer) JobUpdated(
+	position int64,
 	key int64,
 	retries int64,
 	worker string,
 	state string,
+	errorMessage string,
+	errorCode string,
+	deadline sql.NullTime,
+	retryBackoff int64,
 	time time.Time,
 ) error {
 	var job Job
@@ -373,12 +1189,18 @@ func (r *databaseStorer) JobUpdated(
 	}
 
 	err = r.db.Model(&job).
-		Select("Retries", "Worker", "State", "Time").
+		Scopes(olderPosition(position)).
+		Select("Retries", "Worker", "State", "ErrorMessage", "ErrorCode", "Deadline", "RetryBackoff", "Time", "Position").
 		Updates(&Job{
-			Retries: retries,
-			Worker:  worker,
-			State:   state,
-			Time:    time,
+			Retries:      retries,
+			Worker:       worker,
+			State:        state,
+			ErrorMessage: errorMessage,
+			ErrorCode:    errorCode,
+			Deadline:     deadline,
+			RetryBackoff: retryBackoff,
+			Time:         time,
+			Position:     position,
 		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to save job: %w", err)
@@ -386,3 +1208,716 @@ func (r *databaseStorer) JobUpdated(
 
 	return nil
 }
+
+func (r *databaseStorer) JobEventOccurred(
+	partitionID int64,
+	position int64,
+	jobKey int64,
+	processInstanceKey int64,
+	intent string,
+	retries int64,
+	worker string,
+	errorMessage string,
+	errorCode string,
+	retryBackoff int64,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&JobEvent{
+		PartitionID:        partitionID,
+		Position:           position,
+		JobKey:             jobKey,
+		ProcessInstanceKey: processInstanceKey,
+		Intent:             intent,
+		Retries:            retries,
+		Worker:             worker,
+		ErrorMessage:       errorMessage,
+		ErrorCode:          errorCode,
+		RetryBackoff:       retryBackoff,
+		Time:               time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to add to job history: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) JobBatchOccurred(
+	partitionID int64,
+	position int64,
+	worker string,
+	jobType string,
+	intent string,
+	timeout int64,
+	maxJobsToActivate int64,
+	jobCount int64,
+	truncated bool,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&JobBatch{
+		PartitionID:       partitionID,
+		Position:          position,
+		Worker:            worker,
+		Type:              jobType,
+		Intent:            intent,
+		Timeout:           timeout,
+		MaxJobsToActivate: maxJobsToActivate,
+		JobCount:          jobCount,
+		Truncated:         truncated,
+		Time:              time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store job batch: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) CommandRejected(
+	partitionID int64,
+	position int64,
+	key int64,
+	processInstanceKey int64,
+	elementID string,
+	valueType string,
+	intent string,
+	rejectionType string,
+	rejectionReason string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Rejection{
+		PartitionID:        partitionID,
+		Position:           position,
+		Key:                key,
+		ProcessInstanceKey: processInstanceKey,
+		ElementID:          elementID,
+		ValueType:          valueType,
+		Intent:             intent,
+		RejectionType:      rejectionType,
+		RejectionReason:    rejectionReason,
+		Time:               time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store rejection: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) MessagePublished(
+	position int64,
+	key int64,
+	name string,
+	correlationKey string,
+	messageID string,
+	timeToLive int64,
+	variables string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		Message{}.TableName(),
+		[]string{"key"},
+		[]string{"name", "correlation_key", "message_id", "time_to_live", "variables", "state", "publish_time", "time"},
+	)).Create(&Message{
+		Key:            key,
+		Name:           name,
+		CorrelationKey: correlationKey,
+		MessageID:      messageID,
+		TimeToLive:     timeToLive,
+		Variables:      variables,
+		State:          "PUBLISHED",
+		PublishTime:    time,
+		Time:           time,
+		Position:       position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) MessageCorrelated(
+	position int64,
+	key int64,
+	time time.Time,
+) error {
+	result := r.db.Model(&Message{}).
+		Where(&Message{Key: key}).
+		Scopes(olderPosition(position)).
+		Select("State", "Time", "Position").
+		Updates(&Message{
+			State:    "CORRELATED",
+			Time:     time,
+			Position: position,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save message: %w", result.Error)
+	}
+
+	// Nothing is updated either if the message is missing or if it has
+	// been changed by a newer record already, which is only fine for the
+	// latter
+	if result.RowsAffected == 0 {
+		var count int64
+		err := r.db.Model(&Message{}).Where(&Message{Key: key}).Count(&count).Error
+		if err != nil {
+			return fmt.Errorf("failed to find message: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("failed to find message: %w", gorm.ErrRecordNotFound)
+		}
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) MessageExpired(
+	position int64,
+	key int64,
+	time time.Time,
+) error {
+	var message Message
+	err := r.db.
+		Where(&Message{
+			Key: key,
+		}).
+		First(&message).Error
+	if err != nil {
+		return fmt.Errorf("failed to find message: %w", err)
+	}
+
+	err = r.db.Model(&message).
+		Scopes(olderPosition(position)).
+		Select("State", "Time", "Position").
+		Updates(&Message{
+			State:    "EXPIRED",
+			Time:     time,
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) MessageSubscriptionUpdated(
+	position int64,
+	processInstanceKey int64,
+	elementInstanceKey int64,
+	elementID string,
+	messageName string,
+	correlationKey string,
+	messageKey int64,
+	interrupting bool,
+	state string,
+	time time.Time,
+) error {
+	columns := []string{"process_instance_key", "correlation_key", "interrupting", "state", "time"}
+	if elementID != "" {
+		columns = append(columns, "element_id")
+	}
+	if messageKey != 0 {
+		columns = append(columns, "message_key")
+	}
+
+	err := r.db.Clauses(newerPositionUpsert(
+		MessageSubscription{}.TableName(),
+		[]string{"element_instance_key", "message_name"},
+		columns,
+	)).Create(&MessageSubscription{
+		ElementInstanceKey: elementInstanceKey,
+		MessageName:        messageName,
+		ProcessInstanceKey: processInstanceKey,
+		ElementID:          elementID,
+		CorrelationKey:     correlationKey,
+		MessageKey:         messageKey,
+		Interrupting:       interrupting,
+		State:              state,
+		Time:               time,
+		Position:           position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save message subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) TimerCreated(
+	position int64,
+	key int64,
+	processInstanceKey int64,
+	processDefinitionKey int64,
+	elementInstanceKey int64,
+	targetElementID string,
+	dueDate time.Time,
+	repetitions int64,
+	time time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		Timer{}.TableName(),
+		[]string{"key"},
+		[]string{"process_instance_key", "process_definition_key", "element_instance_key", "target_element_id", "due_date", "repetitions", "state", "time"},
+	)).Create(&Timer{
+		Key:                  key,
+		ProcessInstanceKey:   processInstanceKey,
+		ProcessDefinitionKey: processDefinitionKey,
+		ElementInstanceKey:   elementInstanceKey,
+		TargetElementID:      targetElementID,
+		DueDate:              dueDate,
+		Repetitions:          repetitions,
+		State:                "CREATED",
+		Time:                 time,
+		Position:             position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create timer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) TimerUpdated(
+	position int64,
+	key int64,
+	state string,
+	time time.Time,
+) error {
+	var timer Timer
+	err := r.db.
+		Where(&Timer{
+			Key: key,
+		}).
+		First(&timer).Error
+	if err != nil {
+		return fmt.Errorf("failed to find timer: %w", err)
+	}
+
+	err = r.db.Model(&timer).
+		Scopes(olderPosition(position)).
+		Select("State", "Time", "Position").
+		Updates(&Timer{
+			State:    state,
+			Time:     time,
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save timer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) UserTaskCreated(
+	position int64,
+	key int64,
+	processInstanceKey int64,
+	processDefinitionKey int64,
+	elementInstanceKey int64,
+	elementID string,
+	assignee string,
+	candidateGroups string,
+	candidateUsers string,
+	dueDate sql.NullTime,
+	followUpDate sql.NullTime,
+	formKey int64,
+	time time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		UserTask{}.TableName(),
+		[]string{"key"},
+		[]string{"process_instance_key", "process_definition_key", "element_instance_key", "element_id", "assignee", "candidate_groups", "candidate_users", "due_date", "follow_up_date", "form_key", "state", "creation_time", "time"},
+	)).Create(&UserTask{
+		Key:                  key,
+		ProcessInstanceKey:   processInstanceKey,
+		ProcessDefinitionKey: processDefinitionKey,
+		ElementInstanceKey:   elementInstanceKey,
+		ElementID:            elementID,
+		Assignee:             assignee,
+		CandidateGroups:      candidateGroups,
+		CandidateUsers:       candidateUsers,
+		DueDate:              dueDate,
+		FollowUpDate:         followUpDate,
+		FormKey:              formKey,
+		State:                "CREATED",
+		CreationTime:         time,
+		Time:                 time,
+		Position:             position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to create user task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) UserTaskUpdated(
+	position int64,
+	key int64,
+	assignee string,
+	candidateGroups string,
+	candidateUsers string,
+	dueDate sql.NullTime,
+	followUpDate sql.NullTime,
+	time time.Time,
+) error {
+	var userTask UserTask
+	err := r.db.
+		Where(&UserTask{
+			Key: key,
+		}).
+		First(&userTask).Error
+	if err != nil {
+		return fmt.Errorf("failed to find user task: %w", err)
+	}
+
+	err = r.db.Model(&userTask).
+		Scopes(olderPosition(position)).
+		Select("Assignee", "CandidateGroups", "CandidateUsers", "DueDate", "FollowUpDate", "Time", "Position").
+		Updates(&UserTask{
+			Assignee:        assignee,
+			CandidateGroups: candidateGroups,
+			CandidateUsers:  candidateUsers,
+			DueDate:         dueDate,
+			FollowUpDate:    followUpDate,
+			Time:            time,
+			Position:        position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save user task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) UserTaskEnded(
+	position int64,
+	key int64,
+	state string,
+	endTime time.Time,
+) error {
+	var userTask UserTask
+	err := r.db.
+		Where(&UserTask{
+			Key: key,
+		}).
+		First(&userTask).Error
+	if err != nil {
+		return fmt.Errorf("failed to find user task: %w", err)
+	}
+
+	err = r.db.Model(&userTask).
+		Scopes(olderPosition(position)).
+		Select("State", "EndTime", "Time", "Position").
+		Updates(&UserTask{
+			State: state,
+			EndTime: sql.NullTime{
+				Time:  endTime,
+				Valid: true,
+			},
+			Time:     endTime,
+			Position: position,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save user task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) SignalBroadcasted(
+	position int64,
+	key int64,
+	name string,
+	variables string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Signal{
+		Key:       key,
+		Name:      name,
+		Variables: variables,
+		Time:      time,
+		Position:  position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store signal: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) SignalSubscriptionUpdated(
+	position int64,
+	key int64,
+	signalName string,
+	processDefinitionKey int64,
+	bpmnProcessID string,
+	catchEventID string,
+	catchEventInstanceKey int64,
+	state string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(newerPositionUpsert(
+		SignalSubscription{}.TableName(),
+		[]string{"key"},
+		[]string{"signal_name", "process_definition_key", "bpmn_process_id", "catch_event_id", "catch_event_instance_key", "state", "time"},
+	)).Create(&SignalSubscription{
+		Key:                   key,
+		SignalName:            signalName,
+		ProcessDefinitionKey:  processDefinitionKey,
+		BpmnProcessID:         bpmnProcessID,
+		CatchEventID:          catchEventID,
+		CatchEventInstanceKey: catchEventInstanceKey,
+		State:                 state,
+		Time:                  time,
+		Position:              position,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update signal subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) BrokerErrorOccurred(
+	partitionID int64,
+	position int64,
+	errorEventPosition int64,
+	processInstanceKey int64,
+	exceptionMessage string,
+	stacktrace string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&BrokerError{
+		PartitionID:        partitionID,
+		Position:           position,
+		ErrorEventPosition: errorEventPosition,
+		ProcessInstanceKey: processInstanceKey,
+		ExceptionMessage:   exceptionMessage,
+		Stacktrace:         stacktrace,
+		Time:               time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store broker error: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert clause for committing offsets.
+var offsetUpsert = clause.OnConflict{
+	Columns: []clause.Column{
+		{Name: "topic"},
+		{Name: "partition"},
+	},
+	DoUpdates: clause.AssignmentColumns([]string{"offset"}),
+}
+
+// Record the offset of the last applied record of a topic partition.
+func (r *databaseStorer) OffsetCommitted(
+	topic string,
+	partition int32,
+	offset int64,
+) error {
+	kafkaOffset := KafkaOffset{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+	}
+	if r.buffered != nil {
+		r.buffered.offsets[offsetKey{topic, partition}] = kafkaOffset
+		return nil
+	}
+
+	err := r.db.Clauses(offsetUpsert).Create(&kafkaOffset).Error
+	if err != nil {
+		return fmt.Errorf("failed to commit offset: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) LastCommittedOffset(
+	topic string,
+	partition int32,
+) (int64, bool, error) {
+	// Not a struct condition, which would leave out partition 0
+	var kafkaOffset KafkaOffset
+	err := r.db.
+		Where("topic = ? AND partition = ?", topic, partition).
+		Limit(1).
+		Find(&kafkaOffset).Error
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find offset: %w", err)
+	}
+
+	// Find doesn't fail on missing rows, so we need to check ourselves
+	// whether anything was found
+	if kafkaOffset.Topic == "" {
+		return 0, false, nil
+	}
+
+	return kafkaOffset.Offset, true, nil
+}
+
+func (r *databaseStorer) DeadLetterStored(
+	topic string,
+	partition int32,
+	offset int64,
+	payload []byte,
+	errorMessage string,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "topic"},
+			{Name: "partition"},
+			{Name: "offset"},
+		},
+		DoUpdates: clause.Assignments(map[string]any{
+			"error":    errorMessage,
+			"time":     time,
+			"attempts": gorm.Expr("dead_letters.attempts + 1"),
+		}),
+	}).Create(&DeadLetter{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Payload:   payload,
+		Error:     errorMessage,
+		Attempts:  1,
+		Time:      time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) DeadLetterDiscarded(
+	id int64,
+) error {
+	result := r.db.Delete(&DeadLetter{ID: id})
+	if result.Error != nil {
+		return fmt.Errorf("failed to discard dead letter: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to find dead letter: %w", gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) DeadLetter(
+	id int64,
+) (DeadLetter, error) {
+	var deadLetter DeadLetter
+	err := r.db.
+		Where(&DeadLetter{ID: id}).
+		First(&deadLetter).Error
+	if err != nil {
+		return deadLetter, fmt.Errorf("failed to find dead letter: %w", err)
+	}
+
+	return deadLetter, nil
+}
+
+func (r *databaseStorer) DeadLettersToRetry(
+	maxAttempts int64,
+) ([]DeadLetter, error) {
+	var deadLetters []DeadLetter
+	err := r.db.
+		Where("attempts < ?", maxAttempts).
+		Order("id").
+		Find(&deadLetters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+func (r *databaseStorer) PendingRecordStored(
+	topic string,
+	partition int32,
+	offset int64,
+	payload []byte,
+	missingKey int64,
+	time time.Time,
+) error {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&PendingRecord{
+		Topic:      topic,
+		Partition:  partition,
+		Offset:     offset,
+		Payload:    payload,
+		MissingKey: missingKey,
+		Time:       time,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store pending record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) PendingRecords(
+	missingKey int64,
+) ([]PendingRecord, error) {
+	var pendingRecords []PendingRecord
+	err := r.db.
+		Where(&PendingRecord{MissingKey: missingKey}).
+		Order("id").
+		Find(&pendingRecords).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending records: %w", err)
+	}
+
+	return pendingRecords, nil
+}
+
+func (r *databaseStorer) PendingRecordRemoved(
+	id int64,
+) error {
+	result := r.db.Delete(&PendingRecord{ID: id})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove pending record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("failed to find pending record: %w", gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) PendingRecordResolved(
+	topic string,
+	partition int32,
+	offset int64,
+) error {
+	// Not a struct condition, which would leave out partition and offset 0
+	err := r.db.
+		Where(map[string]any{"topic": topic, "partition": partition, "offset": offset}).
+		Delete(&PendingRecord{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to remove pending record: %w", err)
+	}
+
+	return nil
+}
+
+func (r *databaseStorer) PendingRecord(
+	id int64,
+) (PendingRecord, error) {
+	var pendingRecord PendingRecord
+	err := r.db.
+		Where(&PendingRecord{ID: id}).
+		First(&pendingRecord).Error
+	if err != nil {
+		return pendingRecord, fmt.Errorf("failed to find pending record: %w", err)
+	}
+
+	return pendingRecord, nil
+}
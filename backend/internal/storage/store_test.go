@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ var expectedProcess = Process{
 	Version:              1,
 	DeploymentTime:       time.Unix(1701235395, 0),
 	BpmnResource:         expectedBpmnResource,
+	Position:             1,
 }
 
 var expectedInstance = Instance{
@@ -35,20 +37,27 @@ var expectedInstance = Instance{
 		Time:  time.Unix(1701235595, 0),
 		Valid: true,
 	},
+	ParentProcessInstanceKey: -1,
+	ParentElementInstanceKey: -1,
+	Position:                 2,
 }
 
 var expectedVariable = Variable{
-	ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
+	ScopeKey:           expectedInstance.ProcessInstanceKey,
 	Name:               "testName",
+	ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
 	Value:              "testValue",
 	Time:               time.Unix(1701235496, 0),
+	Position:           3,
 }
 
 var expectedVariableUpdated = Variable{
-	ProcessInstanceKey: expectedVariable.ProcessInstanceKey,
+	ScopeKey:           expectedVariable.ScopeKey,
 	Name:               expectedVariable.Name,
+	ProcessInstanceKey: expectedVariable.ProcessInstanceKey,
 	Value:              "testValueUpdated",
 	Time:               time.Unix(1701235498, 0),
+	Position:           4,
 }
 
 var expectedIncident = Incident{
@@ -59,6 +68,7 @@ var expectedIncident = Incident{
 	ErrorMessage:       "Some message",
 	State:              "CREATED",
 	Time:               time.Unix(1701235496, 0),
+	Position:           5,
 }
 
 var expectedIncidentResolved = Incident{
@@ -69,6 +79,7 @@ var expectedIncidentResolved = Incident{
 	ErrorMessage:       expectedIncident.ErrorMessage,
 	State:              "RESOLVED",
 	Time:               time.Unix(1701235497, 0),
+	Position:           6,
 }
 
 var expectedAuditLog = AuditLog{
@@ -83,23 +94,36 @@ var expectedAuditLog = AuditLog{
 var expectedJob = Job{
 	Key:                1,
 	ElementID:          "test-job",
+	ElementInstanceKey: 5,
 	ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
 	Type:               "test-job",
 	Retries:            2,
 	Worker:             "a",
 	State:              "CREATED",
+	CustomHeaders:      `{"header":"value"}`,
 	Time:               time.Unix(1701235497, 0),
+	Position:           7,
 }
 
 var expectedJobUpdated = Job{
 	Key:                expectedJob.Key,
 	ElementID:          expectedJob.ElementID,
+	ElementInstanceKey: expectedJob.ElementInstanceKey,
 	ProcessInstanceKey: expectedJob.ProcessInstanceKey,
 	Type:               expectedJob.Type,
-	Retries:            3,
+	Retries:            1,
 	Worker:             "b",
-	State:              "COMPLETED",
-	Time:               time.Unix(1701235498, 0),
+	State:              "FAILED",
+	ErrorMessage:       "test error",
+	ErrorCode:          "TEST_ERROR",
+	Deadline: sql.NullTime{
+		Time:  time.Unix(1701235597, 0),
+		Valid: true,
+	},
+	RetryBackoff:  1000,
+	CustomHeaders: expectedJob.CustomHeaders,
+	Time:          time.Unix(1701235498, 0),
+	Position:      8,
 }
 
 func TestProcessDeployed(t *testing.T) {
@@ -113,6 +137,7 @@ func TestProcessDeployed(t *testing.T) {
 
 	t.Run("deploy process", func(t *testing.T) {
 		err := storer.ProcessDeployed(
+			expectedProcess.Position,
 			expectedProcess.ProcessDefinitionKey,
 			expectedProcess.BpmnProcessID,
 			expectedProcess.Version,
@@ -122,16 +147,17 @@ func TestProcessDeployed(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("deploy duplicate process", func(t *testing.T) {
-		// deploying the same process again should fail
+	t.Run("deploy process again", func(t *testing.T) {
+		// deploying the same process again should do nothing
 		err := storer.ProcessDeployed(
+			expectedProcess.Position,
 			expectedProcess.ProcessDefinitionKey,
 			expectedProcess.BpmnProcessID,
 			expectedProcess.Version,
 			expectedProcess.DeploymentTime,
 			bpmnResourceRaw,
 		)
-		assert.ErrorContains(t, err, "failed to create process")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -146,6 +172,262 @@ func TestProcessDeployed(t *testing.T) {
 	})
 }
 
+func TestDeploymentCreated(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedDeployment := Deployment{
+		DeploymentKey: 40,
+		Time:          time.Unix(1701235395, 0).UTC(),
+		Position:      1,
+	}
+	expectedResources := []DeploymentResource{
+		{DeploymentKey: 40, ResourceName: "test.bpmn", ResourceType: "BPMN", Size: 100},
+	}
+	expectedDefinitions := []DeployedDefinition{
+		{
+			DeploymentKey: 40,
+			DefinitionKey: expectedProcess.ProcessDefinitionKey,
+			Kind:          "PROCESS",
+			DefinitionID:  expectedProcess.BpmnProcessID,
+			Version:       expectedProcess.Version,
+			ResourceName:  "test.bpmn",
+			Duplicate:     true,
+		},
+	}
+
+	for _, name := range []string{"store deployment", "store deployment again"} {
+		t.Run(name, func(t *testing.T) {
+			// Deployment keys are filled in by the storer
+			resources := []DeploymentResource{expectedResources[0]}
+			resources[0].DeploymentKey = 0
+			definitions := []DeployedDefinition{expectedDefinitions[0]}
+			definitions[0].DeploymentKey = 0
+
+			err := storer.DeploymentCreated(
+				expectedDeployment.Position,
+				expectedDeployment.DeploymentKey,
+				resources,
+				definitions,
+				expectedDeployment.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var deployments []Deployment
+		err := db.Find(&deployments).Error
+		assert.NoError(t, err)
+		assert.Len(t, deployments, 1)
+
+		deployment := deployments[0]
+		deployment.Time = deployment.Time.UTC()
+		assert.Equal(t, expectedDeployment, deployment)
+
+		var resources []DeploymentResource
+		err = db.Find(&resources).Error
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResources, resources)
+
+		var definitions []DeployedDefinition
+		err = db.Find(&definitions).Error
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDefinitions, definitions)
+	})
+}
+
+func TestDecisionDeployed(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedDecisionRequirements := DecisionRequirements{
+		DecisionRequirementsKey: 20,
+		DecisionRequirementsID:  "test-drg",
+		Name:                    "Test DRG",
+		Version:                 1,
+		Namespace:               "http://camunda.org/schema/1.0/dmn",
+		ResourceName:            "test.dmn",
+		DeploymentTime:          time.Unix(1701235395, 0).UTC(),
+		Position:                1,
+	}
+	expectedDecision := Decision{
+		DecisionKey:             21,
+		DecisionID:              "test-decision",
+		Name:                    "Test decision",
+		Version:                 1,
+		DecisionRequirementsKey: expectedDecisionRequirements.DecisionRequirementsKey,
+		DecisionRequirementsID:  expectedDecisionRequirements.DecisionRequirementsID,
+		DeploymentTime:          expectedDecisionRequirements.DeploymentTime,
+		Position:                1,
+	}
+
+	for _, name := range []string{"deploy decision", "deploy decision again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.DecisionRequirementsDeployed(
+				expectedDecisionRequirements.Position,
+				expectedDecisionRequirements.DecisionRequirementsKey,
+				expectedDecisionRequirements.DecisionRequirementsID,
+				expectedDecisionRequirements.Name,
+				expectedDecisionRequirements.Version,
+				expectedDecisionRequirements.Namespace,
+				expectedDecisionRequirements.ResourceName,
+				expectedDecisionRequirements.DeploymentTime,
+				bpmnResourceRaw,
+			)
+			assert.NoError(t, err)
+
+			err = storer.DecisionDeployed(
+				expectedDecision.Position,
+				expectedDecision.DecisionKey,
+				expectedDecision.DecisionID,
+				expectedDecision.Name,
+				expectedDecision.Version,
+				expectedDecision.DecisionRequirementsKey,
+				expectedDecision.DecisionRequirementsID,
+				expectedDecision.DeploymentTime,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var decisionRequirements []DecisionRequirements
+		err := db.Find(&decisionRequirements).Error
+		assert.NoError(t, err)
+		assert.Len(t, decisionRequirements, 1)
+
+		drg := decisionRequirements[0]
+		drg.DeploymentTime = drg.DeploymentTime.UTC()
+		assert.Equal(t, expectedDecisionRequirements, drg)
+
+		var dmnResource DmnResource
+		err = db.First(&dmnResource).Error
+		assert.NoError(t, err)
+		assert.Equal(t, DmnResource{
+			DecisionRequirementsKey: expectedDecisionRequirements.DecisionRequirementsKey,
+			DmnFile:                 "Cg==",
+		}, dmnResource)
+
+		var decisions []Decision
+		err = db.Find(&decisions).Error
+		assert.NoError(t, err)
+		assert.Len(t, decisions, 1)
+
+		decision := decisions[0]
+		decision.DeploymentTime = decision.DeploymentTime.UTC()
+		assert.Equal(t, expectedDecision, decision)
+	})
+}
+
+func TestDecisionEvaluated(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedEvaluation := DecisionEvaluation{
+		Key:                     30,
+		DecisionKey:             21,
+		DecisionID:              "test-decision",
+		DecisionName:            "Test decision",
+		DecisionVersion:         1,
+		DecisionRequirementsKey: 20,
+		Output:                  `"approved"`,
+		ProcessInstanceKey:      expectedInstance.ProcessInstanceKey,
+		ProcessDefinitionKey:    expectedInstance.ProcessDefinitionKey,
+		ElementInstanceKey:      5,
+		ElementID:               "test-rule-task",
+		State:                   "EVALUATED",
+		Time:                    time.Unix(1701235496, 0).UTC(),
+	}
+	expectedDecisions := []EvaluatedDecision{
+		{
+			EvaluationKey:   expectedEvaluation.Key,
+			Index:           0,
+			DecisionKey:     22,
+			DecisionID:      "required-decision",
+			DecisionName:    "Required decision",
+			DecisionVersion: 1,
+			DecisionType:    "LITERAL_EXPRESSION",
+			Output:          "100",
+			Inputs:          "[]",
+			MatchedRules:    "[]",
+		},
+		{
+			EvaluationKey:   expectedEvaluation.Key,
+			Index:           1,
+			DecisionKey:     expectedEvaluation.DecisionKey,
+			DecisionID:      expectedEvaluation.DecisionID,
+			DecisionName:    expectedEvaluation.DecisionName,
+			DecisionVersion: expectedEvaluation.DecisionVersion,
+			DecisionType:    "DECISION_TABLE",
+			Output:          expectedEvaluation.Output,
+			Inputs:          `[{"inputId":"input-1","inputName":"amount","inputValue":"100"}]`,
+			MatchedRules:    `[{"ruleId":"rule-1","ruleIndex":1,"evaluatedOutputs":[]}]`,
+		},
+	}
+
+	for _, name := range []string{"store evaluation", "store evaluation again"} {
+		t.Run(name, func(t *testing.T) {
+			// Keys and indices are filled in by the storer
+			decisions := make([]EvaluatedDecision, len(expectedDecisions))
+			for i, decision := range expectedDecisions {
+				decision.EvaluationKey = 0
+				decision.Index = 0
+				decisions[i] = decision
+			}
+
+			err := storer.DecisionEvaluated(
+				2,
+				expectedEvaluation.Key,
+				expectedEvaluation.DecisionKey,
+				expectedEvaluation.DecisionID,
+				expectedEvaluation.DecisionName,
+				expectedEvaluation.DecisionVersion,
+				expectedEvaluation.DecisionRequirementsKey,
+				expectedEvaluation.Output,
+				expectedEvaluation.ProcessInstanceKey,
+				expectedEvaluation.ProcessDefinitionKey,
+				expectedEvaluation.ElementInstanceKey,
+				expectedEvaluation.ElementID,
+				expectedEvaluation.State,
+				expectedEvaluation.FailureMessage,
+				expectedEvaluation.FailedDecisionID,
+				decisions,
+				expectedEvaluation.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var evaluations []DecisionEvaluation
+		err := db.Find(&evaluations).Error
+		assert.NoError(t, err)
+		assert.Len(t, evaluations, 1)
+
+		evaluation := evaluations[0]
+		evaluation.Time = evaluation.Time.UTC()
+		assert.Equal(t, expectedEvaluation, evaluation)
+
+		var decisions []EvaluatedDecision
+		err = db.Order("evaluation_index ASC").Find(&decisions).Error
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDecisions, decisions)
+	})
+}
+
 func TestProcessInstanceActivated(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -156,34 +438,43 @@ func TestProcessInstanceActivated(t *testing.T) {
 
 	t.Run("activate instance", func(t *testing.T) {
 		err := storer.ProcessInstanceActivated(
+			expectedInstance.Position,
 			expectedInstance.ProcessInstanceKey,
 			expectedInstance.ProcessDefinitionKey,
 			expectedInstance.Version,
+			expectedInstance.ParentProcessInstanceKey,
+			expectedInstance.ParentElementInstanceKey,
 			expectedInstance.StartTime,
 		)
 		assert.NoError(t, err)
 	})
 
-	t.Run("activate duplicate instance", func(t *testing.T) {
-		// duplicate should fail again
+	t.Run("activate instance again", func(t *testing.T) {
+		// applying the same record again should do nothing
 		err := storer.ProcessInstanceActivated(
+			expectedInstance.Position,
 			expectedInstance.ProcessInstanceKey,
 			expectedInstance.ProcessDefinitionKey,
 			expectedInstance.Version,
+			expectedInstance.ParentProcessInstanceKey,
+			expectedInstance.ParentElementInstanceKey,
 			expectedInstance.StartTime,
 		)
-		assert.ErrorContains(t, err, "failed to create process instance")
+		assert.NoError(t, err)
 	})
 
-	t.Run("no such process", func(t *testing.T) {
-		// Use invalid process definition key
+	t.Run("older record ignored", func(t *testing.T) {
+		// A record from an earlier position mustn't overwrite the instance
 		err := storer.ProcessInstanceActivated(
+			expectedInstance.Position-1,
 			expectedInstance.ProcessInstanceKey,
 			expectedInstance.ProcessDefinitionKey+1,
-			expectedInstance.Version,
+			expectedInstance.Version+1,
+			expectedInstance.ParentProcessInstanceKey,
+			expectedInstance.ParentElementInstanceKey,
 			expectedInstance.StartTime,
 		)
-		assert.ErrorContains(t, err, "failed to create process instance")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -196,6 +487,9 @@ func TestProcessInstanceActivated(t *testing.T) {
 		assert.Equal(t, expectedInstance.Version, instance.Version)
 		assert.Equal(t, "ACTIVE", instance.Status)
 		assert.Equal(t, expectedInstance.StartTime.UTC(), instance.StartTime.UTC())
+		assert.Equal(t, expectedInstance.ParentProcessInstanceKey, instance.ParentProcessInstanceKey)
+		assert.Equal(t, expectedInstance.ParentElementInstanceKey, instance.ParentElementInstanceKey)
+		assert.Equal(t, expectedInstance.Position, instance.Position)
 	})
 }
 
@@ -209,15 +503,19 @@ func TestProcessInstanceCompleted(t *testing.T) {
 
 	// Activate instance
 	err := storer.ProcessInstanceActivated(
+		expectedInstance.Position,
 		expectedInstance.ProcessInstanceKey,
 		expectedInstance.ProcessDefinitionKey,
 		expectedInstance.Version,
+		expectedInstance.ParentProcessInstanceKey,
+		expectedInstance.ParentElementInstanceKey,
 		expectedInstance.StartTime,
 	)
 	assert.NoError(t, err)
 
 	t.Run("complete instance", func(t *testing.T) {
 		err := storer.ProcessInstanceCompleted(
+			expectedInstance.Position+1,
 			expectedInstance.ProcessInstanceKey,
 			expectedInstance.EndTime.Time,
 		)
@@ -227,6 +525,7 @@ func TestProcessInstanceCompleted(t *testing.T) {
 	t.Run("no such instance", func(t *testing.T) {
 		// Use invalid process instance key
 		err := storer.ProcessInstanceCompleted(
+			expectedInstance.Position+1,
 			expectedInstance.ProcessInstanceKey+1,
 			expectedInstance.EndTime.Time,
 		)
@@ -255,15 +554,19 @@ func TestProcessInstanceTerminated(t *testing.T) {
 
 	// Activate instance
 	err := storer.ProcessInstanceActivated(
+		expectedInstance.Position,
 		expectedInstance.ProcessInstanceKey,
 		expectedInstance.ProcessDefinitionKey,
 		expectedInstance.Version,
+		expectedInstance.ParentProcessInstanceKey,
+		expectedInstance.ParentElementInstanceKey,
 		expectedInstance.StartTime,
 	)
 	assert.NoError(t, err)
 
 	t.Run("terminate instance", func(t *testing.T) {
 		err := storer.ProcessInstanceTerminated(
+			expectedInstance.Position+1,
 			expectedInstance.ProcessInstanceKey,
 			expectedInstance.EndTime.Time,
 		)
@@ -273,6 +576,7 @@ func TestProcessInstanceTerminated(t *testing.T) {
 	t.Run("no such instance", func(t *testing.T) {
 		// Use invalid process instance key
 		err := storer.ProcessInstanceTerminated(
+			expectedInstance.Position+1,
 			expectedInstance.ProcessInstanceKey+1,
 			expectedInstance.EndTime.Time,
 		)
@@ -291,6 +595,96 @@ func TestProcessInstanceTerminated(t *testing.T) {
 	})
 }
 
+func TestElementInstanceLifecycle(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	startTime := time.Unix(1701235500, 0).UTC()
+	expectedElementInstance := ElementInstance{
+		Key:                600,
+		ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
+		ElementID:          "send-invoice",
+		ElementType:        "SERVICE_TASK",
+		FlowScopeKey:       expectedInstance.ProcessInstanceKey,
+		State:              "ELEMENT_ACTIVATING",
+		StartTime:          startTime,
+		Position:           40,
+	}
+
+	ensureElementInstance := func(t *testing.T, expected ElementInstance) {
+		var elementInstance ElementInstance
+		err := db.First(&elementInstance).Error
+		assert.NoError(t, err)
+
+		elementInstance.StartTime = elementInstance.StartTime.UTC()
+		elementInstance.EndTime.Time = elementInstance.EndTime.Time.UTC()
+		assert.Equal(t, expected, elementInstance)
+	}
+
+	t.Run("update missing element instance", func(t *testing.T) {
+		err := storer.ElementInstanceUpdated(41, expectedElementInstance.Key, "ELEMENT_ACTIVATED")
+		assert.ErrorContains(t, err, "failed to find element instance")
+		assert.True(t, IsNotFound(err))
+
+		err = storer.ElementInstanceEnded(41, expectedElementInstance.Key, "ELEMENT_COMPLETED", startTime)
+		assert.ErrorContains(t, err, "failed to find element instance")
+		assert.True(t, IsNotFound(err))
+	})
+
+	for _, name := range []string{"activate element", "activate again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.ElementInstanceActivating(
+				expectedElementInstance.Position,
+				expectedElementInstance.Key,
+				expectedElementInstance.ProcessInstanceKey,
+				expectedElementInstance.ElementID,
+				expectedElementInstance.ElementType,
+				expectedElementInstance.FlowScopeKey,
+				expectedElementInstance.StartTime,
+			)
+			assert.NoError(t, err)
+			ensureElementInstance(t, expectedElementInstance)
+		})
+	}
+
+	t.Run("element activated", func(t *testing.T) {
+		err := storer.ElementInstanceUpdated(41, expectedElementInstance.Key, "ELEMENT_ACTIVATED")
+		assert.NoError(t, err)
+
+		expected := expectedElementInstance
+		expected.State = "ELEMENT_ACTIVATED"
+		expected.Position = 41
+		ensureElementInstance(t, expected)
+	})
+
+	endTime := startTime.Add(time.Minute)
+	t.Run("element completed", func(t *testing.T) {
+		err := storer.ElementInstanceEnded(43, expectedElementInstance.Key, "ELEMENT_COMPLETED", endTime)
+		assert.NoError(t, err)
+
+		expected := expectedElementInstance
+		expected.State = "ELEMENT_COMPLETED"
+		expected.EndTime = sql.NullTime{Time: endTime, Valid: true}
+		expected.Position = 43
+		ensureElementInstance(t, expected)
+	})
+
+	t.Run("update with older record", func(t *testing.T) {
+		err := storer.ElementInstanceUpdated(42, expectedElementInstance.Key, "ELEMENT_COMPLETING")
+		assert.NoError(t, err)
+
+		expected := expectedElementInstance
+		expected.State = "ELEMENT_COMPLETED"
+		expected.EndTime = sql.NullTime{Time: endTime, Valid: true}
+		expected.Position = 43
+		ensureElementInstance(t, expected)
+	})
+}
+
 func TestVariableCreated(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -301,7 +695,9 @@ func TestVariableCreated(t *testing.T) {
 
 	t.Run("create variable", func(t *testing.T) {
 		err := storer.VariableCreated(
+			expectedVariable.Position,
 			expectedVariable.ProcessInstanceKey,
+			expectedVariable.ScopeKey,
 			expectedVariable.Name,
 			expectedVariable.Value,
 			expectedVariable.Time,
@@ -309,14 +705,16 @@ func TestVariableCreated(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("create duplicate", func(t *testing.T) {
+	t.Run("create again", func(t *testing.T) {
 		err := storer.VariableCreated(
+			expectedVariable.Position,
 			expectedVariable.ProcessInstanceKey,
+			expectedVariable.ScopeKey,
 			expectedVariable.Name,
 			expectedVariable.Value,
 			expectedVariable.Time,
 		)
-		assert.ErrorContains(t, err, "failed to create variable")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -325,6 +723,7 @@ func TestVariableCreated(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, expectedVariable.ProcessInstanceKey, variable.ProcessInstanceKey)
+		assert.Equal(t, expectedVariable.ScopeKey, variable.ScopeKey)
 		assert.Equal(t, expectedVariable.Name, variable.Name)
 		assert.Equal(t, expectedVariable.Value, variable.Value)
 		assert.Equal(t, expectedVariable.Time.UTC(), variable.Time.UTC())
@@ -341,7 +740,9 @@ func TestVariableUpdated(t *testing.T) {
 
 	// Create variable so we can update it
 	err := storer.VariableCreated(
+		expectedVariable.Position,
 		expectedVariable.ProcessInstanceKey,
+		expectedVariable.ScopeKey,
 		expectedVariable.Name,
 		expectedVariable.Value,
 		expectedVariable.Time,
@@ -350,7 +751,9 @@ func TestVariableUpdated(t *testing.T) {
 
 	t.Run("update variable", func(t *testing.T) {
 		err := storer.VariableUpdated(
+			expectedVariableUpdated.Position,
 			expectedVariableUpdated.ProcessInstanceKey,
+			expectedVariableUpdated.ScopeKey,
 			expectedVariableUpdated.Name,
 			expectedVariableUpdated.Value,
 			expectedVariableUpdated.Time,
@@ -360,7 +763,9 @@ func TestVariableUpdated(t *testing.T) {
 
 	t.Run("no such variable", func(t *testing.T) {
 		err := storer.VariableUpdated(
+			expectedVariableUpdated.Position,
 			expectedVariableUpdated.ProcessInstanceKey,
+			expectedVariableUpdated.ScopeKey,
 			"invalidTestName",
 			expectedVariableUpdated.Value,
 			expectedVariableUpdated.Time,
@@ -380,6 +785,123 @@ func TestVariableUpdated(t *testing.T) {
 	})
 }
 
+func TestVariableScopes(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	instanceKey := expectedVariable.ProcessInstanceKey
+	localScopeKey := instanceKey + 100
+
+	err := storer.VariableCreated(1, instanceKey, instanceKey, "name", "global", time.Unix(1, 0))
+	assert.NoError(t, err)
+
+	t.Run("local variable with the same name", func(t *testing.T) {
+		err := storer.VariableCreated(2, instanceKey, localScopeKey, "name", "local", time.Unix(2, 0))
+		assert.NoError(t, err)
+
+		err = storer.VariableUpdated(3, instanceKey, localScopeKey, "name", "local updated", time.Unix(3, 0))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ensure both values", func(t *testing.T) {
+		var variables []Variable
+		err := db.Order("scope_key ASC").Find(&variables).Error
+		assert.NoError(t, err)
+
+		assert.Len(t, variables, 2)
+		assert.Equal(t, instanceKey, variables[0].ScopeKey)
+		assert.Equal(t, "global", variables[0].Value)
+		assert.Equal(t, localScopeKey, variables[1].ScopeKey)
+		assert.Equal(t, "local updated", variables[1].Value)
+	})
+}
+
+func TestVariableChanged(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedChanges := []VariableChange{
+		{
+			PartitionID:        1,
+			Position:           expectedVariable.Position,
+			ProcessInstanceKey: expectedVariable.ProcessInstanceKey,
+			ScopeKey:           expectedVariable.ScopeKey,
+			Name:               expectedVariable.Name,
+			Intent:             "CREATED",
+			Value:              expectedVariable.Value,
+			Time:               expectedVariable.Time.UTC(),
+		},
+		{
+			PartitionID:        1,
+			Position:           expectedVariableUpdated.Position,
+			ProcessInstanceKey: expectedVariableUpdated.ProcessInstanceKey,
+			ScopeKey:           expectedVariableUpdated.ScopeKey,
+			Name:               expectedVariableUpdated.Name,
+			Intent:             "UPDATED",
+			Value:              expectedVariableUpdated.Value,
+			Time:               expectedVariableUpdated.Time.UTC(),
+		},
+	}
+
+	// Changes are added again when records are applied again
+	for _, name := range []string{"add changes", "add changes again"} {
+		t.Run(name, func(t *testing.T) {
+			for _, change := range expectedChanges {
+				err := storer.VariableChanged(
+					change.PartitionID,
+					change.Position,
+					change.ProcessInstanceKey,
+					change.ScopeKey,
+					change.Name,
+					change.Intent,
+					change.Value,
+					change.Time,
+				)
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("ensure equal values", func(t *testing.T) {
+		var changes []VariableChange
+		err := db.Order("position ASC").Find(&changes).Error
+		assert.NoError(t, err)
+
+		for i := range changes {
+			changes[i].Time = changes[i].Time.UTC()
+		}
+		assert.Equal(t, expectedChanges, changes)
+	})
+
+	t.Run("same position in another partition", func(t *testing.T) {
+		change := expectedChanges[0]
+		err := storer.VariableChanged(
+			2,
+			change.Position,
+			change.ProcessInstanceKey+1,
+			change.ScopeKey+1,
+			change.Name,
+			change.Intent,
+			change.Value,
+			change.Time,
+		)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&VariableChange{}).Where("position = ?", change.Position).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
 func TestIncidentCreated(t *testing.T) {
 	testDb := newMigratedTestDB(t)
 	defer func() {
@@ -390,6 +912,7 @@ func TestIncidentCreated(t *testing.T) {
 
 	t.Run("create incident", func(t *testing.T) {
 		err := storer.IncidentCreated(
+			expectedIncident.Position,
 			expectedIncident.Key,
 			expectedIncident.ProcessInstanceKey,
 			expectedIncident.ElementID,
@@ -400,8 +923,9 @@ func TestIncidentCreated(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("create duplicate", func(t *testing.T) {
+	t.Run("create again", func(t *testing.T) {
 		err := storer.IncidentCreated(
+			expectedIncident.Position,
 			expectedIncident.Key,
 			expectedIncident.ProcessInstanceKey,
 			expectedIncident.ElementID,
@@ -409,7 +933,7 @@ func TestIncidentCreated(t *testing.T) {
 			expectedIncident.ErrorMessage,
 			expectedIncident.Time,
 		)
-		assert.ErrorContains(t, err, "failed to create incident")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -436,6 +960,7 @@ func TestIncidentResolved(t *testing.T) {
 
 	// Create incident to resolve it
 	err := storer.IncidentCreated(
+		expectedIncident.Position,
 		expectedIncident.Key,
 		expectedIncident.ProcessInstanceKey,
 		expectedIncident.ElementID,
@@ -447,6 +972,7 @@ func TestIncidentResolved(t *testing.T) {
 
 	t.Run("resolve incident", func(t *testing.T) {
 		err := storer.IncidentResolved(
+			expectedIncidentResolved.Position,
 			expectedIncidentResolved.Key,
 			expectedIncidentResolved.Time,
 		)
@@ -455,6 +981,7 @@ func TestIncidentResolved(t *testing.T) {
 
 	t.Run("no such incident", func(t *testing.T) {
 		err := storer.IncidentResolved(
+			expectedIncidentResolved.Position,
 			expectedIncidentResolved.Key+1,
 			expectedIncidentResolved.Time,
 		)
@@ -495,7 +1022,7 @@ func TestAuditLogEventOccurred(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("duplicate event", func(t *testing.T) {
+	t.Run("event again", func(t *testing.T) {
 		err := storer.AuditLogEventOccurred(
 			expectedAuditLog.Position,
 			expectedAuditLog.ProcessInstanceKey,
@@ -504,7 +1031,7 @@ func TestAuditLogEventOccurred(t *testing.T) {
 			expectedAuditLog.Intent,
 			expectedAuditLog.Time,
 		)
-		assert.ErrorContains(t, err, "failed to add to audit log")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -531,28 +1058,34 @@ func TestJobCreated(t *testing.T) {
 
 	t.Run("create job", func(t *testing.T) {
 		err := storer.JobCreated(
+			expectedJob.Position,
 			expectedJob.Key,
 			expectedJob.ElementID,
+			expectedJob.ElementInstanceKey,
 			expectedJob.ProcessInstanceKey,
 			expectedJob.Type,
 			expectedJob.Retries,
 			expectedJob.Worker,
+			expectedJob.CustomHeaders,
 			expectedJob.Time,
 		)
 		assert.NoError(t, err)
 	})
 
-	t.Run("create duplicate", func(t *testing.T) {
+	t.Run("create again", func(t *testing.T) {
 		err := storer.JobCreated(
+			expectedJob.Position,
 			expectedJob.Key,
 			expectedJob.ElementID,
+			expectedJob.ElementInstanceKey,
 			expectedJob.ProcessInstanceKey,
 			expectedJob.Type,
 			expectedJob.Retries,
 			expectedJob.Worker,
+			expectedJob.CustomHeaders,
 			expectedJob.Time,
 		)
-		assert.ErrorContains(t, err, "failed to create job")
+		assert.NoError(t, err)
 	})
 
 	t.Run("ensure equal value", func(t *testing.T) {
@@ -563,10 +1096,13 @@ func TestJobCreated(t *testing.T) {
 		assert.Equal(t, expectedJob.Key, job.Key)
 		assert.Equal(t, expectedJob.ElementID, job.ElementID)
 		assert.Equal(t, expectedJob.ProcessInstanceKey, job.ProcessInstanceKey)
+		assert.Equal(t, expectedJob.ElementInstanceKey, job.ElementInstanceKey)
 		assert.Equal(t, expectedJob.Type, job.Type)
 		assert.Equal(t, expectedJob.Retries, job.Retries)
 		assert.Equal(t, expectedJob.Worker, job.Worker)
 		assert.Equal(t, expectedJob.State, job.State)
+		assert.Equal(t, expectedJob.CustomHeaders, job.CustomHeaders)
+		assert.False(t, job.Deadline.Valid)
 		assert.Equal(t, expectedJob.Time.UTC(), job.Time.UTC())
 	})
 }
@@ -581,22 +1117,30 @@ func TestJobUpdated(t *testing.T) {
 
 	// Create job to update it
 	err := storer.JobCreated(
+		expectedJob.Position,
 		expectedJob.Key,
 		expectedJob.ElementID,
+		expectedJob.ElementInstanceKey,
 		expectedJob.ProcessInstanceKey,
 		expectedJob.Type,
 		expectedJob.Retries,
 		expectedJob.Worker,
+		expectedJob.CustomHeaders,
 		expectedJob.Time,
 	)
 	assert.NoError(t, err)
 
 	t.Run("update job", func(t *testing.T) {
 		err := storer.JobUpdated(
+			expectedJobUpdated.Position,
 			expectedJobUpdated.Key,
 			expectedJobUpdated.Retries,
 			expectedJobUpdated.Worker,
 			expectedJobUpdated.State,
+			expectedJobUpdated.ErrorMessage,
+			expectedJobUpdated.ErrorCode,
+			expectedJobUpdated.Deadline,
+			expectedJobUpdated.RetryBackoff,
 			expectedJobUpdated.Time,
 		)
 		assert.NoError(t, err)
@@ -604,15 +1148,37 @@ func TestJobUpdated(t *testing.T) {
 
 	t.Run("no such job", func(t *testing.T) {
 		err := storer.JobUpdated(
+			expectedJobUpdated.Position,
 			expectedJobUpdated.Key+1,
 			expectedJobUpdated.Retries,
 			expectedJobUpdated.Worker,
 			expectedJobUpdated.State,
+			expectedJobUpdated.ErrorMessage,
+			expectedJobUpdated.ErrorCode,
+			expectedJobUpdated.Deadline,
+			expectedJobUpdated.RetryBackoff,
 			expectedJobUpdated.Time,
 		)
 		assert.ErrorContains(t, err, "failed to find job")
 	})
 
+	t.Run("older update ignored", func(t *testing.T) {
+		// An update from an earlier position mustn't overwrite a newer one
+		err := storer.JobUpdated(
+			expectedJobUpdated.Position-1,
+			expectedJobUpdated.Key,
+			expectedJobUpdated.Retries+1,
+			expectedJobUpdated.Worker,
+			"FAILED",
+			expectedJobUpdated.ErrorMessage,
+			expectedJobUpdated.ErrorCode,
+			expectedJobUpdated.Deadline,
+			expectedJobUpdated.RetryBackoff,
+			expectedJobUpdated.Time,
+		)
+		assert.NoError(t, err)
+	})
+
 	t.Run("ensure equal value", func(t *testing.T) {
 		var job Job
 		err := db.First(&job).Error
@@ -625,6 +1191,1154 @@ func TestJobUpdated(t *testing.T) {
 		assert.Equal(t, expectedJobUpdated.Retries, job.Retries)
 		assert.Equal(t, expectedJobUpdated.Worker, job.Worker)
 		assert.Equal(t, expectedJobUpdated.State, job.State)
+		assert.Equal(t, expectedJobUpdated.ErrorMessage, job.ErrorMessage)
+		assert.Equal(t, expectedJobUpdated.ErrorCode, job.ErrorCode)
+		assert.Equal(t, expectedJobUpdated.Deadline.Time.UTC(), job.Deadline.Time.UTC())
+		assert.Equal(t, expectedJobUpdated.RetryBackoff, job.RetryBackoff)
+		assert.Equal(t, expectedJobUpdated.CustomHeaders, job.CustomHeaders)
 		assert.Equal(t, expectedJobUpdated.Time.UTC(), job.Time.UTC())
+		assert.Equal(t, expectedJobUpdated.Position, job.Position)
+	})
+}
+
+func TestJobEventOccurred(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedEvent := JobEvent{
+		PartitionID:        1,
+		Position:           expectedJobUpdated.Position,
+		JobKey:             expectedJobUpdated.Key,
+		ProcessInstanceKey: expectedJobUpdated.ProcessInstanceKey,
+		Intent:             expectedJobUpdated.State,
+		Retries:            expectedJobUpdated.Retries,
+		Worker:             expectedJobUpdated.Worker,
+		ErrorMessage:       expectedJobUpdated.ErrorMessage,
+		ErrorCode:          expectedJobUpdated.ErrorCode,
+		RetryBackoff:       expectedJobUpdated.RetryBackoff,
+		Time:               expectedJobUpdated.Time.UTC(),
+	}
+
+	for _, name := range []string{"add event", "add event again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.JobEventOccurred(
+				expectedEvent.PartitionID,
+				expectedEvent.Position,
+				expectedEvent.JobKey,
+				expectedEvent.ProcessInstanceKey,
+				expectedEvent.Intent,
+				expectedEvent.Retries,
+				expectedEvent.Worker,
+				expectedEvent.ErrorMessage,
+				expectedEvent.ErrorCode,
+				expectedEvent.RetryBackoff,
+				expectedEvent.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var events []JobEvent
+		err := db.Find(&events).Error
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+
+		event := events[0]
+		event.Time = event.Time.UTC()
+		assert.Equal(t, expectedEvent, event)
+	})
+
+	t.Run("same position in another partition", func(t *testing.T) {
+		err := storer.JobEventOccurred(
+			2,
+			expectedEvent.Position,
+			expectedEvent.JobKey+1,
+			expectedEvent.ProcessInstanceKey+1,
+			expectedEvent.Intent,
+			expectedEvent.Retries,
+			expectedEvent.Worker,
+			expectedEvent.ErrorMessage,
+			expectedEvent.ErrorCode,
+			expectedEvent.RetryBackoff,
+			expectedEvent.Time,
+		)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&JobEvent{}).Where("position = ?", expectedEvent.Position).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestJobBatchOccurred(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedBatch := JobBatch{
+		PartitionID:       1,
+		Position:          500,
+		Worker:            expectedJobUpdated.Worker,
+		Type:              expectedJobUpdated.Type,
+		Intent:            "ACTIVATED",
+		Timeout:           30000,
+		MaxJobsToActivate: 32,
+		JobCount:          2,
+		Truncated:         false,
+		Time:              time.Unix(1701235499, 0).UTC(),
+	}
+
+	for _, name := range []string{"store batch", "store batch again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.JobBatchOccurred(
+				expectedBatch.PartitionID,
+				expectedBatch.Position,
+				expectedBatch.Worker,
+				expectedBatch.Type,
+				expectedBatch.Intent,
+				expectedBatch.Timeout,
+				expectedBatch.MaxJobsToActivate,
+				expectedBatch.JobCount,
+				expectedBatch.Truncated,
+				expectedBatch.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var batches []JobBatch
+		err := db.Find(&batches).Error
+		assert.NoError(t, err)
+		assert.Len(t, batches, 1)
+
+		batch := batches[0]
+		batch.Time = batch.Time.UTC()
+		assert.Equal(t, expectedBatch, batch)
+	})
+
+	t.Run("same position in another partition", func(t *testing.T) {
+		err := storer.JobBatchOccurred(
+			2,
+			expectedBatch.Position,
+			expectedBatch.Worker,
+			expectedBatch.Type,
+			expectedBatch.Intent,
+			expectedBatch.Timeout,
+			expectedBatch.MaxJobsToActivate,
+			expectedBatch.JobCount,
+			expectedBatch.Truncated,
+			expectedBatch.Time,
+		)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&JobBatch{}).Where("position = ?", expectedBatch.Position).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestOffsetCommitted(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	t.Run("no committed offset", func(t *testing.T) {
+		_, ok, err := storer.LastCommittedOffset("zeebe-job", 0)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("commit offset", func(t *testing.T) {
+		err := storer.OffsetCommitted("zeebe-job", 0, 10)
+		assert.NoError(t, err)
+
+		offset, ok, err := storer.LastCommittedOffset("zeebe-job", 0)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(10), offset)
+	})
+
+	t.Run("commit newer offset", func(t *testing.T) {
+		err := storer.OffsetCommitted("zeebe-job", 0, 11)
+		assert.NoError(t, err)
+
+		offset, ok, err := storer.LastCommittedOffset("zeebe-job", 0)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(11), offset)
+	})
+
+	t.Run("partitions are separate", func(t *testing.T) {
+		_, ok, err := storer.LastCommittedOffset("zeebe-job", 1)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		_, ok, err = storer.LastCommittedOffset("zeebe-variable", 0)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("partition 0 is separate", func(t *testing.T) {
+		err := storer.OffsetCommitted("zeebe-variable", 1, 500)
+		assert.NoError(t, err)
+
+		_, ok, err := storer.LastCommittedOffset("zeebe-variable", 0)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDeadLetterStored(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	payload := []byte(`{"valueType": "JOB"}`)
+
+	t.Run("store dead letter", func(t *testing.T) {
+		err := storer.DeadLetterStored("zeebe-job", 1, 20, payload,
+			"first error", time.Unix(1701235495, 0))
+		assert.NoError(t, err)
+	})
+
+	t.Run("store same dead letter again", func(t *testing.T) {
+		err := storer.DeadLetterStored("zeebe-job", 1, 20, payload,
+			"second error", time.Unix(1701235496, 0))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var deadLetters []DeadLetter
+		err := db.Find(&deadLetters).Error
+		assert.NoError(t, err)
+		assert.Len(t, deadLetters, 1)
+
+		deadLetter := deadLetters[0]
+		assert.Equal(t, "zeebe-job", deadLetter.Topic)
+		assert.Equal(t, int32(1), deadLetter.Partition)
+		assert.Equal(t, int64(20), deadLetter.Offset)
+		assert.Equal(t, payload, deadLetter.Payload)
+		assert.Equal(t, "second error", deadLetter.Error)
+		assert.Equal(t, int64(2), deadLetter.Attempts)
+		assert.Equal(t, time.Unix(1701235496, 0).UTC(), deadLetter.Time.UTC())
+
+		found, err := storer.DeadLetter(deadLetter.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, deadLetter.ID, found.ID)
+	})
+
+	t.Run("dead letters to retry", func(t *testing.T) {
+		err := storer.DeadLetterStored("zeebe-job", 1, 21, payload,
+			"error", time.Unix(1701235497, 0))
+		assert.NoError(t, err)
+
+		deadLetters, err := storer.DeadLettersToRetry(2)
+		assert.NoError(t, err)
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, int64(21), deadLetters[0].Offset)
+
+		deadLetters, err = storer.DeadLettersToRetry(3)
+		assert.NoError(t, err)
+		assert.Len(t, deadLetters, 2)
+	})
+
+	t.Run("no such dead letter", func(t *testing.T) {
+		_, err := storer.DeadLetter(1000)
+		assert.ErrorContains(t, err, "failed to find dead letter")
+	})
+}
+
+func TestDeadLetterDiscarded(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	err := storer.DeadLetterStored("zeebe-job", 1, 20, []byte(`{}`),
+		"error", time.Unix(1701235495, 0))
+	assert.NoError(t, err)
+
+	var deadLetter DeadLetter
+	err = db.First(&deadLetter).Error
+	assert.NoError(t, err)
+
+	t.Run("discard dead letter", func(t *testing.T) {
+		err := storer.DeadLetterDiscarded(deadLetter.ID)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&DeadLetter{}).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("no such dead letter", func(t *testing.T) {
+		err := storer.DeadLetterDiscarded(deadLetter.ID)
+		assert.ErrorContains(t, err, "failed to find dead letter")
+	})
+}
+
+func TestPendingRecordStored(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	payload := []byte(`{"valueType": "JOB"}`)
+
+	t.Run("store pending record", func(t *testing.T) {
+		err := storer.PendingRecordStored("zeebe-job", 1, 20, payload,
+			expectedJob.Key, time.Unix(1701235495, 0))
+		assert.NoError(t, err)
+	})
+
+	t.Run("store same pending record again", func(t *testing.T) {
+		err := storer.PendingRecordStored("zeebe-job", 1, 20, payload,
+			expectedJob.Key, time.Unix(1701235496, 0))
+		assert.NoError(t, err)
+	})
+
+	t.Run("pending records for key", func(t *testing.T) {
+		err := storer.PendingRecordStored("zeebe-job", 1, 21, payload,
+			expectedJob.Key+1, time.Unix(1701235497, 0))
+		assert.NoError(t, err)
+
+		pendingRecords, err := storer.PendingRecords(expectedJob.Key)
+		assert.NoError(t, err)
+		assert.Len(t, pendingRecords, 1)
+
+		pendingRecord := pendingRecords[0]
+		assert.Equal(t, "zeebe-job", pendingRecord.Topic)
+		assert.Equal(t, int32(1), pendingRecord.Partition)
+		assert.Equal(t, int64(20), pendingRecord.Offset)
+		assert.Equal(t, payload, pendingRecord.Payload)
+		assert.Equal(t, expectedJob.Key, pendingRecord.MissingKey)
+		assert.Equal(t, time.Unix(1701235495, 0).UTC(), pendingRecord.Time.UTC())
+	})
+
+	t.Run("remove pending record", func(t *testing.T) {
+		pendingRecords, err := storer.PendingRecords(expectedJob.Key)
+		assert.NoError(t, err)
+		assert.Len(t, pendingRecords, 1)
+
+		err = storer.PendingRecordRemoved(pendingRecords[0].ID)
+		assert.NoError(t, err)
+
+		pendingRecords, err = storer.PendingRecords(expectedJob.Key)
+		assert.NoError(t, err)
+		assert.Empty(t, pendingRecords)
+	})
+
+	t.Run("no such pending record", func(t *testing.T) {
+		err := storer.PendingRecordRemoved(1000)
+		assert.ErrorContains(t, err, "failed to find pending record")
+
+		_, err = storer.PendingRecord(1000)
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("resolve pending record", func(t *testing.T) {
+		pendingRecords, err := storer.PendingRecords(expectedJob.Key + 1)
+		assert.NoError(t, err)
+		assert.Len(t, pendingRecords, 1)
+
+		pendingRecord, err := storer.PendingRecord(pendingRecords[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(21), pendingRecord.Offset)
+
+		err = storer.PendingRecordResolved("zeebe-job", 1, 21)
+		assert.NoError(t, err)
+
+		// Resolving a record that isn't pending does nothing
+		err = storer.PendingRecordResolved("zeebe-job", 1, 21)
+		assert.NoError(t, err)
+
+		pendingRecords, err = storer.PendingRecords(expectedJob.Key + 1)
+		assert.NoError(t, err)
+		assert.Empty(t, pendingRecords)
+	})
+
+	t.Run("resolve pending record in partition 0", func(t *testing.T) {
+		for partition := int32(0); partition < 3; partition++ {
+			for offset := int64(0); offset < 2; offset++ {
+				err := storer.PendingRecordStored("zeebe-process-instance", partition, offset, []byte("{}"), 100, time.Now())
+				assert.NoError(t, err)
+			}
+		}
+
+		err := storer.PendingRecordResolved("zeebe-process-instance", 0, 0)
+		assert.NoError(t, err)
+
+		pendingRecords, err := storer.PendingRecords(100)
+		assert.NoError(t, err)
+		assert.Len(t, pendingRecords, 5)
+		for _, pendingRecord := range pendingRecords {
+			assert.False(t, pendingRecord.Partition == 0 && pendingRecord.Offset == 0)
+		}
+	})
+}
+
+func TestCommandRejected(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedRejection := Rejection{
+		PartitionID:        1,
+		Position:           200,
+		Key:                expectedJob.Key,
+		ProcessInstanceKey: expectedJob.ProcessInstanceKey,
+		ElementID:          expectedJob.ElementID,
+		ValueType:          "JOB",
+		Intent:             "COMPLETE",
+		RejectionType:      "NOT_FOUND",
+		RejectionReason:    "Expected to find the job, but it doesn't exist",
+		Time:               time.Unix(1701235499, 0).UTC(),
+	}
+
+	for _, name := range []string{"store rejection", "store rejection again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.CommandRejected(
+				expectedRejection.PartitionID,
+				expectedRejection.Position,
+				expectedRejection.Key,
+				expectedRejection.ProcessInstanceKey,
+				expectedRejection.ElementID,
+				expectedRejection.ValueType,
+				expectedRejection.Intent,
+				expectedRejection.RejectionType,
+				expectedRejection.RejectionReason,
+				expectedRejection.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var rejections []Rejection
+		err := db.Find(&rejections).Error
+		assert.NoError(t, err)
+		assert.Len(t, rejections, 1)
+
+		rejection := rejections[0]
+		rejection.Time = rejection.Time.UTC()
+		assert.Equal(t, expectedRejection, rejection)
+	})
+
+	t.Run("same position in another partition", func(t *testing.T) {
+		err := storer.CommandRejected(
+			2,
+			expectedRejection.Position,
+			expectedRejection.Key,
+			expectedRejection.ProcessInstanceKey,
+			expectedRejection.ElementID,
+			expectedRejection.ValueType,
+			expectedRejection.Intent,
+			expectedRejection.RejectionType,
+			expectedRejection.RejectionReason,
+			expectedRejection.Time,
+		)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&Rejection{}).Where("position = ?", expectedRejection.Position).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestMessageLifecycle(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	publishTime := time.Unix(1701235500, 0).UTC()
+	expectedMessage := Message{
+		Key:            300,
+		Name:           "payment-received",
+		CorrelationKey: "order-1",
+		MessageID:      "message-id",
+		TimeToLive:     60000,
+		Variables:      `{"amount":10}`,
+		State:          "PUBLISHED",
+		PublishTime:    publishTime,
+		Time:           publishTime,
+		Position:       10,
+	}
+
+	ensureMessage := func(t *testing.T, expected Message) {
+		var message Message
+		err := db.First(&message).Error
+		assert.NoError(t, err)
+
+		message.PublishTime = message.PublishTime.UTC()
+		message.Time = message.Time.UTC()
+		assert.Equal(t, expected, message)
+	}
+
+	t.Run("expire missing message", func(t *testing.T) {
+		err := storer.MessageExpired(11, expectedMessage.Key, publishTime)
+		assert.ErrorContains(t, err, "failed to find message")
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("correlate missing message", func(t *testing.T) {
+		err := storer.MessageCorrelated(11, expectedMessage.Key, publishTime)
+		assert.ErrorContains(t, err, "failed to find message")
+		assert.True(t, IsNotFound(err))
+	})
+
+	for _, name := range []string{"publish message", "publish again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.MessagePublished(
+				expectedMessage.Position,
+				expectedMessage.Key,
+				expectedMessage.Name,
+				expectedMessage.CorrelationKey,
+				expectedMessage.MessageID,
+				expectedMessage.TimeToLive,
+				expectedMessage.Variables,
+				expectedMessage.PublishTime,
+			)
+			assert.NoError(t, err)
+			ensureMessage(t, expectedMessage)
+		})
+	}
+
+	correlatedTime := publishTime.Add(time.Second)
+	t.Run("correlate message", func(t *testing.T) {
+		err := storer.MessageCorrelated(12, expectedMessage.Key, correlatedTime)
+		assert.NoError(t, err)
+
+		expected := expectedMessage
+		expected.State = "CORRELATED"
+		expected.Time = correlatedTime
+		expected.Position = 12
+		ensureMessage(t, expected)
+	})
+
+	t.Run("correlate with older record", func(t *testing.T) {
+		err := storer.MessageCorrelated(11, expectedMessage.Key, publishTime)
+		assert.NoError(t, err)
+
+		expected := expectedMessage
+		expected.State = "CORRELATED"
+		expected.Time = correlatedTime
+		expected.Position = 12
+		ensureMessage(t, expected)
+	})
+
+	t.Run("expire with older record", func(t *testing.T) {
+		err := storer.MessageExpired(11, expectedMessage.Key, publishTime)
+		assert.NoError(t, err)
+
+		expected := expectedMessage
+		expected.State = "CORRELATED"
+		expected.Time = correlatedTime
+		expected.Position = 12
+		ensureMessage(t, expected)
+	})
+
+	expiredTime := publishTime.Add(time.Minute)
+	t.Run("expire message", func(t *testing.T) {
+		err := storer.MessageExpired(13, expectedMessage.Key, expiredTime)
+		assert.NoError(t, err)
+
+		expected := expectedMessage
+		expected.State = "EXPIRED"
+		expected.Time = expiredTime
+		expected.Position = 13
+		ensureMessage(t, expected)
+	})
+}
+
+func TestMessageSubscriptionUpdated(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	subscriptionTime := time.Unix(1701235500, 0).UTC()
+	expectedSubscription := MessageSubscription{
+		ElementInstanceKey: 400,
+		MessageName:        "payment-received",
+		ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
+		ElementID:          "wait-for-payment",
+		CorrelationKey:     "order-1",
+		MessageKey:         0,
+		Interrupting:       true,
+		State:              "CREATED",
+		Time:               subscriptionTime,
+		Position:           20,
+	}
+
+	ensureSubscription := func(t *testing.T, expected MessageSubscription) {
+		var subscriptions []MessageSubscription
+		err := db.Find(&subscriptions).Error
+		assert.NoError(t, err)
+		assert.Len(t, subscriptions, 1)
+
+		subscription := subscriptions[0]
+		subscription.Time = subscription.Time.UTC()
+		assert.Equal(t, expected, subscription)
+	}
+
+	update := func(position int64, elementID string, messageKey int64, state string) error {
+		return storer.MessageSubscriptionUpdated(
+			position,
+			expectedSubscription.ProcessInstanceKey,
+			expectedSubscription.ElementInstanceKey,
+			elementID,
+			expectedSubscription.MessageName,
+			expectedSubscription.CorrelationKey,
+			messageKey,
+			expectedSubscription.Interrupting,
+			state,
+			subscriptionTime,
+		)
+	}
+
+	t.Run("create subscription", func(t *testing.T) {
+		err := update(20, expectedSubscription.ElementID, 0, "CREATED")
+		assert.NoError(t, err)
+		ensureSubscription(t, expectedSubscription)
+	})
+
+	t.Run("correlate without element id", func(t *testing.T) {
+		err := update(21, "", 300, "CORRELATING")
+		assert.NoError(t, err)
+
+		expected := expectedSubscription
+		expected.MessageKey = 300
+		expected.State = "CORRELATING"
+		expected.Position = 21
+		ensureSubscription(t, expected)
+	})
+
+	t.Run("update with older record", func(t *testing.T) {
+		err := update(20, expectedSubscription.ElementID, 0, "CREATED")
+		assert.NoError(t, err)
+
+		expected := expectedSubscription
+		expected.MessageKey = 300
+		expected.State = "CORRELATING"
+		expected.Position = 21
+		ensureSubscription(t, expected)
+	})
+
+	t.Run("delete without message key", func(t *testing.T) {
+		err := update(22, expectedSubscription.ElementID, 0, "DELETED")
+		assert.NoError(t, err)
+
+		expected := expectedSubscription
+		expected.MessageKey = 300
+		expected.State = "DELETED"
+		expected.Position = 22
+		ensureSubscription(t, expected)
+	})
+}
+
+func TestTimerLifecycle(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	createdTime := time.Unix(1701235500, 0).UTC()
+	expectedTimer := Timer{
+		Key:                  500,
+		ProcessInstanceKey:   expectedInstance.ProcessInstanceKey,
+		ProcessDefinitionKey: expectedInstance.ProcessDefinitionKey,
+		ElementInstanceKey:   501,
+		TargetElementID:      "wait-a-day",
+		DueDate:              createdTime.Add(24 * time.Hour),
+		Repetitions:          -1,
+		State:                "CREATED",
+		Time:                 createdTime,
+		Position:             30,
+	}
+
+	ensureTimer := func(t *testing.T, expected Timer) {
+		var timer Timer
+		err := db.First(&timer).Error
+		assert.NoError(t, err)
+
+		timer.DueDate = timer.DueDate.UTC()
+		timer.Time = timer.Time.UTC()
+		assert.Equal(t, expected, timer)
+	}
+
+	t.Run("trigger missing timer", func(t *testing.T) {
+		err := storer.TimerUpdated(31, expectedTimer.Key, "TRIGGERED", createdTime)
+		assert.ErrorContains(t, err, "failed to find timer")
+		assert.True(t, IsNotFound(err))
+	})
+
+	for _, name := range []string{"create timer", "create again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.TimerCreated(
+				expectedTimer.Position,
+				expectedTimer.Key,
+				expectedTimer.ProcessInstanceKey,
+				expectedTimer.ProcessDefinitionKey,
+				expectedTimer.ElementInstanceKey,
+				expectedTimer.TargetElementID,
+				expectedTimer.DueDate,
+				expectedTimer.Repetitions,
+				expectedTimer.Time,
+			)
+			assert.NoError(t, err)
+			ensureTimer(t, expectedTimer)
+		})
+	}
+
+	triggeredTime := expectedTimer.DueDate
+	t.Run("trigger timer", func(t *testing.T) {
+		err := storer.TimerUpdated(31, expectedTimer.Key, "TRIGGERED", triggeredTime)
+		assert.NoError(t, err)
+
+		expected := expectedTimer
+		expected.State = "TRIGGERED"
+		expected.Time = triggeredTime
+		expected.Position = 31
+		ensureTimer(t, expected)
+	})
+
+	t.Run("update with older record", func(t *testing.T) {
+		err := storer.TimerUpdated(30, expectedTimer.Key, "CANCELED", createdTime)
+		assert.NoError(t, err)
+
+		expected := expectedTimer
+		expected.State = "TRIGGERED"
+		expected.Time = triggeredTime
+		expected.Position = 31
+		ensureTimer(t, expected)
+	})
+}
+
+func TestUserTaskLifecycle(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	createdTime := time.Unix(1701235500, 0).UTC()
+	expectedUserTask := UserTask{
+		Key:                  600,
+		ProcessInstanceKey:   expectedInstance.ProcessInstanceKey,
+		ProcessDefinitionKey: expectedInstance.ProcessDefinitionKey,
+		ElementInstanceKey:   601,
+		ElementID:            "approve",
+		Assignee:             "",
+		CandidateGroups:      `["accounting"]`,
+		CandidateUsers:       `[]`,
+		DueDate: sql.NullTime{
+			Time:  createdTime.Add(24 * time.Hour),
+			Valid: true,
+		},
+		FormKey:      602,
+		State:        "CREATED",
+		CreationTime: createdTime,
+		Time:         createdTime,
+		Position:     40,
+	}
+
+	ensureUserTask := func(t *testing.T, expected UserTask) {
+		var userTask UserTask
+		err := db.First(&userTask).Error
+		assert.NoError(t, err)
+
+		userTask.DueDate.Time = userTask.DueDate.Time.UTC()
+		userTask.EndTime.Time = userTask.EndTime.Time.UTC()
+		userTask.CreationTime = userTask.CreationTime.UTC()
+		userTask.Time = userTask.Time.UTC()
+		assert.Equal(t, expected, userTask)
+	}
+
+	t.Run("assign missing user task", func(t *testing.T) {
+		err := storer.UserTaskUpdated(41, expectedUserTask.Key, "demo", "[]", "[]",
+			sql.NullTime{}, sql.NullTime{}, createdTime)
+		assert.ErrorContains(t, err, "failed to find user task")
+		assert.True(t, IsNotFound(err))
+	})
+
+	for _, name := range []string{"create user task", "create again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.UserTaskCreated(
+				expectedUserTask.Position,
+				expectedUserTask.Key,
+				expectedUserTask.ProcessInstanceKey,
+				expectedUserTask.ProcessDefinitionKey,
+				expectedUserTask.ElementInstanceKey,
+				expectedUserTask.ElementID,
+				expectedUserTask.Assignee,
+				expectedUserTask.CandidateGroups,
+				expectedUserTask.CandidateUsers,
+				expectedUserTask.DueDate,
+				expectedUserTask.FollowUpDate,
+				expectedUserTask.FormKey,
+				expectedUserTask.Time,
+			)
+			assert.NoError(t, err)
+			ensureUserTask(t, expectedUserTask)
+		})
+	}
+
+	assignedTime := createdTime.Add(time.Minute)
+	expectedAssigned := expectedUserTask
+	expectedAssigned.Assignee = "demo"
+	expectedAssigned.Time = assignedTime
+	expectedAssigned.Position = 41
+	t.Run("assign user task", func(t *testing.T) {
+		err := storer.UserTaskUpdated(
+			41,
+			expectedUserTask.Key,
+			"demo",
+			expectedUserTask.CandidateGroups,
+			expectedUserTask.CandidateUsers,
+			expectedUserTask.DueDate,
+			expectedUserTask.FollowUpDate,
+			assignedTime,
+		)
+		assert.NoError(t, err)
+		ensureUserTask(t, expectedAssigned)
+	})
+
+	completedTime := createdTime.Add(time.Hour)
+	expectedCompleted := expectedAssigned
+	expectedCompleted.State = "COMPLETED"
+	expectedCompleted.EndTime = sql.NullTime{
+		Time:  completedTime,
+		Valid: true,
+	}
+	expectedCompleted.Time = completedTime
+	expectedCompleted.Position = 42
+	t.Run("complete user task", func(t *testing.T) {
+		err := storer.UserTaskEnded(42, expectedUserTask.Key, "COMPLETED", completedTime)
+		assert.NoError(t, err)
+		ensureUserTask(t, expectedCompleted)
+	})
+
+	t.Run("update with older record", func(t *testing.T) {
+		err := storer.UserTaskUpdated(41, expectedUserTask.Key, "other", "[]", "[]",
+			sql.NullTime{}, sql.NullTime{}, assignedTime)
+		assert.NoError(t, err)
+		ensureUserTask(t, expectedCompleted)
+	})
+}
+
+func TestSignals(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedSignal := Signal{
+		Key:       700,
+		Name:      "cancel-orders",
+		Variables: `{"reason":"recall"}`,
+		Time:      time.Unix(1701235500, 0).UTC(),
+		Position:  50,
+	}
+	expectedSubscription := SignalSubscription{
+		Key:                   701,
+		SignalName:            expectedSignal.Name,
+		ProcessDefinitionKey:  expectedProcess.ProcessDefinitionKey,
+		BpmnProcessID:         expectedProcess.BpmnProcessID,
+		CatchEventID:          "on-cancel",
+		CatchEventInstanceKey: -1,
+		State:                 "CREATED",
+		Time:                  time.Unix(1701235400, 0).UTC(),
+		Position:              45,
+	}
+
+	for _, name := range []string{"broadcast signal", "broadcast again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.SignalBroadcasted(
+				expectedSignal.Position,
+				expectedSignal.Key,
+				expectedSignal.Name,
+				expectedSignal.Variables,
+				expectedSignal.Time,
+			)
+			assert.NoError(t, err)
+
+			var signals []Signal
+			err = db.Find(&signals).Error
+			assert.NoError(t, err)
+			assert.Len(t, signals, 1)
+
+			signal := signals[0]
+			signal.Time = signal.Time.UTC()
+			assert.Equal(t, expectedSignal, signal)
+		})
+	}
+
+	ensureSubscription := func(t *testing.T, expected SignalSubscription) {
+		var subscription SignalSubscription
+		err := db.First(&subscription).Error
+		assert.NoError(t, err)
+
+		subscription.Time = subscription.Time.UTC()
+		assert.Equal(t, expected, subscription)
+	}
+
+	updateSubscription := func(position int64, state string, time time.Time) error {
+		return storer.SignalSubscriptionUpdated(
+			position,
+			expectedSubscription.Key,
+			expectedSubscription.SignalName,
+			expectedSubscription.ProcessDefinitionKey,
+			expectedSubscription.BpmnProcessID,
+			expectedSubscription.CatchEventID,
+			expectedSubscription.CatchEventInstanceKey,
+			state,
+			time,
+		)
+	}
+
+	t.Run("create subscription", func(t *testing.T) {
+		err := updateSubscription(expectedSubscription.Position, "CREATED", expectedSubscription.Time)
+		assert.NoError(t, err)
+		ensureSubscription(t, expectedSubscription)
+	})
+
+	deletedTime := expectedSubscription.Time.Add(time.Hour)
+	expectedDeleted := expectedSubscription
+	expectedDeleted.State = "DELETED"
+	expectedDeleted.Time = deletedTime
+	expectedDeleted.Position = 46
+	t.Run("delete subscription", func(t *testing.T) {
+		err := updateSubscription(46, "DELETED", deletedTime)
+		assert.NoError(t, err)
+		ensureSubscription(t, expectedDeleted)
+	})
+
+	t.Run("update with older record", func(t *testing.T) {
+		err := updateSubscription(expectedSubscription.Position, "CREATED", expectedSubscription.Time)
+		assert.NoError(t, err)
+		ensureSubscription(t, expectedDeleted)
+	})
+}
+
+func TestBrokerErrorOccurred(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	expectedBrokerError := BrokerError{
+		PartitionID:        1,
+		Position:           400,
+		ErrorEventPosition: 399,
+		ProcessInstanceKey: expectedInstance.ProcessInstanceKey,
+		ExceptionMessage:   "Expected to process record, but failed",
+		Stacktrace:         "java.lang.IllegalStateException: ...",
+		Time:               time.Unix(1701235499, 0).UTC(),
+	}
+
+	for _, name := range []string{"store error", "store error again"} {
+		t.Run(name, func(t *testing.T) {
+			err := storer.BrokerErrorOccurred(
+				expectedBrokerError.PartitionID,
+				expectedBrokerError.Position,
+				expectedBrokerError.ErrorEventPosition,
+				expectedBrokerError.ProcessInstanceKey,
+				expectedBrokerError.ExceptionMessage,
+				expectedBrokerError.Stacktrace,
+				expectedBrokerError.Time,
+			)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("ensure equal value", func(t *testing.T) {
+		var brokerErrors []BrokerError
+		err := db.Find(&brokerErrors).Error
+		assert.NoError(t, err)
+		assert.Len(t, brokerErrors, 1)
+
+		brokerError := brokerErrors[0]
+		brokerError.Time = brokerError.Time.UTC()
+		assert.Equal(t, expectedBrokerError, brokerError)
+	})
+
+	t.Run("same position in another partition", func(t *testing.T) {
+		err := storer.BrokerErrorOccurred(
+			2,
+			expectedBrokerError.Position,
+			expectedBrokerError.ErrorEventPosition,
+			expectedBrokerError.ProcessInstanceKey,
+			expectedBrokerError.ExceptionMessage,
+			expectedBrokerError.Stacktrace,
+			expectedBrokerError.Time,
+		)
+		assert.NoError(t, err)
+
+		var count int64
+		err = db.Model(&BrokerError{}).Where("position = ?", expectedBrokerError.Position).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	testDb := newMigratedTestDB(t)
+	defer func() {
+		assert.NoError(t, testDb.Rollback())
+	}()
+	db := testDb.DB()
+	storer := NewStorer(db)
+
+	errNested := errors.New("nested failure")
+
+	t.Run("nested failure is isolated", func(t *testing.T) {
+		err := storer.Transaction(func(tx Storer) error {
+			err := tx.Transaction(func(nested Storer) error {
+				return nested.VariableCreated(
+					expectedVariable.Position,
+					expectedVariable.ProcessInstanceKey,
+					expectedVariable.ScopeKey,
+					expectedVariable.Name,
+					expectedVariable.Value,
+					expectedVariable.Time,
+				)
+			})
+			assert.NoError(t, err)
+
+			err = tx.Transaction(func(nested Storer) error {
+				err := nested.AuditLogEventOccurred(
+					expectedAuditLog.Position,
+					expectedAuditLog.ProcessInstanceKey,
+					expectedAuditLog.ElementID,
+					expectedAuditLog.ElementType,
+					expectedAuditLog.Intent,
+					expectedAuditLog.Time,
+				)
+				assert.NoError(t, err)
+
+				err = nested.IncidentCreated(
+					expectedIncident.Position,
+					expectedIncident.Key,
+					expectedIncident.ProcessInstanceKey,
+					expectedIncident.ElementID,
+					expectedIncident.ErrorType,
+					expectedIncident.ErrorMessage,
+					expectedIncident.Time,
+				)
+				assert.NoError(t, err)
+
+				return errNested
+			})
+			assert.ErrorIs(t, err, errNested)
+
+			return tx.OffsetCommitted("zeebe", 0, 10)
+		})
+		assert.NoError(t, err)
+
+		var variables int64
+		assert.NoError(t, db.Model(&Variable{}).Count(&variables).Error)
+		assert.Equal(t, int64(1), variables)
+
+		var incidents int64
+		assert.NoError(t, db.Model(&Incident{}).Count(&incidents).Error)
+		assert.Zero(t, incidents)
+
+		var auditLogs int64
+		assert.NoError(t, db.Model(&AuditLog{}).Count(&auditLogs).Error)
+		assert.Zero(t, auditLogs)
+
+		offset, ok, err := storer.LastCommittedOffset("zeebe", 0)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(10), offset)
+	})
+
+	t.Run("buffered writes are written in bulk", func(t *testing.T) {
+		err := storer.Transaction(func(tx Storer) error {
+			for i := int64(0); i < BulkInsertSize+1; i++ {
+				err := tx.AuditLogEventOccurred(
+					expectedAuditLog.Position+i,
+					expectedAuditLog.ProcessInstanceKey,
+					expectedAuditLog.ElementID,
+					expectedAuditLog.ElementType,
+					expectedAuditLog.Intent,
+					expectedAuditLog.Time,
+				)
+				assert.NoError(t, err)
+
+				err = tx.OffsetCommitted("zeebe", 0, 11+i)
+				assert.NoError(t, err)
+			}
+
+			// Buffered writes aren't visible until the transaction
+			// commits
+			offset, _, err := tx.LastCommittedOffset("zeebe", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(10), offset)
+
+			return nil
+		})
+		assert.NoError(t, err)
+
+		var auditLogs int64
+		assert.NoError(t, db.Model(&AuditLog{}).Count(&auditLogs).Error)
+		assert.Equal(t, int64(BulkInsertSize+1), auditLogs)
+
+		offset, ok, err := storer.LastCommittedOffset("zeebe", 0)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, int64(11+BulkInsertSize), offset)
+	})
+
+	t.Run("failure rolls back everything", func(t *testing.T) {
+		err := storer.Transaction(func(tx Storer) error {
+			err := tx.OffsetCommitted("zeebe", 0, 5000)
+			assert.NoError(t, err)
+
+			return errNested
+		})
+		assert.ErrorIs(t, err, errNested)
+
+		offset, _, err := storer.LastCommittedOffset("zeebe", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11+BulkInsertSize), offset)
 	})
 }
@@ -12,8 +12,31 @@ var TableMigrations = []any{
 	&AuditLog{},
 	&Incident{},
 	&Job{},
+	&JobEvent{},
+	&JobBatch{},
 	&Variable{},
+	&VariableChange{},
 	&BpmnResource{},
+	&Deployment{},
+	&DeploymentResource{},
+	&DeployedDefinition{},
+	&DecisionRequirements{},
+	&DmnResource{},
+	&Decision{},
+	&DecisionEvaluation{},
+	&EvaluatedDecision{},
+	&KafkaOffset{},
+	&DeadLetter{},
+	&PendingRecord{},
+	&Rejection{},
+	&Message{},
+	&MessageSubscription{},
+	&Timer{},
+	&UserTask{},
+	&Signal{},
+	&SignalSubscription{},
+	&BrokerError{},
+	&ElementInstance{},
 }
 
 // Interface for models that have a table name. Implementing this interface
@@ -31,10 +54,17 @@ type Instance struct {
 	Status               string    `gorm:"not null"`
 	StartTime            time.Time `gorm:"not null"`
 	EndTime              sql.NullTime
-	AuditLogs            []AuditLog `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
-	Incidents            []Incident `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
-	Jobs                 []Job      `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
-	Variables            []Variable `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	// Instance and call activity that started this instance. Zeebe uses -1
+	// for instances that weren't started by a call activity.
+	ParentProcessInstanceKey int64             `gorm:"not null;default:0;index"`
+	ParentElementInstanceKey int64             `gorm:"not null;default:0"`
+	AuditLogs                []AuditLog        `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	Incidents                []Incident        `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	Jobs                     []Job             `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	Variables                []Variable        `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	ElementInstances         []ElementInstance `gorm:"foreignKey:ProcessInstanceKey;references:ProcessInstanceKey"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
 }
 
 func (Instance) TableName() string {
@@ -49,6 +79,8 @@ type Process struct {
 	DeploymentTime       time.Time    `gorm:"not null"`
 	BpmnResource         BpmnResource `gorm:"foreignKey:ProcessDefinitionKey;references:ProcessDefinitionKey"`
 	Instances            []Instance   `gorm:"foreignKey:ProcessDefinitionKey;references:ProcessDefinitionKey"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
 }
 
 func (Process) TableName() string {
@@ -76,6 +108,8 @@ type Incident struct {
 	ErrorMessage       string    `gorm:"not null"`
 	State              string    `gorm:"not null"`
 	Time               time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
 }
 
 func (Incident) TableName() string {
@@ -84,32 +118,123 @@ func (Incident) TableName() string {
 
 // Job model struct for the 'jobs' database table.
 type Job struct {
-	Key                int64     `gorm:"primarykey"`
-	ElementID          string    `gorm:"not null"`
-	ProcessInstanceKey int64     `gorm:"not null"`
-	Type               string    `gorm:"not null"`
-	Retries            int64     `gorm:"not null"`
-	Worker             string    `gorm:"not null"`
-	State              string    `gorm:"not null"`
-	Time               time.Time `gorm:"not null"`
+	Key                int64  `gorm:"primarykey"`
+	ElementID          string `gorm:"not null"`
+	ElementInstanceKey int64  `gorm:"not null;default:0"`
+	ProcessInstanceKey int64  `gorm:"not null"`
+	Type               string `gorm:"not null"`
+	Retries            int64  `gorm:"not null"`
+	Worker             string `gorm:"not null"`
+	State              string `gorm:"not null"`
+	ErrorMessage       string `gorm:"not null;default:''"`
+	ErrorCode          string `gorm:"not null;default:''"`
+	// When the worker that activated the job has to complete it by.
+	Deadline sql.NullTime
+	// How long to wait before retrying a failed job, in milliseconds.
+	RetryBackoff int64 `gorm:"not null;default:0"`
+	// Custom headers of the job as a JSON object.
+	CustomHeaders string    `gorm:"not null;default:'{}'"`
+	Time          time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
 }
 
 func (Job) TableName() string {
 	return "jobs"
 }
 
+// JobEvent model struct for the 'job_events' database table.
+//
+// Each row is a step in the lifecycle of a job, e.g. it being activated by a
+// worker or failing. Rows are only ever added, so the table holds the whole
+// history of every job.
+type JobEvent struct {
+	// Partition and position of the job record. Positions are only unique
+	// within a partition.
+	PartitionID        int64  `gorm:"primarykey;autoIncrement:false"`
+	Position           int64  `gorm:"primarykey;autoIncrement:false"`
+	JobKey             int64  `gorm:"not null;index"`
+	ProcessInstanceKey int64  `gorm:"not null;index"`
+	Intent             string `gorm:"not null"`
+	// Retries left after the event.
+	Retries      int64     `gorm:"not null"`
+	Worker       string    `gorm:"not null"`
+	ErrorMessage string    `gorm:"not null"`
+	ErrorCode    string    `gorm:"not null"`
+	RetryBackoff int64     `gorm:"not null"`
+	Time         time.Time `gorm:"not null"`
+}
+
+func (JobEvent) TableName() string {
+	return "job_events"
+}
+
+// JobBatch model struct for the 'job_batches' database table.
+//
+// Each row is a worker asking for jobs of a type to work on (ACTIVATE) or
+// being handed jobs (ACTIVATED). Rows are only ever added.
+type JobBatch struct {
+	// Partition and position of the job batch record. Positions are only
+	// unique within a partition.
+	PartitionID int64  `gorm:"primarykey;autoIncrement:false"`
+	Position    int64  `gorm:"primarykey;autoIncrement:false"`
+	Worker      string `gorm:"not null;index"`
+	Type        string `gorm:"not null"`
+	Intent      string `gorm:"not null"`
+	// How long the worker has to complete the jobs, in milliseconds.
+	Timeout           int64 `gorm:"not null"`
+	MaxJobsToActivate int64 `gorm:"not null"`
+	// Number of jobs handed to the worker.
+	JobCount  int64     `gorm:"not null"`
+	Truncated bool      `gorm:"not null"`
+	Time      time.Time `gorm:"not null"`
+}
+
+func (JobBatch) TableName() string {
+	return "job_batches"
+}
+
 // Variable model struct for the 'variables' database table.
+//
+// Variables are identified by the element instance whose scope they're in
+// and their name. Global variables are in the scope of the process itself,
+// so their scope key is the process instance key.
 type Variable struct {
-	ProcessInstanceKey int64     `gorm:"primarykey;autoIncrement:false"`
+	ScopeKey           int64     `gorm:"primarykey;autoIncrement:false"`
 	Name               string    `gorm:"primarykey"`
+	ProcessInstanceKey int64     `gorm:"not null;index"`
 	Value              string    `gorm:"not null"`
 	Time               time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
 }
 
 func (Variable) TableName() string {
 	return "variables"
 }
 
+// VariableChange model struct for the 'variable_changes' database table.
+//
+// Each row is a value a variable was created or updated with. Rows are only
+// ever added, so the table holds the whole history of every variable.
+type VariableChange struct {
+	// Partition and position of the variable record. Positions are only
+	// unique within a partition.
+	PartitionID        int64  `gorm:"primarykey;autoIncrement:false"`
+	Position           int64  `gorm:"primarykey;autoIncrement:false"`
+	ProcessInstanceKey int64  `gorm:"not null;index"`
+	ScopeKey           int64  `gorm:"not null;index:idx_variable_changes_variable"`
+	Name               string `gorm:"not null;index:idx_variable_changes_variable"`
+	// CREATED or UPDATED.
+	Intent string    `gorm:"not null"`
+	Value  string    `gorm:"not null"`
+	Time   time.Time `gorm:"not null"`
+}
+
+func (VariableChange) TableName() string {
+	return "variable_changes"
+}
+
 // BpmnResource model struct for the 'bpmn_resources' database table.
 //
 // This table is used to store the BPMN XML files which are relatively large
@@ -123,3 +248,438 @@ type BpmnResource struct {
 func (BpmnResource) TableName() string {
 	return "bpmn_resources"
 }
+
+// Deployment model struct for the 'deployments' database table.
+//
+// Each row is a set of resources deployed together. The definitions they
+// contain are stored on their own as well, e.g. in the 'processes' table.
+type Deployment struct {
+	DeploymentKey int64                `gorm:"primarykey;autoIncrement:false"`
+	Time          time.Time            `gorm:"not null"`
+	Resources     []DeploymentResource `gorm:"foreignKey:DeploymentKey;references:DeploymentKey"`
+	Definitions   []DeployedDefinition `gorm:"foreignKey:DeploymentKey;references:DeploymentKey"`
+	Position      int64                `gorm:"not null"`
+}
+
+func (Deployment) TableName() string {
+	return "deployments"
+}
+
+// DeploymentResource model struct for the 'deployment_resources' database
+// table.
+type DeploymentResource struct {
+	DeploymentKey int64  `gorm:"primarykey;autoIncrement:false"`
+	ResourceName  string `gorm:"primarykey"`
+	// BPMN, DMN, FORM or OTHER.
+	ResourceType string `gorm:"not null"`
+	// Size of the resource in bytes.
+	Size int64 `gorm:"not null"`
+}
+
+func (DeploymentResource) TableName() string {
+	return "deployment_resources"
+}
+
+// DeployedDefinition model struct for the 'deployed_definitions' database
+// table.
+//
+// Each row is a definition found in the resources of a deployment. Resources
+// that didn't change since they were last deployed don't create new versions,
+// their definitions are marked as duplicates instead.
+type DeployedDefinition struct {
+	DeploymentKey int64 `gorm:"primarykey;autoIncrement:false"`
+	// Key of the process, decision requirements, decision or form.
+	DefinitionKey int64 `gorm:"primarykey;autoIncrement:false"`
+	// PROCESS, DECISION_REQUIREMENTS, DECISION or FORM.
+	Kind         string `gorm:"not null"`
+	DefinitionID string `gorm:"not null"`
+	Version      int64  `gorm:"not null"`
+	ResourceName string `gorm:"not null"`
+	Duplicate    bool   `gorm:"not null"`
+}
+
+func (DeployedDefinition) TableName() string {
+	return "deployed_definitions"
+}
+
+// DecisionRequirements model struct for the 'decision_requirements' database
+// table.
+//
+// Each row is a deployed version of a decision requirements graph (DRG), i.e.
+// a DMN file, which contains one or more decisions.
+type DecisionRequirements struct {
+	DecisionRequirementsKey int64       `gorm:"primarykey;autoIncrement:false"`
+	DecisionRequirementsID  string      `gorm:"not null"`
+	Name                    string      `gorm:"not null"`
+	Version                 int64       `gorm:"not null"`
+	Namespace               string      `gorm:"not null"`
+	ResourceName            string      `gorm:"not null"`
+	DeploymentTime          time.Time   `gorm:"not null"`
+	DmnResource             DmnResource `gorm:"foreignKey:DecisionRequirementsKey;references:DecisionRequirementsKey"`
+	Decisions               []Decision  `gorm:"foreignKey:DecisionRequirementsKey;references:DecisionRequirementsKey"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (DecisionRequirements) TableName() string {
+	return "decision_requirements"
+}
+
+// DmnResource model struct for the 'dmn_resources' database table.
+//
+// Like BPMN files, DMN files are stored in a table of their own as a base64
+// encoded string.
+type DmnResource struct {
+	DecisionRequirementsKey int64 `gorm:"primarykey;autoIncrement:false"`
+	// A base64 encoded string of the DMN XML file.
+	DmnFile string `gorm:"not null"`
+}
+
+func (DmnResource) TableName() string {
+	return "dmn_resources"
+}
+
+// Decision model struct for the 'decisions' database table.
+type Decision struct {
+	DecisionKey             int64     `gorm:"primarykey;autoIncrement:false"`
+	DecisionID              string    `gorm:"not null"`
+	Name                    string    `gorm:"not null"`
+	Version                 int64     `gorm:"not null"`
+	DecisionRequirementsKey int64     `gorm:"not null;index"`
+	DecisionRequirementsID  string    `gorm:"not null"`
+	DeploymentTime          time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (Decision) TableName() string {
+	return "decisions"
+}
+
+// DecisionEvaluation model struct for the 'decision_evaluations' database
+// table.
+//
+// Each row is an evaluation of a decision, usually by a business rule task of
+// a process instance. Evaluating a decision evaluates the decisions it
+// requires too, which are stored in the 'evaluated_decisions' table.
+type DecisionEvaluation struct {
+	Key                     int64  `gorm:"primarykey;autoIncrement:false"`
+	DecisionKey             int64  `gorm:"not null;index"`
+	DecisionID              string `gorm:"not null"`
+	DecisionName            string `gorm:"not null"`
+	DecisionVersion         int64  `gorm:"not null"`
+	DecisionRequirementsKey int64  `gorm:"not null"`
+	// Output of the decision as JSON.
+	Output               string `gorm:"not null"`
+	ProcessInstanceKey   int64  `gorm:"not null;index"`
+	ProcessDefinitionKey int64  `gorm:"not null"`
+	ElementInstanceKey   int64  `gorm:"not null"`
+	ElementID            string `gorm:"not null"`
+	// EVALUATED or FAILED.
+	State            string              `gorm:"not null"`
+	FailureMessage   string              `gorm:"not null"`
+	FailedDecisionID string              `gorm:"not null"`
+	Time             time.Time           `gorm:"not null"`
+	Decisions        []EvaluatedDecision `gorm:"foreignKey:EvaluationKey;references:Key"`
+}
+
+func (DecisionEvaluation) TableName() string {
+	return "decision_evaluations"
+}
+
+// EvaluatedDecision model struct for the 'evaluated_decisions' database table.
+//
+// Each row is a decision evaluated as part of a decision evaluation, in the
+// order they were evaluated in.
+type EvaluatedDecision struct {
+	EvaluationKey int64 `gorm:"primarykey;autoIncrement:false"`
+	// Order the decision was evaluated in, starting from zero.
+	Index           int64  `gorm:"column:evaluation_index;primarykey;autoIncrement:false"`
+	DecisionKey     int64  `gorm:"not null"`
+	DecisionID      string `gorm:"not null"`
+	DecisionName    string `gorm:"not null"`
+	DecisionVersion int64  `gorm:"not null"`
+	// E.g. DECISION_TABLE or LITERAL_EXPRESSION.
+	DecisionType string `gorm:"not null"`
+	// Output of the decision as JSON.
+	Output string `gorm:"not null"`
+	// Inputs of a decision table as a JSON array of objects with the input
+	// ID, name and value.
+	Inputs string `gorm:"not null"`
+	// Rules of a decision table that matched as a JSON array of objects
+	// with the rule ID, index and evaluated outputs.
+	MatchedRules string `gorm:"not null"`
+}
+
+func (EvaluatedDecision) TableName() string {
+	return "evaluated_decisions"
+}
+
+// KafkaOffset model struct for the 'kafka_offsets' database table.
+//
+// Each row holds the offset of the last record applied from a topic
+// partition, so that consuming can resume from where it left off after a
+// restart. Imported files are tracked here too, under topic names starting
+// with "file:", which no Kafka topic can have.
+type KafkaOffset struct {
+	Topic     string `gorm:"primarykey"`
+	Partition int32  `gorm:"primarykey;autoIncrement:false"`
+	Offset    int64  `gorm:"not null"`
+}
+
+func (KafkaOffset) TableName() string {
+	return "kafka_offsets"
+}
+
+// DeadLetter model struct for the 'dead_letters' database table.
+//
+// Records that couldn't be parsed or applied are kept here with their raw
+// payload so that they can be retried or discarded later. Each record is
+// identified by where it was read from.
+type DeadLetter struct {
+	ID        int64     `gorm:"primarykey"`
+	Topic     string    `gorm:"not null;uniqueIndex:idx_dead_letters_origin"`
+	Partition int32     `gorm:"not null;uniqueIndex:idx_dead_letters_origin"`
+	Offset    int64     `gorm:"not null;uniqueIndex:idx_dead_letters_origin"`
+	Payload   []byte    `gorm:"not null"`
+	Error     string    `gorm:"not null"`
+	Attempts  int64     `gorm:"not null"`
+	Time      time.Time `gorm:"not null"`
+}
+
+func (DeadLetter) TableName() string {
+	return "dead_letters"
+}
+
+// PendingRecord model struct for the 'pending_records' database table.
+//
+// Records that update a row which still hadn't been created after waiting
+// for it are kept here. They're applied once a record creating the row with
+// `MissingKey` arrives.
+type PendingRecord struct {
+	ID         int64     `gorm:"primarykey"`
+	Topic      string    `gorm:"not null;uniqueIndex:idx_pending_records_origin"`
+	Partition  int32     `gorm:"not null;uniqueIndex:idx_pending_records_origin"`
+	Offset     int64     `gorm:"not null;uniqueIndex:idx_pending_records_origin"`
+	Payload    []byte    `gorm:"not null"`
+	MissingKey int64     `gorm:"not null;index"`
+	Time       time.Time `gorm:"not null"`
+}
+
+func (PendingRecord) TableName() string {
+	return "pending_records"
+}
+
+// Rejection model struct for the 'rejections' database table.
+//
+// Each row is a command Zeebe rejected, e.g. completing a job that no longer
+// exists. Rejections are linked to the process instance and element of the
+// command where its value has them, otherwise those are left zero.
+type Rejection struct {
+	// Partition and position of the rejection record. Positions are only
+	// unique within a partition.
+	PartitionID int64 `gorm:"primarykey;autoIncrement:false"`
+	Position    int64 `gorm:"primarykey;autoIncrement:false"`
+	// Key of the entity the rejected command was for.
+	Key                int64     `gorm:"not null"`
+	ProcessInstanceKey int64     `gorm:"not null;index"`
+	ElementID          string    `gorm:"not null"`
+	ValueType          string    `gorm:"not null"`
+	Intent             string    `gorm:"not null"`
+	RejectionType      string    `gorm:"not null;index"`
+	RejectionReason    string    `gorm:"not null"`
+	Time               time.Time `gorm:"not null"`
+}
+
+func (Rejection) TableName() string {
+	return "rejections"
+}
+
+// Message model struct for the 'messages' database table.
+//
+// Each row is a message published to Zeebe. The state is the last step of its
+// lifecycle: PUBLISHED, CORRELATED or EXPIRED.
+type Message struct {
+	Key            int64  `gorm:"primarykey;autoIncrement:false"`
+	Name           string `gorm:"not null;index"`
+	CorrelationKey string `gorm:"not null"`
+	MessageID      string `gorm:"not null"`
+	// Time to live in milliseconds.
+	TimeToLive int64 `gorm:"not null"`
+	// Variables of the message as a JSON object.
+	Variables   string    `gorm:"not null"`
+	State       string    `gorm:"not null"`
+	PublishTime time.Time `gorm:"not null"`
+	// Time of the last state change.
+	Time time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (Message) TableName() string {
+	return "messages"
+}
+
+// MessageSubscription model struct for the 'message_subscriptions' database
+// table.
+//
+// Each row is an element instance waiting for a message with a name and
+// correlation key. The state is the intent of the last subscription record,
+// e.g. CREATED, CORRELATED or DELETED.
+type MessageSubscription struct {
+	ElementInstanceKey int64  `gorm:"primarykey;autoIncrement:false"`
+	MessageName        string `gorm:"primarykey"`
+	ProcessInstanceKey int64  `gorm:"not null;index"`
+	ElementID          string `gorm:"not null"`
+	CorrelationKey     string `gorm:"not null"`
+	// Key of the message correlated to the subscription, zero if none has
+	// been yet.
+	MessageKey   int64     `gorm:"not null;index"`
+	Interrupting bool      `gorm:"not null"`
+	State        string    `gorm:"not null"`
+	Time         time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (MessageSubscription) TableName() string {
+	return "message_subscriptions"
+}
+
+// Timer model struct for the 'timers' database table.
+//
+// The state is CREATED until the timer is TRIGGERED or CANCELED. Timers of
+// timer start events belong to a process but no instance, so their instance
+// key is -1.
+type Timer struct {
+	Key                  int64     `gorm:"primarykey;autoIncrement:false"`
+	ProcessInstanceKey   int64     `gorm:"not null;index"`
+	ProcessDefinitionKey int64     `gorm:"not null;index"`
+	ElementInstanceKey   int64     `gorm:"not null"`
+	TargetElementID      string    `gorm:"not null"`
+	DueDate              time.Time `gorm:"not null;index"`
+	// Remaining repetitions of a cycle timer, -1 if it repeats forever.
+	Repetitions int64     `gorm:"not null"`
+	State       string    `gorm:"not null"`
+	Time        time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (Timer) TableName() string {
+	return "timers"
+}
+
+// UserTask model struct for the 'user_tasks' database table.
+//
+// Only user tasks managed by Zeebe itself have records of their own, user
+// tasks handled by job workers are stored as jobs instead.
+type UserTask struct {
+	Key                  int64  `gorm:"primarykey;autoIncrement:false"`
+	ProcessInstanceKey   int64  `gorm:"not null;index"`
+	ProcessDefinitionKey int64  `gorm:"not null;index"`
+	ElementInstanceKey   int64  `gorm:"not null"`
+	ElementID            string `gorm:"not null"`
+	Assignee             string `gorm:"not null;index"`
+	// Candidate groups and users as JSON arrays.
+	CandidateGroups string       `gorm:"not null"`
+	CandidateUsers  string       `gorm:"not null"`
+	DueDate         sql.NullTime `gorm:"index"`
+	FollowUpDate    sql.NullTime
+	FormKey         int64 `gorm:"not null"`
+	// CREATED, COMPLETED or CANCELED.
+	State        string    `gorm:"not null"`
+	CreationTime time.Time `gorm:"not null"`
+	EndTime      sql.NullTime
+	// When the user task was last changed.
+	Time time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (UserTask) TableName() string {
+	return "user_tasks"
+}
+
+// Signal model struct for the 'signals' database table.
+type Signal struct {
+	Key  int64  `gorm:"primarykey;autoIncrement:false"`
+	Name string `gorm:"not null;index"`
+	// Variables of the signal as a JSON object.
+	Variables string    `gorm:"not null"`
+	Time      time.Time `gorm:"not null"`
+	Position  int64     `gorm:"not null"`
+}
+
+func (Signal) TableName() string {
+	return "signals"
+}
+
+// SignalSubscription model struct for the 'signal_subscriptions' database
+// table.
+//
+// Each row is a signal catch event waiting for a signal, either a start event
+// of a process or an event of an element instance.
+type SignalSubscription struct {
+	Key                  int64  `gorm:"primarykey;autoIncrement:false"`
+	SignalName           string `gorm:"not null;index"`
+	ProcessDefinitionKey int64  `gorm:"not null;index"`
+	BpmnProcessID        string `gorm:"not null"`
+	CatchEventID         string `gorm:"not null"`
+	// Zero or less for signal start events.
+	CatchEventInstanceKey int64 `gorm:"not null"`
+	// CREATED or DELETED.
+	State string    `gorm:"not null"`
+	Time  time.Time `gorm:"not null"`
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (SignalSubscription) TableName() string {
+	return "signal_subscriptions"
+}
+
+// BrokerError model struct for the 'broker_errors' database table.
+//
+// Each row is an error the broker ran into while processing a record, e.g.
+// an exception thrown by an exporter. The instance the failed record belongs
+// to can't make progress until the error is dealt with.
+type BrokerError struct {
+	// Partition and position of the error record. Positions are only unique
+	// within a partition.
+	PartitionID int64 `gorm:"primarykey;autoIncrement:false"`
+	Position    int64 `gorm:"primarykey;autoIncrement:false"`
+	// Position of the record whose processing failed, in the same partition.
+	ErrorEventPosition int64     `gorm:"not null;index"`
+	ProcessInstanceKey int64     `gorm:"not null;index"`
+	ExceptionMessage   string    `gorm:"not null"`
+	Stacktrace         string    `gorm:"not null"`
+	Time               time.Time `gorm:"not null"`
+}
+
+func (BrokerError) TableName() string {
+	return "broker_errors"
+}
+
+// ElementInstance model struct for the 'element_instances' database table.
+//
+// Each row is one activation of a BPMN element in a process instance,
+// including the process itself. The state is the last lifecycle step of the
+// element, e.g. ELEMENT_ACTIVATED or ELEMENT_COMPLETED.
+type ElementInstance struct {
+	Key                int64  `gorm:"primarykey;autoIncrement:false"`
+	ProcessInstanceKey int64  `gorm:"not null;index"`
+	ElementID          string `gorm:"not null"`
+	ElementType        string `gorm:"not null"`
+	// Key of the element instance containing this one, -1 for the process.
+	FlowScopeKey int64     `gorm:"not null"`
+	State        string    `gorm:"not null"`
+	StartTime    time.Time `gorm:"not null"`
+	EndTime      sql.NullTime
+	// Position of the record that last changed the row.
+	Position int64 `gorm:"not null;default:0"`
+}
+
+func (ElementInstance) TableName() string {
+	return "element_instances"
+}